@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -14,6 +16,8 @@ import (
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/database"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/database/migration"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment/loader"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/server"
@@ -33,11 +37,27 @@ func main() {
 
 	loader := loader.NewLoader()
 
+	args := os.Args[1:]
+
+	local := len(args) > 0 && args[0] == "local"
+	if local {
+		slog.Info("loading environment from .env file")
+		args = args[1:]
+	}
+
+	// the migrations load only the environment of the database, the settings of the server are not required
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := migrate(ctx, loader, local, args[1:]); err != nil {
+			slog.ErrorContext(ctx, "error running migrations", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var config *environment.Config
 	var err error
 
-	if len(os.Args) > 1 && os.Args[1] == "local" {
-		slog.Info("loading environment from .env file")
+	if local {
 		config, err = loader.GetEnvironmentFromFile(ctx, ".env")
 	} else {
 		config, err = loader.GetEnvironment(ctx)
@@ -50,31 +70,31 @@ func main() {
 
 	logger.SetupLog(config)
 
-	cloudConfig, err := awsConfig.LoadDefaultConfig(ctx)
+	cloudConfig, err := loadCloudConfig(ctx, &config.CloudConfig.CloudEndpointConfig)
 	if err != nil {
 		slog.ErrorContext(ctx, "error loading aws config", "error", err)
 		panic(err)
 	}
 
-	if config.CloudConfig.IsBaseEndpointSet() {
-		cloudConfig.BaseEndpoint = aws.String(config.CloudConfig.BaseEndpoint)
-	}
-
-	secret := cloud.NewSecretService(cloudConfig)
-
-	dbUrl, err := secret.GetSecret(ctx, config.DbConfig.UrlSecretName)
-	if err != nil {
-		slog.ErrorContext(ctx, "error getting secret", "secret_name", config.DbConfig.UrlSecretName, "error", err)
+	if err := loadDatabaseUrl(ctx, cloudConfig, &config.CloudConfig.CloudEndpointConfig, config.DbConfig); err != nil {
 		panic(err)
 	}
-	if dbUrl == "" {
-		slog.ErrorContext(ctx, "secret is empty", "secret_name", config.DbConfig.UrlSecretName, "error", err)
-	}
-
-	config.DbConfig.Url = dbUrl
 
 	server := server.NewServer(config)
 
+	if config.DbConfig.MigrateOnStartup {
+		migrator, err := migration.NewMigrator(server.DatabaseService.GetInstance())
+		if err != nil {
+			slog.ErrorContext(ctx, "error loading migrations", "error", err)
+			panic(err)
+		}
+
+		if _, err := migrator.Up(ctx); err != nil {
+			slog.ErrorContext(ctx, "error applying migrations", "error", err)
+			panic(err)
+		}
+	}
+
 	if err := server.QueueService.UpdateQueueUrl(ctx); err != nil {
 		slog.ErrorContext(ctx, "error updating queue url", "error", err)
 		panic(err)
@@ -90,11 +110,10 @@ func main() {
 		panic(err)
 	}
 
-	go func(ctx context.Context) {
-		for {
-			server.QueueService.ConsumeMessages(ctx)
-		}
-	}(ctx)
+	server.QueueService.Start(ctx)
+
+	server.OutboxRelay.Start(ctx)
+	server.PaymentExpiration.Start(ctx)
 
 	httpServer := server.GetHttpServer()
 
@@ -117,5 +136,136 @@ func main() {
 	if err := httpServer.Shutdown(ctx); err != nil {
 		slog.ErrorContext(ctx, "error while trying to shutdown the server", "error", err)
 	}
+
+	if err := server.QueueService.Stop(ctx); err != nil {
+		slog.ErrorContext(ctx, "error while trying to stop the queue consumer", "error", err)
+	}
+
+	if err := server.PaymentExpiration.Stop(ctx); err != nil {
+		slog.ErrorContext(ctx, "error while trying to stop the payment expiration", "error", err)
+	}
+
+	if err := server.OutboxRelay.Stop(ctx); err != nil {
+		slog.ErrorContext(ctx, "error while trying to stop the outbox relay", "error", err)
+	}
+
+	// flushes the spans still buffered by the exporter
+	if err := server.TracerProvider.Shutdown(ctx); err != nil {
+		slog.ErrorContext(ctx, "error while trying to shutdown the tracer provider", "error", err)
+	}
 	slog.Info("graceful shutdown completed ✅")
 }
+
+func loadCloudConfig(ctx context.Context, endpoint *environment.CloudEndpointConfig) (aws.Config, error) {
+	cloudConfig, err := awsConfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	if endpoint.IsBaseEndpointSet() {
+		cloudConfig.BaseEndpoint = aws.String(endpoint.BaseEndpoint)
+	}
+
+	return cloudConfig, nil
+}
+
+// loadDatabaseUrl reads the database url from its secret, the in-memory cloud has
+// no secrets manager, so the database url is read from the environment
+func loadDatabaseUrl(ctx context.Context, cloudConfig aws.Config, endpoint *environment.CloudEndpointConfig, dbConfig *environment.DatabaseConfig) error {
+	if endpoint.IsMemoryDriver() {
+		slog.Info("using the in-memory cloud services")
+		return nil
+	}
+
+	secret := cloud.NewSecretService(cloudConfig)
+
+	dbUrl, err := secret.GetSecret(ctx, dbConfig.UrlSecretName)
+	if err != nil {
+		slog.ErrorContext(ctx, "error getting secret", "secret_name", dbConfig.UrlSecretName, "error", err)
+		return err
+	}
+	if dbUrl == "" {
+		slog.ErrorContext(ctx, "secret is empty", "secret_name", dbConfig.UrlSecretName, "error", err)
+	}
+
+	dbConfig.Url = dbUrl
+
+	return nil
+}
+
+// migrate handles "migrate up", "migrate down [steps]" and "migrate version"
+func migrate(ctx context.Context, loader *loader.Loader, local bool, args []string) error {
+	var config *environment.MigrationConfig
+	var err error
+
+	if local {
+		config, err = loader.GetMigrationEnvironmentFromFile(ctx, ".env")
+	} else {
+		config, err = loader.GetMigrationEnvironment(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error loading environment: %w", err)
+	}
+
+	logger.SetupLog(&environment.Config{
+		ApiConfig: config.ApiConfig,
+	})
+
+	cloudConfig, err := loadCloudConfig(ctx, config.CloudConfig)
+	if err != nil {
+		return fmt.Errorf("error loading aws config: %w", err)
+	}
+
+	if err := loadDatabaseUrl(ctx, cloudConfig, config.CloudConfig, config.DbConfig); err != nil {
+		return err
+	}
+
+	db := database.NewDatabase(&environment.Config{
+		DbConfig: config.DbConfig,
+	}).GetInstance()
+	defer db.Close()
+
+	migrator, err := migration.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "migrations applied", "count", count, "latest", migrator.Latest())
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+		}
+
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "migrations reverted", "count", count)
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "schema version", "version", version, "latest", migrator.Latest())
+	default:
+		return fmt.Errorf("unknown migrate command: %s", command)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 )
 
 type AwsDbUrlSecretService struct {
@@ -29,3 +30,27 @@ func (s *AwsDbUrlSecretService) GetSecret(ctx context.Context, secretName string
 
 	return *output.SecretString, nil
 }
+
+type SecretHealthCheck struct {
+	SecretName string
+	Client     *secretsmanager.Client
+}
+
+// NewSecretHealthCheck checks the secret can be described, without reading its value
+func NewSecretHealthCheck(secretName string, config aws.Config) health.HealthCheck {
+	return &SecretHealthCheck{
+		SecretName: secretName,
+		Client:     secretsmanager.NewFromConfig(config),
+	}
+}
+
+func (c *SecretHealthCheck) Health(ctx context.Context) *health.HealthStatus {
+	_, err := c.Client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(c.SecretName),
+	})
+	if err != nil {
+		return health.Unhealthy(err)
+	}
+
+	return health.Healthy()
+}
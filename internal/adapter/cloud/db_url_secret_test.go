@@ -90,3 +90,51 @@ func TestGetSecret(t *testing.T) {
 		testtools.ExitTest(stubber, t)
 	})
 }
+
+func TestSecretHealthCheck(t *testing.T) {
+	t.Run("Should return healthy when secret is described", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DescribeSecret",
+			Input: &secretsmanager.DescribeSecretInput{
+				SecretId: aws.String("my-secret"),
+			},
+			Output: &secretsmanager.DescribeSecretOutput{},
+		})
+
+		check := NewSecretHealthCheck("my-secret", *stubber.SdkConfig)
+
+		// Act
+		status := check.Health(ctx)
+
+		// Assert
+		assert.False(t, status.HasError())
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return unhealthy when secret cannot be described", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DescribeSecret",
+			Input: &secretsmanager.DescribeSecretInput{
+				SecretId: aws.String("my-secret"),
+			},
+			Error: &testtools.StubError{Err: errors.New("ClientError")},
+		})
+
+		check := NewSecretHealthCheck("my-secret", *stubber.SdkConfig)
+
+		// Act
+		status := check.Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		testtools.ExitTest(stubber, t)
+	})
+}
@@ -0,0 +1,78 @@
+package cloud
+
+import "sync"
+
+// keyedQueue runs the work sharing the same key one at a time, in the order
+// its turns were taken, while letting the work of different keys run concurrently
+type keyedQueue struct {
+	mutex sync.Mutex
+	tails map[string]*keyedTurn
+}
+
+// keyedTurn is the place of a work in the queue of its key
+type keyedTurn struct {
+	queue    *keyedQueue
+	key      string
+	previous chan struct{}
+	done     chan struct{}
+}
+
+func newKeyedQueue() *keyedQueue {
+	return &keyedQueue{
+		tails: make(map[string]*keyedTurn),
+	}
+}
+
+// Take returns the next turn of the key, it runs after every turn taken before it
+func (q *keyedQueue) Take(key string) *keyedTurn {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	turn := &keyedTurn{
+		queue: q,
+		key:   key,
+		done:  make(chan struct{}),
+	}
+
+	if tail, ok := q.tails[key]; ok {
+		turn.previous = tail.done
+	}
+	q.tails[key] = turn
+
+	return turn
+}
+
+// Wait blocks until every turn taken before this one is done,
+// a nil turn never waits
+func (t *keyedTurn) Wait() {
+	if t == nil || t.previous == nil {
+		return
+	}
+
+	<-t.previous
+}
+
+// Done hands the key over to the next turn, a turn that is done
+// without waiting still lets the turns before it finish first
+func (t *keyedTurn) Done() {
+	if t == nil {
+		return
+	}
+
+	t.Wait()
+
+	t.queue.mutex.Lock()
+	if t.queue.tails[t.key] == t {
+		delete(t.queue.tails, t.key)
+	}
+	t.queue.mutex.Unlock()
+
+	close(t.done)
+}
+
+func (q *keyedQueue) size() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.tails)
+}
@@ -0,0 +1,185 @@
+package cloud
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedQueue(t *testing.T) {
+	t.Run("Should serialize the work of the same key", func(t *testing.T) {
+		// Arrange
+		queue := newKeyedQueue()
+
+		var waitGroup sync.WaitGroup
+		var running, maxRunning int
+		var counter sync.Mutex
+
+		// Act
+		for i := 0; i < 5; i++ {
+			waitGroup.Add(1)
+			go func(turn *keyedTurn) {
+				defer waitGroup.Done()
+				defer turn.Done()
+
+				turn.Wait()
+
+				counter.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				counter.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				counter.Lock()
+				running--
+				counter.Unlock()
+			}(queue.Take("order"))
+		}
+
+		waitGroup.Wait()
+
+		// Assert
+		assert.Equal(t, 1, maxRunning)
+		assert.Zero(t, queue.size())
+	})
+
+	t.Run("Should run the work of the same key in the order the turns were taken", func(t *testing.T) {
+		// Arrange
+		queue := newKeyedQueue()
+
+		turns := make([]*keyedTurn, 5)
+		for i := range turns {
+			turns[i] = queue.Take("order")
+		}
+
+		var waitGroup sync.WaitGroup
+		var order []int
+		var counter sync.Mutex
+
+		// Act
+		for i := len(turns) - 1; i >= 0; i-- {
+			waitGroup.Add(1)
+			go func(i int) {
+				defer waitGroup.Done()
+				defer turns[i].Done()
+
+				turns[i].Wait()
+
+				counter.Lock()
+				order = append(order, i)
+				counter.Unlock()
+			}(i)
+
+			// lets the later turns reach the queue first
+			time.Sleep(time.Millisecond)
+		}
+
+		waitGroup.Wait()
+
+		// Assert
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+		assert.Zero(t, queue.size())
+	})
+
+	t.Run("Should let the previous turns finish when a turn is done without waiting", func(t *testing.T) {
+		// Arrange
+		queue := newKeyedQueue()
+
+		first := queue.Take("order")
+		second := queue.Take("order")
+		third := queue.Take("order")
+
+		released := make(chan struct{})
+
+		// Act
+		go func() {
+			second.Done()
+			third.Wait()
+			close(released)
+		}()
+
+		// Assert
+		select {
+		case <-released:
+			t.Fatal("turn ran before the previous turn was done")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		first.Done()
+
+		select {
+		case <-released:
+		case <-time.After(time.Second):
+			t.Fatal("turn was not released")
+		}
+
+		third.Done()
+		assert.Zero(t, queue.size())
+	})
+
+	t.Run("Should not block the work of different keys", func(t *testing.T) {
+		// Arrange
+		queue := newKeyedQueue()
+
+		turn := queue.Take("order-1")
+		turn.Wait()
+		defer turn.Done()
+
+		done := make(chan struct{})
+
+		// Act
+		go func() {
+			other := queue.Take("order-2")
+			other.Wait()
+			other.Done()
+			close(done)
+		}()
+
+		// Assert
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("turn of a different key was blocked")
+		}
+		assert.Equal(t, 1, queue.size())
+	})
+
+	t.Run("Should not wait on a nil turn", func(t *testing.T) {
+		// Arrange
+		var turn *keyedTurn
+
+		// Act
+		turn.Wait()
+		turn.Done()
+
+		// Assert
+		assert.Nil(t, turn)
+	})
+}
+
+func TestPeekOrderId(t *testing.T) {
+	t.Run("Should read the order id of the notification", func(t *testing.T) {
+		// Arrange
+		body := `{"Type":"Notification","Message":"{\"order_id\":\"c3fdab1b-3c06-4db2-9edc-4760a2429462\"}"}`
+
+		// Act
+		orderId := peekOrderId(body)
+
+		// Assert
+		assert.Equal(t, "c3fdab1b-3c06-4db2-9edc-4760a2429462", orderId)
+	})
+
+	t.Run("Should return an empty order id when the notification cannot be read", func(t *testing.T) {
+		// Arrange
+		body := `not a notification`
+
+		// Act
+		orderId := peekOrderId(body)
+
+		// Assert
+		assert.Empty(t, orderId)
+	})
+}
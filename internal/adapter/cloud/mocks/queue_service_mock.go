@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	health "github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,11 +14,6 @@ type MockQueueService struct {
 	mock.Mock
 }
 
-// ConsumeMessages provides a mock function with given fields: ctx
-func (_m *MockQueueService) ConsumeMessages(ctx context.Context) {
-	_m.Called(ctx)
-}
-
 // GetQueueName provides a mock function with given fields:
 func (_m *MockQueueService) GetQueueName() string {
 	ret := _m.Called()
@@ -36,6 +32,69 @@ func (_m *MockQueueService) GetQueueName() string {
 	return r0
 }
 
+// Health provides a mock function with given fields: ctx
+func (_m *MockQueueService) Health(ctx context.Context) *health.HealthStatus {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Health")
+	}
+
+	var r0 *health.HealthStatus
+	if rf, ok := ret.Get(0).(func(context.Context) *health.HealthStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*health.HealthStatus)
+		}
+	}
+
+	return r0
+}
+
+// Heartbeat provides a mock function with given fields:
+func (_m *MockQueueService) Heartbeat() health.HealthCheck {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Heartbeat")
+	}
+
+	var r0 health.HealthCheck
+	if rf, ok := ret.Get(0).(func() health.HealthCheck); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(health.HealthCheck)
+		}
+	}
+
+	return r0
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *MockQueueService) Start(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *MockQueueService) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stop")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateQueueUrl provides a mock function with given fields: ctx
 func (_m *MockQueueService) UpdateQueueUrl(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -4,6 +4,9 @@ package mocks
 
 import (
 	context "context"
+	jsontext "encoding/json/jsontext"
+
+	health "github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -31,9 +34,29 @@ func (_m *MockTopicService) GetTopicName() string {
 	return r0
 }
 
-// PublishMessage provides a mock function with given fields: ctx, message
-func (_m *MockTopicService) PublishMessage(ctx context.Context, message interface{}) (*string, error) {
-	ret := _m.Called(ctx, message)
+// Health provides a mock function with given fields: ctx
+func (_m *MockTopicService) Health(ctx context.Context) *health.HealthStatus {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Health")
+	}
+
+	var r0 *health.HealthStatus
+	if rf, ok := ret.Get(0).(func(context.Context) *health.HealthStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*health.HealthStatus)
+		}
+	}
+
+	return r0
+}
+
+// PublishMessage provides a mock function with given fields: ctx, deduplicationId, payload
+func (_m *MockTopicService) PublishMessage(ctx context.Context, deduplicationId string, payload jsontext.Value) (*string, error) {
+	ret := _m.Called(ctx, deduplicationId, payload)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PublishMessage")
@@ -41,19 +64,19 @@ func (_m *MockTopicService) PublishMessage(ctx context.Context, message interfac
 
 	var r0 *string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) (*string, error)); ok {
-		return rf(ctx, message)
+	if rf, ok := ret.Get(0).(func(context.Context, string, jsontext.Value) (*string, error)); ok {
+		return rf(ctx, deduplicationId, payload)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) *string); ok {
-		r0 = rf(ctx, message)
+	if rf, ok := ret.Get(0).(func(context.Context, string, jsontext.Value) *string); ok {
+		r0 = rf(ctx, deduplicationId, payload)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
-		r1 = rf(ctx, message)
+	if rf, ok := ret.Get(1).(func(context.Context, string, jsontext.Value) error); ok {
+		r1 = rf(ctx, deduplicationId, payload)
 	} else {
 		r1 = ret.Error(1)
 	}
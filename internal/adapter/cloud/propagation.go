@@ -0,0 +1,162 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var messagingSystemSns = semconv.MessagingSystemKey.String("aws_sns")
+
+// snsAttributesCarrier writes the trace context into the attributes of a SNS message
+type snsAttributesCarrier map[string]snstypes.MessageAttributeValue
+
+func (c snsAttributesCarrier) Get(key string) string {
+	if value, ok := c[key]; ok && value.StringValue != nil {
+		return *value.StringValue
+	}
+
+	return ""
+}
+
+func (c snsAttributesCarrier) Set(key string, value string) {
+	c[key] = snstypes.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c snsAttributesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// sqsAttributesCarrier reads the trace context of a message sent straight to SQS
+// or delivered by SNS with raw message delivery
+type sqsAttributesCarrier map[string]sqstypes.MessageAttributeValue
+
+func (c sqsAttributesCarrier) Get(key string) string {
+	if value, ok := c[key]; ok && value.StringValue != nil {
+		return *value.StringValue
+	}
+
+	return ""
+}
+
+func (c sqsAttributesCarrier) Set(key string, value string) {
+	c[key] = sqstypes.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c sqsAttributesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// notificationAttributesCarrier reads the trace context of the SNS envelope of a message
+type notificationAttributesCarrier map[string]TopicNotificationAttribute
+
+func (c notificationAttributesCarrier) Get(key string) string {
+	if value, ok := c[key]; ok && value.Type == "String" {
+		return value.Value
+	}
+
+	return ""
+}
+
+func (c notificationAttributesCarrier) Set(key string, value string) {
+	c[key] = TopicNotificationAttribute{
+		Type:  "String",
+		Value: value,
+	}
+}
+
+func (c notificationAttributesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// startPublishSpan starts the span of a publication, the attributes returned carry
+// its trace context and are nil when there is nothing to propagate
+func startPublishSpan(ctx context.Context, topicName string) (context.Context, trace.Span, map[string]snstypes.MessageAttributeValue) {
+	ctx, span := tracing.Tracer().Start(ctx, topicName+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			messagingSystemSns,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(topicName),
+		),
+	)
+
+	attributes := snsAttributesCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, attributes)
+
+	if len(attributes) == 0 {
+		return ctx, span, nil
+	}
+
+	return ctx, span, attributes
+}
+
+// startProcessSpan starts the span of a message continuing the trace of its producer,
+// read from the SQS attributes or else from the SNS envelope in the body
+func startProcessSpan(ctx context.Context, queueName string, message sqstypes.Message) (context.Context, trace.Span) {
+	propagator := otel.GetTextMapPropagator()
+
+	remote := propagator.Extract(ctx, sqsAttributesCarrier(message.MessageAttributes))
+
+	if !trace.SpanContextFromContext(remote).IsValid() && message.Body != nil {
+		var notification TopicNotification
+
+		// an invalid body fails later while parsing the request, here it only has no trace
+		if err := json.Unmarshal([]byte(*message.Body), &notification); err == nil {
+			remote = propagator.Extract(ctx, notificationAttributesCarrier(notification.MessageAttributes))
+		}
+	}
+
+	return tracing.Tracer().Start(remote, queueName+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemAWSSqs,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(queueName),
+			semconv.MessagingMessageID(aws.ToString(message.MessageId)),
+		),
+	)
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func messageIdAttribute(messageId *string) attribute.KeyValue {
+	return semconv.MessagingMessageID(aws.ToString(messageId))
+}
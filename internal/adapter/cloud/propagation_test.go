@@ -0,0 +1,140 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useInMemoryTracing sets an in-memory provider as the global one for the test,
+// the other tests expect nothing to be propagated
+func useInMemoryTracing(t *testing.T) *tracetest.InMemoryExporter {
+	provider := otel.GetTracerProvider()
+	propagator := otel.GetTextMapPropagator()
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	memoryProvider := tracing.NewSyncTracerProvider(exporter)
+
+	t.Cleanup(func() {
+		_ = memoryProvider.Shutdown(context.Background())
+
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	return exporter
+}
+
+func TestStartPublishSpan(t *testing.T) {
+	t.Run("Should not propagate anything when tracing is disabled", func(t *testing.T) {
+		// Act
+		_, span, attributes := startPublishSpan(context.Background(), "update-order-topic")
+		span.End()
+
+		// Assert
+		assert.Nil(t, attributes)
+	})
+
+	t.Run("Should inject the trace context of the publication", func(t *testing.T) {
+		// Arrange
+		exporter := useInMemoryTracing(t)
+
+		// Act
+		ctx, span, attributes := startPublishSpan(context.Background(), "update-order-topic")
+		span.End()
+
+		// Assert
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "update-order-topic publish", spans[0].Name)
+		assert.Equal(t, trace.SpanKindProducer, spans[0].SpanKind)
+		assert.Contains(t, *attributes["traceparent"].StringValue, trace.SpanContextFromContext(ctx).TraceID().String())
+	})
+}
+
+func TestStartProcessSpan(t *testing.T) {
+	t.Run("Should continue the trace of the SQS attributes", func(t *testing.T) {
+		// Arrange
+		exporter := useInMemoryTracing(t)
+
+		producerCtx, producer, attributes := startPublishSpan(context.Background(), "order-payment-topic")
+		producer.End()
+
+		carrier := sqsAttributesCarrier{}
+		for key, value := range attributes {
+			carrier.Set(key, *value.StringValue)
+		}
+
+		message := types.Message{
+			MessageId:         aws.String("message_id"),
+			MessageAttributes: carrier,
+		}
+
+		// Act
+		ctx, span := startProcessSpan(context.Background(), "order-payment-queue", message)
+		span.End()
+
+		// Assert
+		assert.Equal(t, trace.SpanContextFromContext(producerCtx).TraceID(), trace.SpanContextFromContext(ctx).TraceID())
+		assert.Len(t, exporter.GetSpans(), 2)
+		assert.Equal(t, trace.SpanKindConsumer, exporter.GetSpans()[1].SpanKind)
+	})
+
+	t.Run("Should continue the trace of the SNS envelope", func(t *testing.T) {
+		// Arrange
+		useInMemoryTracing(t)
+
+		producerCtx, producer, attributes := startPublishSpan(context.Background(), "order-payment-topic")
+		producer.End()
+
+		notification := TopicNotification{
+			Type:              "Notification",
+			Message:           "{}",
+			MessageAttributes: notificationAttributesCarrier{},
+		}
+		for key, value := range attributes {
+			notificationAttributesCarrier(notification.MessageAttributes).Set(key, *value.StringValue)
+		}
+
+		body, err := json.Marshal(notification)
+		assert.NoError(t, err)
+
+		message := types.Message{
+			MessageId: aws.String("message_id"),
+			Body:      aws.String(string(body)),
+		}
+
+		// Act
+		ctx, span := startProcessSpan(context.Background(), "order-payment-queue", message)
+		span.End()
+
+		// Assert
+		assert.Equal(t, trace.SpanContextFromContext(producerCtx).TraceID(), trace.SpanContextFromContext(ctx).TraceID())
+	})
+
+	t.Run("Should start a new trace when the message has none", func(t *testing.T) {
+		// Arrange
+		useInMemoryTracing(t)
+
+		message := types.Message{
+			MessageId: aws.String("message_id"),
+			Body:      aws.String("not json"),
+		}
+
+		// Act
+		ctx, span := startProcessSpan(context.Background(), "order-payment-queue", message)
+		span.End()
+
+		// Assert
+		assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+	})
+}
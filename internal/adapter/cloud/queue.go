@@ -3,43 +3,102 @@ package cloud
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/create"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 )
 
 type CtxKey string
 
 const MessageId CtxKey = "message_id"
 
+const (
+	maxReceiveMessages int32 = 10
+	receiveErrorDelay        = time.Second
+	releaseTimeout           = 5 * time.Second
+)
+
+var (
+	ErrQueueNotResolved   = errors.New("queue url not resolved")
+	ErrConsumerNotStarted = errors.New("queue consumer not started")
+	ErrConsumerStalled    = errors.New("queue consumer stalled")
+)
+
 type QueueService interface {
 	GetQueueName() string
 	UpdateQueueUrl(ctx context.Context) error
-	ConsumeMessages(ctx context.Context)
+	Start(ctx context.Context)
+	Stop(ctx context.Context) error
+
+	// Health checks the queue can be reached
+	health.HealthCheck
+
+	// Heartbeat checks the consumer loop is still polling
+	Heartbeat() health.HealthCheck
 }
 
+// QueueObserver is notified of the outcome of the queue operations,
+// the metrics decorator registers itself through SetObserver
+type QueueObserver interface {
+	ObserveReceive(messages int, err error)
+	ObserveDelete(err error)
+	ObserveProcess(duration time.Duration, err error)
+}
+
+type noopQueueObserver struct{}
+
+func (noopQueueObserver) ObserveReceive(messages int, err error)           {}
+func (noopQueueObserver) ObserveDelete(err error)                          {}
+func (noopQueueObserver) ObserveProcess(duration time.Duration, err error) {}
+
 type AwsSqsService struct {
 	queueName string
 	queueUrl  string
+	dlqName   string
+	dlqUrl    string
+	config    *environment.QueueConfig
 	client    *sqs.Client
 
 	createPayment        service.CreatePaymentService[create.CreatePaymentDTO]
 	createPaymentGateway service.CreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO]
 
-	chanMessage chan types.Message
+	// slots bounds how many messages are processed at the same time
+	slots      chan struct{}
+	orderTurns *keyedQueue
+	waitGroup  sync.WaitGroup
+
+	cancel    context.CancelFunc
+	abort     context.CancelFunc
+	pollGroup sync.WaitGroup
+
+	// inFlight holds the receipt handles of the messages not settled yet
+	inFlight      map[string]*string
+	inFlightMutex sync.Mutex
+
+	observer QueueObserver
 
-	mutex     sync.Mutex
-	waitGroup sync.WaitGroup
+	// lastPoll is the unix nano time the consumer loop last polled the queue
+	lastPoll atomic.Int64
 }
 
 func NewQueueService(
 	queueName string,
+	dlqName string,
+	queueConfig *environment.QueueConfig,
 	config aws.Config,
 	createPayment service.CreatePaymentService[create.CreatePaymentDTO],
 	createPaymentGateway service.CreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO],
@@ -48,18 +107,28 @@ func NewQueueService(
 
 	return &AwsSqsService{
 		queueName: queueName,
+		dlqName:   dlqName,
+		config:    queueConfig,
 		client:    client,
 
 		createPayment:        createPayment,
 		createPaymentGateway: createPaymentGateway,
 
-		chanMessage: make(chan types.Message, 10),
+		slots:      make(chan struct{}, max(queueConfig.Concurrency, 1)),
+		orderTurns: newKeyedQueue(),
+		waitGroup:  sync.WaitGroup{},
 
-		mutex:     sync.Mutex{},
-		waitGroup: sync.WaitGroup{},
+		inFlight: make(map[string]*string),
+
+		observer: noopQueueObserver{},
 	}
 }
 
+// SetObserver must be called before Start
+func (s *AwsSqsService) SetObserver(observer QueueObserver) {
+	s.observer = observer
+}
+
 func (s *AwsSqsService) GetQueueName() string {
 	return s.queueName
 }
@@ -74,79 +143,471 @@ func (s *AwsSqsService) UpdateQueueUrl(ctx context.Context) error {
 
 	s.queueUrl = *output.QueueUrl
 
+	output, err = s.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: &s.dlqName,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.dlqUrl = *output.QueueUrl
+
 	return nil
 }
 
-func (s *AwsSqsService) ConsumeMessages(ctx context.Context) {
+func (s *AwsSqsService) Health(ctx context.Context) *health.HealthStatus {
+	if s.queueUrl == "" {
+		return health.Unhealthy(ErrQueueNotResolved)
+	}
+
+	_, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: &s.queueUrl,
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameQueueArn,
+		},
+	})
+	if err != nil {
+		return health.Unhealthy(err)
+	}
+
+	return health.Healthy()
+}
+
+// Heartbeat reports the consumer unhealthy when it did not poll within the heartbeat timeout,
+// which also happens when every worker is stuck on a message
+func (s *AwsSqsService) Heartbeat() health.HealthCheck {
+	return health.CheckFunc(func(ctx context.Context) *health.HealthStatus {
+		lastPoll := s.lastPoll.Load()
+		if lastPoll == 0 {
+			return health.Unhealthy(ErrConsumerNotStarted)
+		}
+
+		elapsed := time.Since(time.Unix(0, lastPoll))
+		if elapsed > s.config.HeartbeatTimeout {
+			return health.Unhealthy(fmt.Errorf("%w: last poll %s ago", ErrConsumerStalled, elapsed.Round(time.Second)))
+		}
+
+		return health.Healthy()
+	})
+}
+
+func (s *AwsSqsService) beat() {
+	s.lastPoll.Store(time.Now().UnixNano())
+}
+
+// Start long-polls the queue in background until Stop is called
+func (s *AwsSqsService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.beat()
+
+	// the workers outlive the polling, so the messages in flight can finish while draining
+	workerCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	s.abort = abort
+
+	s.pollGroup.Add(1)
+
+	go func() {
+		defer s.pollGroup.Done()
+
+		slog.InfoContext(ctx, "queue consumer started", "queue_name", s.queueName, "concurrency", cap(s.slots))
+
+		s.consumeMessages(ctx, workerCtx)
+
+		slog.InfoContext(ctx, "queue consumer stopped", "queue_name", s.queueName)
+	}()
+}
+
+// Stop stops the polling and waits for the messages in flight, when the context
+// is done before they finish, the unfinished messages are released back to the queue
+func (s *AwsSqsService) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.pollGroup.Wait()
+		s.waitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.abort()
+		return nil
+	case <-ctx.Done():
+		s.abort()
+
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancel()
+
+		s.releaseMessages(releaseCtx)
+
+		return ctx.Err()
+	}
+}
+
+func (s *AwsSqsService) consumeMessages(ctx context.Context, workerCtx context.Context) {
+	for ctx.Err() == nil {
+		err := s.receiveMessages(ctx, workerCtx)
+
+		s.beat()
+
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "error receiving message from queue", "queue_url", s.queueUrl, "error", err)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(receiveErrorDelay):
+			}
+		}
+	}
+}
+
+func (s *AwsSqsService) receiveMessages(ctx context.Context, workerCtx context.Context) error {
+	slots := s.acquireSlots(ctx)
+	if slots == 0 {
+		return nil
+	}
+
 	output, err := s.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
 		QueueUrl:            &s.queueUrl,
-		MaxNumberOfMessages: 10,
+		MaxNumberOfMessages: slots,
 		WaitTimeSeconds:     20,
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeName(types.MessageSystemAttributeNameApproximateReceiveCount),
+		},
+		// the trace context of the producer travels in the message attributes
+		MessageAttributeNames: []string{"All"},
 	})
 	if err != nil {
-		slog.ErrorContext(ctx, "error receiving message from queue", "queue_url", s.queueUrl, "error", err)
-		return
+		s.observer.ObserveReceive(0, err)
+		s.releaseSlots(int(slots))
+		return err
 	}
 
+	s.observer.ObserveReceive(len(output.Messages), nil)
+
+	s.releaseSlots(int(slots) - len(output.Messages))
+
 	s.waitGroup.Add(len(output.Messages))
 
 	for _, message := range output.Messages {
-		go s.processMessage(ctx, message)
+		s.track(message)
+
+		// the turns are taken here, in the order the messages were received,
+		// so the messages of the same order are handled in that order
+		turn := s.takeOrderTurn(message)
+
+		go s.processMessage(workerCtx, message, turn)
 	}
 
-	s.waitGroup.Wait()
+	return nil
 }
 
-func (s *AwsSqsService) processMessage(ctx context.Context, message types.Message) {
+// acquireSlots waits for a free worker and then takes every other free
+// worker, up to the number of messages a single receive can return
+func (s *AwsSqsService) acquireSlots(ctx context.Context) int32 {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return 0
+	}
+
+	// a worker may be freed at the same time the context is done
+	if ctx.Err() != nil {
+		s.releaseSlots(1)
+		return 0
+	}
+
+	acquired := int32(1)
+
+	for acquired < maxReceiveMessages {
+		select {
+		case s.slots <- struct{}{}:
+			acquired++
+		default:
+			return acquired
+		}
+	}
+
+	return acquired
+}
+
+func (s *AwsSqsService) releaseSlots(slots int) {
+	for i := 0; i < slots; i++ {
+		<-s.slots
+	}
+}
+
+// takeOrderTurn returns the turn of the order of the message,
+// or nil when the order cannot be read from the message
+func (s *AwsSqsService) takeOrderTurn(message types.Message) *keyedTurn {
+	orderId := peekOrderId(aws.ToString(message.Body))
+	if orderId == "" {
+		return nil
+	}
+
+	return s.orderTurns.Take(orderId)
+}
+
+func (s *AwsSqsService) processMessage(ctx context.Context, message types.Message, turn *keyedTurn) {
 	defer s.waitGroup.Done()
-	s.mutex.Lock()
+	defer s.releaseSlots(1)
+	defer turn.Done()
 
 	ctx = context.WithValue(ctx, MessageId, *message.MessageId)
 
-	slog.InfoContext(ctx, "message received")
+	ctx, span := startProcessSpan(ctx, s.queueName, message)
+	defer span.End()
+
+	slog.InfoContext(ctx, "message received", "message_id", *message.MessageId)
+
+	// messages of the same order are handled one at a time
+	turn.Wait()
+
+	start := time.Now()
+
+	request, err := s.parseMessage(ctx, message)
+	if err == nil {
+		err = s.handleRequest(ctx, request)
+	}
+
+	s.observer.ObserveProcess(time.Since(start), err)
+
+	recordSpanError(span, err)
+
+	// an aborted message stays tracked, so Stop releases it back to the queue
+	// whether it gets there before or after this worker
+	if ctx.Err() != nil {
+		slog.WarnContext(ctx, "message processing aborted", "error", err)
+		return
+	}
+
+	defer s.untrack(message)
+
+	if err := s.settleMessage(ctx, message, err); err != nil {
+		slog.ErrorContext(ctx, "error settling message", "error", err)
+	}
+}
+
+// settleMessage acknowledges the message when it was processed or can never be,
+// otherwise the message is scheduled to be retried
+func (s *AwsSqsService) settleMessage(ctx context.Context, message types.Message, processErr error) error {
+	if processErr == nil {
+		return s.deleteMessage(ctx, message)
+	}
+
+	if isRejectedError(processErr) {
+		slog.ErrorContext(ctx, "message rejected, sending to dead-letter queue", "error", processErr)
+
+		if err := s.sendToDeadLetterQueue(ctx, message, getReceiveCount(message), processErr); err != nil {
+			return err
+		}
+
+		return s.deleteMessage(ctx, message)
+	}
+
+	if isPermanentError(processErr) {
+		slog.WarnContext(ctx, "acknowledging message that cannot be processed", "error", processErr)
+		return s.deleteMessage(ctx, message)
+	}
+
+	return s.retryMessage(ctx, message, processErr)
+}
 
+func (s *AwsSqsService) parseMessage(ctx context.Context, message types.Message) (create.CreatePaymentDTO, error) {
+	return parseNotification(ctx, aws.ToString(message.Body))
+}
+
+// parseNotification reads the payment request from the SNS envelope of a message
+func parseNotification(ctx context.Context, body string) (create.CreatePaymentDTO, error) {
 	var notification TopicNotification
 
-	err := json.Unmarshal([]byte(*message.Body), &notification)
-	if err != nil {
+	if err := json.Unmarshal([]byte(body), &notification); err != nil {
 		slog.ErrorContext(ctx, "error unmarshalling message", "error", err)
-	} else {
-		if notification.Type != "Notification" {
-			slog.ErrorContext(ctx, "invalid notification type", "type", notification.Type)
-		} else {
-			var request create.CreatePaymentDTO
-
-			err = json.Unmarshal([]byte(notification.Message), &request)
-			if err != nil {
-				slog.ErrorContext(ctx, "error unmarshalling message", "error", err)
-			}
+		return create.CreatePaymentDTO{}, custom_error.ErrQueueMessageNotValid
+	}
 
-			if err == nil {
-				slog.InfoContext(ctx, "message unmarshalled", "request", request)
-				payment, err := s.createPayment.Handle(ctx, request)
-				if err != nil {
-					slog.ErrorContext(ctx, "error create payment", "error", err)
-				}
-
-				if payment != nil {
-					gatewayReq := gateway.CreatePaymentGatewayDTO{
-						PaymentID: payment.PaymentId,
-						Amount:    payment.Amount,
-					}
-
-					if err := s.createPaymentGateway.Handle(ctx, gatewayReq); err != nil {
-						slog.ErrorContext(ctx, "error create payment gateway", "error", err)
-					}
-				}
-			}
+	if notification.Type != "Notification" {
+		slog.ErrorContext(ctx, "invalid notification type", "type", notification.Type)
+		return create.CreatePaymentDTO{}, custom_error.ErrQueueMessageNotValid
+	}
+
+	var request create.CreatePaymentDTO
+
+	if err := json.Unmarshal([]byte(notification.Message), &request); err != nil {
+		slog.ErrorContext(ctx, "error unmarshalling message", "error", err)
+		return create.CreatePaymentDTO{}, custom_error.ErrQueueMessageNotValid
+	}
+
+	slog.InfoContext(ctx, "message unmarshalled", "request", request)
+
+	return request, nil
+}
+
+// peekOrderId reads the order id of a notification without validating it,
+// an empty id is returned when the notification cannot be read
+func peekOrderId(body string) string {
+	var notification TopicNotification
+	if err := json.Unmarshal([]byte(body), &notification); err != nil {
+		return ""
+	}
+
+	var request struct {
+		OrderId string `json:"order_id"`
+	}
+	if err := json.Unmarshal([]byte(notification.Message), &request); err != nil {
+		return ""
+	}
+
+	return request.OrderId
+}
+
+func (s *AwsSqsService) handleRequest(ctx context.Context, request create.CreatePaymentDTO) error {
+	return handlePaymentRequest(ctx, s.createPayment, s.createPaymentGateway, request)
+}
+
+func handlePaymentRequest(
+	ctx context.Context,
+	createPayment service.CreatePaymentService[create.CreatePaymentDTO],
+	createPaymentGateway service.CreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO],
+	request create.CreatePaymentDTO,
+) error {
+	gatewayReq := gateway.CreatePaymentGatewayDTO{
+		PaymentID: request.PaymentId,
+		Amount:    request.Amount,
+	}
+
+	payment, err := createPayment.Handle(ctx, request)
+	if err != nil && !errors.Is(err, custom_error.ErrPaymentAlreadyExists) {
+		slog.ErrorContext(ctx, "error create payment", "error", err)
+		return err
+	}
+
+	// a redelivered message finds the payment already created, but the charge
+	// may still be missing, the gateway service skips payments that have one
+	if payment != nil {
+		gatewayReq.PaymentID = payment.PaymentId
+		gatewayReq.Amount = payment.Amount
+	}
+
+	if err := createPaymentGateway.Handle(ctx, gatewayReq); err != nil {
+		slog.ErrorContext(ctx, "error create payment gateway", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// retryMessage makes the message visible again after an exponential backoff,
+// forwarding it to the dead-letter queue once it was received too many times
+func (s *AwsSqsService) retryMessage(ctx context.Context, message types.Message, reason error) error {
+	receiveCount := getReceiveCount(message)
+
+	if receiveCount >= s.config.MaxReceiveCount {
+		slog.ErrorContext(ctx, "message exceeded the max receive count, sending to dead-letter queue", "receive_count", receiveCount, "error", reason)
+
+		if err := s.sendToDeadLetterQueue(ctx, message, receiveCount, reason); err != nil {
+			return err
 		}
+
+		return s.deleteMessage(ctx, message)
 	}
 
-	if err := s.deleteMessage(ctx, message); err != nil {
-		slog.ErrorContext(ctx, "error deleting message", "error", err)
+	backoff := s.backoff(receiveCount)
+
+	slog.WarnContext(ctx, "message processing failed, retrying later", "receive_count", receiveCount, "backoff", backoff, "error", reason)
+
+	_, err := s.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          &s.queueUrl,
+		ReceiptHandle:     message.ReceiptHandle,
+		VisibilityTimeout: int32(backoff.Seconds()),
+	})
+
+	return err
+}
+
+func (s *AwsSqsService) sendToDeadLetterQueue(ctx context.Context, message types.Message, receiveCount int, reason error) error {
+	_, err := s.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    &s.dlqUrl,
+		MessageBody: message.Body,
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"reason": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(reason.Error()),
+			},
+			"source_queue": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(s.queueName),
+			},
+			"source_message_id": {
+				DataType:    aws.String("String"),
+				StringValue: message.MessageId,
+			},
+			"receive_count": {
+				DataType:    aws.String("Number"),
+				StringValue: aws.String(strconv.Itoa(receiveCount)),
+			},
+		},
+	})
+
+	return err
+}
+
+func (s *AwsSqsService) backoff(receiveCount int) time.Duration {
+	return retryBackoff(s.config, receiveCount)
+}
+
+func retryBackoff(config *environment.QueueConfig, receiveCount int) time.Duration {
+	backoff := config.BaseBackoff
+
+	for i := 1; i < receiveCount && backoff < config.MaxBackoff; i++ {
+		backoff *= 2
 	}
 
-	s.mutex.Unlock()
+	return min(backoff, config.MaxBackoff)
+}
+
+func (s *AwsSqsService) track(message types.Message) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	s.inFlight[*message.MessageId] = message.ReceiptHandle
+}
+
+func (s *AwsSqsService) untrack(message types.Message) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	delete(s.inFlight, *message.MessageId)
+}
+
+// releaseMessages makes the unfinished messages visible again right away,
+// instead of waiting for their visibility timeout to expire
+func (s *AwsSqsService) releaseMessages(ctx context.Context) {
+	s.inFlightMutex.Lock()
+	defer s.inFlightMutex.Unlock()
+
+	for messageId, receiptHandle := range s.inFlight {
+		delete(s.inFlight, messageId)
+
+		_, err := s.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          &s.queueUrl,
+			ReceiptHandle:     receiptHandle,
+			VisibilityTimeout: 0,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "error releasing message", "message_id", messageId, "error", err)
+			continue
+		}
+
+		slog.InfoContext(ctx, "message released back to the queue", "message_id", messageId)
+	}
 }
 
 func (s *AwsSqsService) deleteMessage(ctx context.Context, message types.Message) error {
@@ -154,9 +615,49 @@ func (s *AwsSqsService) deleteMessage(ctx context.Context, message types.Message
 		QueueUrl:      &s.queueUrl,
 		ReceiptHandle: message.ReceiptHandle,
 	})
+
+	s.observer.ObserveDelete(err)
+
+	return err
+}
+
+func getReceiveCount(message types.Message) int {
+	receiveCount, err := strconv.Atoi(message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
 	if err != nil {
-		return err
+		return 1
 	}
 
-	return nil
+	return receiveCount
+}
+
+// permanentErrors are acknowledged right away, retrying them would fail the same way
+var permanentErrors = []error{
+	custom_error.ErrQueueMessageNotValid,
+	custom_error.ErrPaymentAlreadyExists,
+	custom_error.ErrGatewayRequestNotValid,
+}
+
+// rejectedErrors would fail the same way when retried too, but the order the message carries
+// would be lost, so it is sent to the dead-letter queue right away instead of being acknowledged
+var rejectedErrors = []error{
+	custom_error.ErrRequestNotValid,
+	custom_error.ErrPaymentAmountMismatch,
+}
+
+func isPermanentError(err error) bool {
+	return isAnyError(err, permanentErrors)
+}
+
+func isRejectedError(err error) bool {
+	return isAnyError(err, rejectedErrors)
+}
+
+func isAnyError(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,292 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/create"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
+)
+
+const memoryQueueCapacity = 1000
+
+var (
+	ErrUnknownDriver   = errors.New("unknown cloud driver")
+	ErrMemoryQueueFull = errors.New("in-memory queue is full")
+)
+
+// MemoryMessage is a message held by the in-memory queue
+type MemoryMessage struct {
+	MessageId    string `json:"message_id"`
+	Body         string `json:"body"`
+	ReceiveCount int    `json:"receive_count"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// MemoryQueueService replaces SQS for local development and tests, the messages are
+// processed one at a time and are lost when the process stops
+type MemoryQueueService struct {
+	queueName string
+	config    *environment.QueueConfig
+
+	createPayment        service.CreatePaymentService[create.CreatePaymentDTO]
+	createPaymentGateway service.CreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO]
+
+	messages chan MemoryMessage
+
+	deadLetters      []MemoryMessage
+	deadLettersMutex sync.Mutex
+
+	cancel     context.CancelFunc
+	pollGroup  sync.WaitGroup
+	retryGroup sync.WaitGroup
+	started    atomic.Bool
+
+	observer QueueObserver
+}
+
+func NewMemoryQueueService(
+	queueName string,
+	queueConfig *environment.QueueConfig,
+	createPayment service.CreatePaymentService[create.CreatePaymentDTO],
+	createPaymentGateway service.CreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO],
+) *MemoryQueueService {
+	return &MemoryQueueService{
+		queueName: queueName,
+		config:    queueConfig,
+
+		createPayment:        createPayment,
+		createPaymentGateway: createPaymentGateway,
+
+		messages: make(chan MemoryMessage, memoryQueueCapacity),
+
+		observer: noopQueueObserver{},
+	}
+}
+
+// SetObserver must be called before Start
+func (s *MemoryQueueService) SetObserver(observer QueueObserver) {
+	s.observer = observer
+}
+
+func (s *MemoryQueueService) GetQueueName() string {
+	return s.queueName
+}
+
+func (s *MemoryQueueService) UpdateQueueUrl(ctx context.Context) error {
+	return nil
+}
+
+func (s *MemoryQueueService) Health(ctx context.Context) *health.HealthStatus {
+	return health.Healthy()
+}
+
+func (s *MemoryQueueService) Heartbeat() health.HealthCheck {
+	return health.CheckFunc(func(ctx context.Context) *health.HealthStatus {
+		if !s.started.Load() {
+			return health.Unhealthy(ErrConsumerNotStarted)
+		}
+
+		return health.Healthy()
+	})
+}
+
+// Enqueue delivers the notification as if SNS had forwarded it to the queue,
+// the type and message id are filled in when missing
+func (s *MemoryQueueService) Enqueue(ctx context.Context, notification TopicNotification) (string, error) {
+	if notification.Type == "" {
+		notification.Type = "Notification"
+	}
+
+	if notification.MessageId == "" {
+		notification.MessageId = uuid.NewString()
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return "", err
+	}
+
+	message := MemoryMessage{
+		MessageId: notification.MessageId,
+		Body:      string(body),
+	}
+
+	if err := s.push(message); err != nil {
+		return "", err
+	}
+
+	slog.InfoContext(ctx, "message enqueued", "queue_name", s.queueName, "message_id", message.MessageId)
+
+	return message.MessageId, nil
+}
+
+// DeadLetters returns the messages that exceeded the max receive count
+func (s *MemoryQueueService) DeadLetters() []MemoryMessage {
+	s.deadLettersMutex.Lock()
+	defer s.deadLettersMutex.Unlock()
+
+	return append([]MemoryMessage(nil), s.deadLetters...)
+}
+
+func (s *MemoryQueueService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.started.Store(true)
+
+	s.pollGroup.Add(1)
+
+	go func() {
+		defer s.pollGroup.Done()
+
+		slog.InfoContext(ctx, "in-memory queue consumer started", "queue_name", s.queueName)
+
+		s.consumeMessages(ctx)
+
+		slog.InfoContext(ctx, "in-memory queue consumer stopped", "queue_name", s.queueName)
+	}()
+}
+
+// Stop waits for the message in process, the messages waiting for a retry are dropped
+func (s *MemoryQueueService) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.pollGroup.Wait()
+		s.retryGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.started.Store(false)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *MemoryQueueService) consumeMessages(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message := <-s.messages:
+			s.observer.ObserveReceive(1, nil)
+
+			s.processMessage(ctx, message)
+		}
+	}
+}
+
+// processMessage finishes the message even when the consumer is stopped meanwhile,
+// only its redelivery is bound to the consumer context
+func (s *MemoryQueueService) processMessage(consumerCtx context.Context, message MemoryMessage) {
+	message.ReceiveCount++
+
+	ctx := context.WithValue(context.WithoutCancel(consumerCtx), MessageId, message.MessageId)
+
+	ctx, span := startProcessSpan(ctx, s.queueName, types.Message{
+		MessageId: aws.String(message.MessageId),
+		Body:      aws.String(message.Body),
+	})
+	defer span.End()
+
+	slog.InfoContext(ctx, "message received", "message_id", message.MessageId)
+
+	start := time.Now()
+
+	request, err := parseNotification(ctx, message.Body)
+	if err == nil {
+		err = handlePaymentRequest(ctx, s.createPayment, s.createPaymentGateway, request)
+	}
+
+	s.observer.ObserveProcess(time.Since(start), err)
+
+	recordSpanError(span, err)
+
+	s.settleMessage(ctx, consumerCtx, message, err)
+}
+
+// settleMessage follows the same rules of the SQS consumer, the rejected errors are sent to the
+// dead letters, the permanent ones are dropped and the other ones are retried with backoff
+// until the max receive count
+func (s *MemoryQueueService) settleMessage(ctx context.Context, consumerCtx context.Context, message MemoryMessage, processErr error) {
+	if processErr == nil {
+		s.observer.ObserveDelete(nil)
+		return
+	}
+
+	if isRejectedError(processErr) {
+		slog.ErrorContext(ctx, "message rejected, sending to dead-letter queue", "error", processErr)
+		s.deadLetter(message, processErr)
+		return
+	}
+
+	if isPermanentError(processErr) {
+		slog.WarnContext(ctx, "acknowledging message that cannot be processed", "error", processErr)
+		s.observer.ObserveDelete(nil)
+		return
+	}
+
+	if message.ReceiveCount >= s.config.MaxReceiveCount {
+		slog.ErrorContext(ctx, "message exceeded the max receive count, sending to dead-letter queue", "receive_count", message.ReceiveCount, "error", processErr)
+		s.deadLetter(message, processErr)
+		return
+	}
+
+	backoff := retryBackoff(s.config, message.ReceiveCount)
+
+	slog.WarnContext(ctx, "message processing failed, retrying later", "receive_count", message.ReceiveCount, "backoff", backoff, "error", processErr)
+
+	s.retryGroup.Add(1)
+
+	go func() {
+		defer s.retryGroup.Done()
+
+		select {
+		case <-consumerCtx.Done():
+			slog.WarnContext(ctx, "dropping message waiting for retry", "message_id", message.MessageId)
+		case <-time.After(backoff):
+			if err := s.push(message); err != nil {
+				slog.ErrorContext(ctx, "error redelivering message", "message_id", message.MessageId, "error", err)
+			}
+		}
+	}()
+}
+
+func (s *MemoryQueueService) deadLetter(message MemoryMessage, reason error) {
+	message.Reason = reason.Error()
+
+	s.deadLettersMutex.Lock()
+	s.deadLetters = append(s.deadLetters, message)
+	s.deadLettersMutex.Unlock()
+
+	s.observer.ObserveDelete(nil)
+}
+
+func (s *MemoryQueueService) push(message MemoryMessage) error {
+	select {
+	case s.messages <- message:
+		return nil
+	default:
+		return ErrMemoryQueueFull
+	}
+}
@@ -0,0 +1,244 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/create"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const memoryMessage = `{"order_id":"be6293ff-4ec0-4ed8-95c9-b36ce99aa105","payment_id":"a5c81ac9-a549-44c5-bb09-c330116b929f","amount":59.98}`
+
+type settledObserver struct {
+	noopQueueObserver
+
+	settled chan struct{}
+}
+
+func (o *settledObserver) ObserveDelete(err error) {
+	o.settled <- struct{}{}
+}
+
+func newMemoryQueueConfig() *environment.QueueConfig {
+	return &environment.QueueConfig{
+		MaxReceiveCount: 2,
+		BaseBackoff:     time.Millisecond,
+		MaxBackoff:      time.Millisecond,
+	}
+}
+
+func waitSettled(t *testing.T, observer *settledObserver) {
+	select {
+	case <-observer.settled:
+	case <-time.After(time.Second):
+		t.Fatal("message was not settled")
+	}
+}
+
+func TestMemoryQueueEnqueue(t *testing.T) {
+	t.Run("Should fill in the type and the message id", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), nil, nil)
+
+		// Act
+		messageId, err := service.Enqueue(ctx, TopicNotification{Message: memoryMessage})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, messageId)
+
+		message := <-service.messages
+
+		var notification TopicNotification
+		assert.NoError(t, json.Unmarshal([]byte(message.Body), &notification))
+		assert.Equal(t, "Notification", notification.Type)
+		assert.Equal(t, messageId, notification.MessageId)
+		assert.Equal(t, memoryMessage, notification.Message)
+	})
+
+	t.Run("Should return an error when the queue is full", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), nil, nil)
+
+		for i := 0; i < memoryQueueCapacity; i++ {
+			_, err := service.Enqueue(ctx, TopicNotification{Message: memoryMessage})
+			assert.NoError(t, err)
+		}
+
+		// Act
+		_, err := service.Enqueue(ctx, TopicNotification{Message: memoryMessage})
+
+		// Assert
+		assert.ErrorIs(t, err, ErrMemoryQueueFull)
+	})
+}
+
+func TestMemoryQueueConsume(t *testing.T) {
+	t.Run("Should create the payment and the charge of the message", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, mock.Anything).
+			Return(&payment_entity.Payment{PaymentId: "a5c81ac9-a549-44c5-bb09-c330116b929f"}, nil).
+			Once()
+
+		createPaymentGateway.On("Handle", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+
+		observer := &settledObserver{settled: make(chan struct{}, 1)}
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), createPayment, createPaymentGateway)
+		service.SetObserver(observer)
+		service.Start(ctx)
+
+		// Act
+		_, err := service.Enqueue(ctx, TopicNotification{Message: memoryMessage})
+
+		// Assert
+		assert.NoError(t, err)
+		waitSettled(t, observer)
+		assert.NoError(t, service.Stop(ctx))
+		assert.Empty(t, service.DeadLetters())
+		createPayment.AssertExpectations(t)
+		createPaymentGateway.AssertExpectations(t)
+	})
+
+	t.Run("Should drop the message when the error is permanent", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		observer := &settledObserver{settled: make(chan struct{}, 1)}
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), createPayment, createPaymentGateway)
+		service.SetObserver(observer)
+		service.Start(ctx)
+
+		// Act
+		_, err := service.Enqueue(ctx, TopicNotification{Message: "not a json"})
+
+		// Assert
+		assert.NoError(t, err)
+		waitSettled(t, observer)
+		assert.NoError(t, service.Stop(ctx))
+		assert.Empty(t, service.DeadLetters())
+		createPayment.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should send a rejected message to the dead letters without retrying it", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, custom_error.ErrPaymentAmountMismatch).
+			Once()
+
+		observer := &settledObserver{settled: make(chan struct{}, 1)}
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), createPayment, createPaymentGateway)
+		service.SetObserver(observer)
+		service.Start(ctx)
+
+		// Act
+		messageId, err := service.Enqueue(ctx, TopicNotification{Message: memoryMessage})
+
+		// Assert
+		assert.NoError(t, err)
+		waitSettled(t, observer)
+		assert.NoError(t, service.Stop(ctx))
+
+		deadLetters := service.DeadLetters()
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, messageId, deadLetters[0].MessageId)
+		assert.Equal(t, 1, deadLetters[0].ReceiveCount)
+		assert.Equal(t, custom_error.ErrPaymentAmountMismatch.Error(), deadLetters[0].Reason)
+		createPayment.AssertExpectations(t)
+	})
+
+	t.Run("Should send the message to the dead letters after the max receive count", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, errors.New("database unavailable")).
+			Twice()
+
+		observer := &settledObserver{settled: make(chan struct{}, 1)}
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), createPayment, createPaymentGateway)
+		service.SetObserver(observer)
+		service.Start(ctx)
+
+		// Act
+		messageId, err := service.Enqueue(ctx, TopicNotification{Message: memoryMessage})
+
+		// Assert
+		assert.NoError(t, err)
+		waitSettled(t, observer)
+		assert.NoError(t, service.Stop(ctx))
+
+		deadLetters := service.DeadLetters()
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, messageId, deadLetters[0].MessageId)
+		assert.Equal(t, 2, deadLetters[0].ReceiveCount)
+		assert.Equal(t, "database unavailable", deadLetters[0].Reason)
+		createPayment.AssertExpectations(t)
+	})
+}
+
+func TestMemoryQueueHeartbeat(t *testing.T) {
+	t.Run("Should return unhealthy when the consumer was not started", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), nil, nil)
+
+		// Act
+		status := service.Heartbeat().Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		assert.Equal(t, ErrConsumerNotStarted.Error(), status.Err)
+	})
+
+	t.Run("Should return healthy when the consumer is running", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryQueueService("test-queue", newMemoryQueueConfig(), nil, nil)
+		service.Start(ctx)
+		defer service.Stop(ctx)
+
+		// Act
+		status := service.Heartbeat().Health(ctx)
+
+		// Assert
+		assert.False(t, status.HasError())
+	})
+}
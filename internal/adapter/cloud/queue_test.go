@@ -3,27 +3,78 @@ package cloud
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/awsdocs/aws-doc-sdk-examples/gov2/testtools"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/create"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var receiveAttributeNames = []types.QueueAttributeName{
+	types.QueueAttributeName(types.MessageSystemAttributeNameApproximateReceiveCount),
+}
+
+func newQueueConfig() *environment.QueueConfig {
+	return &environment.QueueConfig{
+		Concurrency:      10,
+		MaxReceiveCount:  3,
+		BaseBackoff:      10 * time.Second,
+		MaxBackoff:       time.Minute,
+		HeartbeatTimeout: time.Minute,
+	}
+}
+
+func addGetQueueUrlStubs(stubber *testtools.AwsmStubber) {
+	stubber.Add(testtools.Stub{
+		OperationName: "GetQueueUrl",
+		Input: &sqs.GetQueueUrlInput{
+			QueueName: aws.String("test-queue"),
+		},
+		Output: &sqs.GetQueueUrlOutput{
+			QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+		},
+	})
+
+	stubber.Add(testtools.Stub{
+		OperationName: "GetQueueUrl",
+		Input: &sqs.GetQueueUrlInput{
+			QueueName: aws.String("test-dlq"),
+		},
+		Output: &sqs.GetQueueUrlOutput{
+			QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-dlq"),
+		},
+	})
+}
+
+type recordingObserver struct {
+	noopQueueObserver
+
+	deletes []error
+}
+
+func (o *recordingObserver) ObserveDelete(err error) {
+	o.deletes = append(o.deletes, err)
+}
+
 func TestGetQueueName(t *testing.T) {
 	t.Run("Should return queue name", func(t *testing.T) {
 		// Arrange
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", aws.Config{}, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, createPayment, createPaymentGateway)
 
 		// Act
 		queueName := service.GetQueueName()
@@ -49,10 +100,20 @@ func TestUpdateQueueUrl(t *testing.T) {
 			},
 		})
 
+		stubber.Add(testtools.Stub{
+			OperationName: "GetQueueUrl",
+			Input: &sqs.GetQueueUrlInput{
+				QueueName: aws.String("test-dlq"),
+			},
+			Output: &sqs.GetQueueUrlOutput{
+				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-dlq"),
+			},
+		})
+
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway)
 
 		// Act
 		err := service.UpdateQueueUrl(ctx)
@@ -77,7 +138,7 @@ func TestUpdateQueueUrl(t *testing.T) {
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway)
 
 		// Act
 		err := service.UpdateQueueUrl(ctx)
@@ -88,21 +149,13 @@ func TestUpdateQueueUrl(t *testing.T) {
 	})
 }
 
-func TestStartConsuming(t *testing.T) {
+func TestReceiveMessages(t *testing.T) {
 	t.Run("Should start consuming messages", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		stubber := testtools.NewStubber()
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		response := `{
 			"Type" : "Notification",
@@ -119,9 +172,11 @@ func TestStartConsuming(t *testing.T) {
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Output: &sqs.ReceiveMessageOutput{
 				Messages: []types.Message{
@@ -138,7 +193,7 @@ func TestStartConsuming(t *testing.T) {
 			OperationName: "DeleteMessage",
 			Input: &sqs.DeleteMessageInput{
 				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				ReceiptHandle: aws.String("1234567890"),
+				ReceiptHandle: aws.String("1234567891"),
 			},
 			Output: &sqs.DeleteMessageOutput{},
 		})
@@ -154,15 +209,18 @@ func TestStartConsuming(t *testing.T) {
 			Return(nil).
 			Once()
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.receiveMessages(ctx, ctx)
+		service.waitGroup.Wait()
 
 		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, service.slots, 0)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 		createPaymentGateway.AssertExpectations(t)
@@ -175,22 +233,16 @@ func TestStartConsuming(t *testing.T) {
 
 		raiseErr := &testtools.StubError{Err: errors.New("ClientError")}
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Error: raiseErr,
 		})
@@ -198,15 +250,17 @@ func TestStartConsuming(t *testing.T) {
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.receiveMessages(ctx, ctx)
 
 		// Assert
+		testtools.VerifyError(err, raiseErr, t)
+		assert.Len(t, service.slots, 0)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 		createPaymentGateway.AssertExpectations(t)
@@ -217,15 +271,7 @@ func TestStartConsuming(t *testing.T) {
 		ctx := context.Background()
 		stubber := testtools.NewStubber()
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		response := `{
 			"Type" : false,
@@ -242,9 +288,11 @@ func TestStartConsuming(t *testing.T) {
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Output: &sqs.ReceiveMessageOutput{
 				Messages: []types.Message{
@@ -261,7 +309,7 @@ func TestStartConsuming(t *testing.T) {
 			OperationName: "DeleteMessage",
 			Input: &sqs.DeleteMessageInput{
 				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				ReceiptHandle: aws.String("1234567890"),
+				ReceiptHandle: aws.String("1234567891"),
 			},
 			Output: &sqs.DeleteMessageOutput{},
 		})
@@ -269,33 +317,28 @@ func TestStartConsuming(t *testing.T) {
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.receiveMessages(ctx, ctx)
+		service.waitGroup.Wait()
 
 		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, service.slots, 0)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 	})
 
-	t.Run("Should log when message processor returns an error", func(t *testing.T) {
+	t.Run("Should retry the message when the message processor returns an error", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		stubber := testtools.NewStubber()
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		response := `{
 			"Type" : "Notification",
@@ -312,9 +355,11 @@ func TestStartConsuming(t *testing.T) {
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Output: &sqs.ReceiveMessageOutput{
 				Messages: []types.Message{
@@ -328,12 +373,13 @@ func TestStartConsuming(t *testing.T) {
 		})
 
 		stubber.Add(testtools.Stub{
-			OperationName: "DeleteMessage",
-			Input: &sqs.DeleteMessageInput{
-				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				ReceiptHandle: aws.String("1234567890"),
+			OperationName: "ChangeMessageVisibility",
+			Input: &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle:     aws.String("1234567891"),
+				VisibilityTimeout: 10,
 			},
-			Output: &sqs.DeleteMessageOutput{},
+			Output: &sqs.ChangeMessageVisibilityOutput{},
 		})
 
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
@@ -343,34 +389,29 @@ func TestStartConsuming(t *testing.T) {
 			Return(nil, assert.AnError).
 			Once()
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.receiveMessages(ctx, ctx)
+		service.waitGroup.Wait()
 
 		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, service.slots, 0)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 		createPaymentGateway.AssertExpectations(t)
 	})
 
-	t.Run("Should log when payment gateway returns an error", func(t *testing.T) {
+	t.Run("Should retry the message when the payment gateway returns an error", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		stubber := testtools.NewStubber()
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		response := `{
 			"Type" : "Notification",
@@ -387,9 +428,11 @@ func TestStartConsuming(t *testing.T) {
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Output: &sqs.ReceiveMessageOutput{
 				Messages: []types.Message{
@@ -403,12 +446,13 @@ func TestStartConsuming(t *testing.T) {
 		})
 
 		stubber.Add(testtools.Stub{
-			OperationName: "DeleteMessage",
-			Input: &sqs.DeleteMessageInput{
-				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				ReceiptHandle: aws.String("1234567890"),
+			OperationName: "ChangeMessageVisibility",
+			Input: &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle:     aws.String("1234567891"),
+				VisibilityTimeout: 10,
 			},
-			Output: &sqs.DeleteMessageOutput{},
+			Output: &sqs.ChangeMessageVisibilityOutput{},
 		})
 
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
@@ -422,15 +466,18 @@ func TestStartConsuming(t *testing.T) {
 			Return(assert.AnError).
 			Once()
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.receiveMessages(ctx, ctx)
+		service.waitGroup.Wait()
 
 		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, service.slots, 0)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 		createPaymentGateway.AssertExpectations(t)
@@ -441,15 +488,7 @@ func TestStartConsuming(t *testing.T) {
 		ctx := context.Background()
 		stubber := testtools.NewStubber()
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		response := `{
 			"Type" : "AnotherType",
@@ -466,9 +505,11 @@ func TestStartConsuming(t *testing.T) {
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Output: &sqs.ReceiveMessageOutput{
 				Messages: []types.Message{
@@ -493,15 +534,18 @@ func TestStartConsuming(t *testing.T) {
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.receiveMessages(ctx, ctx)
+		service.waitGroup.Wait()
 
 		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, service.slots, 0)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 		createPaymentGateway.AssertExpectations(t)
@@ -512,15 +556,7 @@ func TestStartConsuming(t *testing.T) {
 		ctx := context.Background()
 		stubber := testtools.NewStubber()
 
-		stubber.Add(testtools.Stub{
-			OperationName: "GetQueueUrl",
-			Input: &sqs.GetQueueUrlInput{
-				QueueName: aws.String("test-queue"),
-			},
-			Output: &sqs.GetQueueUrlOutput{
-				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-			},
-		})
+		addGetQueueUrlStubs(stubber)
 
 		response := `{
 			"Type" : "Notification",
@@ -537,9 +573,11 @@ func TestStartConsuming(t *testing.T) {
 		stubber.Add(testtools.Stub{
 			OperationName: "ReceiveMessage",
 			Input: &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   10,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
 			},
 			Output: &sqs.ReceiveMessageOutput{
 				Messages: []types.Message{
@@ -564,17 +602,831 @@ func TestStartConsuming(t *testing.T) {
 		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
 		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
 
-		service := NewQueueService("test-queue", *stubber.SdkConfig, createPayment, createPaymentGateway)
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.receiveMessages(ctx, ctx)
+		service.waitGroup.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, service.slots, 0)
+		testtools.ExitTest(stubber, t)
+		createPayment.AssertExpectations(t)
+		createPaymentGateway.AssertExpectations(t)
+	})
+}
+
+func TestStartStop(t *testing.T) {
+	body := `{"Type":"Notification","Message":"{\"order_id\":\"be6293ff-4ec0-4ed8-95c9-b36ce99aa105\",\"payment_id\":\"a5c81ac9-a549-44c5-bb09-c330116b929f\",\"amount\":59.980000000000004}"}`
+
+	addReceiveStub := func(stubber *testtools.AwsmStubber) {
+		stubber.Add(testtools.Stub{
+			OperationName: "ReceiveMessage",
+			Input: &sqs.ReceiveMessageInput{
+				QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				MaxNumberOfMessages:   1,
+				WaitTimeSeconds:       20,
+				AttributeNames:        receiveAttributeNames,
+				MessageAttributeNames: []string{"All"},
+			},
+			Output: &sqs.ReceiveMessageOutput{
+				Messages: []types.Message{
+					{
+						MessageId:     aws.String("fc8e9ffd-6122-5c52-8fb9-c13e3ee2629a"),
+						Body:          aws.String(body),
+						ReceiptHandle: aws.String("1234567891"),
+					},
+				},
+			},
+		})
+	}
+
+	t.Run("Should do nothing when the service was not started", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil)
+
+		// Act
+		err := service.Stop(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should drain the messages in flight before stopping", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+		addReceiveStub(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DeleteMessage",
+			Input: &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle: aws.String("1234567891"),
+			},
+			Output: &sqs.DeleteMessageOutput{},
+		})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				close(started)
+				<-release
+			}).
+			Return(&payment_entity.Payment{}, nil).
+			Once()
+
+		createPaymentGateway.On("Handle", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+
+		config := newQueueConfig()
+		config.Concurrency = 1
+
+		service := NewQueueService("test-queue", "test-dlq", config, *stubber.SdkConfig, createPayment, createPaymentGateway)
 
 		err := service.UpdateQueueUrl(ctx)
 		assert.NoError(t, err)
 
+		service.Start(ctx)
+		<-started
+
+		stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+		}()
+
 		// Act
-		service.ConsumeMessages(ctx)
+		err = service.Stop(stopCtx)
 
 		// Assert
+		assert.NoError(t, err)
 		testtools.ExitTest(stubber, t)
 		createPayment.AssertExpectations(t)
 		createPaymentGateway.AssertExpectations(t)
 	})
+
+	t.Run("Should release the message of a handler that returns as soon as it is aborted", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+		addReceiveStub(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ChangeMessageVisibility",
+			Input: &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle:     aws.String("1234567891"),
+				VisibilityTimeout: 0,
+			},
+			Output: &sqs.ChangeMessageVisibilityOutput{},
+		})
+
+		started := make(chan struct{})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				close(started)
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(nil, context.Canceled).
+			Once()
+
+		config := newQueueConfig()
+		config.Concurrency = 1
+
+		service := NewQueueService("test-queue", "test-dlq", config, *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		service.Start(ctx)
+		<-started
+
+		stopCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		// Act
+		err = service.Stop(stopCtx)
+		service.waitGroup.Wait()
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, service.inFlight)
+		testtools.ExitTest(stubber, t)
+		createPayment.AssertExpectations(t)
+	})
+
+	t.Run("Should release the message of a handler still blocked after the deadline", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+		addReceiveStub(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ChangeMessageVisibility",
+			Input: &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle:     aws.String("1234567891"),
+				VisibilityTimeout: 0,
+			},
+			Output: &sqs.ChangeMessageVisibilityOutput{},
+		})
+
+		started := make(chan struct{})
+		unblock := make(chan struct{})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				close(started)
+				<-unblock
+			}).
+			Return(nil, assert.AnError).
+			Once()
+
+		config := newQueueConfig()
+		config.Concurrency = 1
+
+		service := NewQueueService("test-queue", "test-dlq", config, *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		service.Start(ctx)
+		<-started
+
+		stopCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		// Act
+		err = service.Stop(stopCtx)
+
+		close(unblock)
+		service.waitGroup.Wait()
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, service.inFlight)
+		testtools.ExitTest(stubber, t)
+		createPayment.AssertExpectations(t)
+	})
+}
+
+func TestAcquireSlots(t *testing.T) {
+	t.Run("Should receive only as many messages as there are free workers", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		config := newQueueConfig()
+		config.Concurrency = 3
+
+		service := NewQueueService("test-queue", "test-dlq", config, aws.Config{}, nil, nil).(*AwsSqsService)
+
+		// Act
+		first := service.acquireSlots(ctx)
+		service.releaseSlots(1)
+		second := service.acquireSlots(ctx)
+
+		// Assert
+		assert.Equal(t, int32(3), first)
+		assert.Equal(t, int32(1), second)
+	})
+
+	t.Run("Should not take more slots than a receive can return", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		config := newQueueConfig()
+		config.Concurrency = 50
+
+		service := NewQueueService("test-queue", "test-dlq", config, aws.Config{}, nil, nil).(*AwsSqsService)
+
+		// Act
+		slots := service.acquireSlots(ctx)
+
+		// Assert
+		assert.Equal(t, maxReceiveMessages, slots)
+	})
+
+	t.Run("Should return when the context is done while every worker is busy", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+
+		config := newQueueConfig()
+		config.Concurrency = 1
+
+		service := NewQueueService("test-queue", "test-dlq", config, aws.Config{}, nil, nil).(*AwsSqsService)
+
+		busy := service.acquireSlots(ctx)
+		cancel()
+
+		// Act
+		slots := service.acquireSlots(ctx)
+
+		// Assert
+		assert.Equal(t, int32(1), busy)
+		assert.Zero(t, slots)
+	})
+}
+
+func TestParseMessage(t *testing.T) {
+	t.Run("Should return the request of the notification", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		body := `{"Type":"Notification","Message":"{\"order_id\":\"be6293ff-4ec0-4ed8-95c9-b36ce99aa105\",\"payment_id\":\"a5c81ac9-a549-44c5-bb09-c330116b929f\",\"amount\":59.980000000000004}"}`
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil).(*AwsSqsService)
+
+		// Act
+		request, err := service.parseMessage(ctx, types.Message{Body: aws.String(body)})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "be6293ff-4ec0-4ed8-95c9-b36ce99aa105", request.OrderId)
+		assert.Equal(t, "a5c81ac9-a549-44c5-bb09-c330116b929f", request.PaymentId)
+		assert.Equal(t, money.New(5998, money.BRL), request.Amount)
+	})
+
+	t.Run("Should return a permanent error when the message is not valid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil).(*AwsSqsService)
+
+		// Act
+		_, err := service.parseMessage(ctx, types.Message{Body: aws.String("not a json")})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrQueueMessageNotValid)
+		assert.True(t, isPermanentError(err))
+	})
+}
+
+func TestHandleRequest(t *testing.T) {
+	t.Run("Should create the charge when the payment already exists", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		request := create.CreatePaymentDTO{
+			OrderId:   "be6293ff-4ec0-4ed8-95c9-b36ce99aa105",
+			PaymentId: "a5c81ac9-a549-44c5-bb09-c330116b929f",
+			Amount:    money.New(5998, money.BRL),
+		}
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		createPayment.On("Handle", mock.Anything, request).
+			Return(nil, custom_error.ErrPaymentAlreadyExists).
+			Once()
+
+		createPaymentGateway.On("Handle", mock.Anything, gateway.CreatePaymentGatewayDTO{
+			PaymentID: "a5c81ac9-a549-44c5-bb09-c330116b929f",
+			Amount:    money.New(5998, money.BRL),
+		}).
+			Return(nil).
+			Once()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		// Act
+		err := service.handleRequest(ctx, request)
+
+		// Assert
+		assert.NoError(t, err)
+		createPayment.AssertExpectations(t)
+		createPaymentGateway.AssertExpectations(t)
+	})
+}
+
+func TestSettleMessage(t *testing.T) {
+	t.Run("Should acknowledge the message when the error is permanent", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DeleteMessage",
+			Input: &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle: aws.String("1234567891"),
+			},
+			Output: &sqs.DeleteMessageOutput{},
+		})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.settleMessage(ctx, types.Message{
+			ReceiptHandle: aws.String("1234567891"),
+		}, custom_error.ErrPaymentAlreadyExists)
+
+		// Assert
+		assert.NoError(t, err)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should send a rejected message to the dead-letter queue on its first receive", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "SendMessage",
+			Input: &sqs.SendMessageInput{
+				QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-dlq"),
+				MessageBody: aws.String("body"),
+				MessageAttributes: map[string]types.MessageAttributeValue{
+					"reason": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String(custom_error.ErrPaymentAmountMismatch.Error()),
+					},
+					"source_queue": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String("test-queue"),
+					},
+					"source_message_id": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String("123"),
+					},
+					"receive_count": {
+						DataType:    aws.String("Number"),
+						StringValue: aws.String("1"),
+					},
+				},
+			},
+			Output: &sqs.SendMessageOutput{},
+		})
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DeleteMessage",
+			Input: &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle: aws.String("1234567891"),
+			},
+			Output: &sqs.DeleteMessageOutput{},
+		})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.settleMessage(ctx, types.Message{
+			MessageId:     aws.String("123"),
+			Body:          aws.String("body"),
+			ReceiptHandle: aws.String("1234567891"),
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": "1",
+			},
+		}, custom_error.ErrPaymentAmountMismatch)
+
+		// Assert
+		assert.NoError(t, err)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should notify the observer of the delete outcome", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DeleteMessage",
+			Input: &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle: aws.String("1234567891"),
+			},
+			Error: &testtools.StubError{Err: errors.New("delete failed")},
+		})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		observer := &recordingObserver{}
+		service.SetObserver(observer)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.settleMessage(ctx, types.Message{
+			ReceiptHandle: aws.String("1234567891"),
+		}, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Len(t, observer.deletes, 1)
+		assert.Error(t, observer.deletes[0])
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should increase the visibility timeout for each receive", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ChangeMessageVisibility",
+			Input: &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle:     aws.String("1234567891"),
+				VisibilityTimeout: 20,
+			},
+			Output: &sqs.ChangeMessageVisibilityOutput{},
+		})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.settleMessage(ctx, types.Message{
+			ReceiptHandle: aws.String("1234567891"),
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": "2",
+			},
+		}, assert.AnError)
+
+		// Assert
+		assert.NoError(t, err)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should send the message to the dead-letter queue when the max receive count is reached", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		addGetQueueUrlStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "SendMessage",
+			Input: &sqs.SendMessageInput{
+				QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-dlq"),
+				MessageBody: aws.String("body"),
+				MessageAttributes: map[string]types.MessageAttributeValue{
+					"reason": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String(assert.AnError.Error()),
+					},
+					"source_queue": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String("test-queue"),
+					},
+					"source_message_id": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String("123"),
+					},
+					"receive_count": {
+						DataType:    aws.String("Number"),
+						StringValue: aws.String("3"),
+					},
+				},
+			},
+			Output: &sqs.SendMessageOutput{},
+		})
+
+		stubber.Add(testtools.Stub{
+			OperationName: "DeleteMessage",
+			Input: &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				ReceiptHandle: aws.String("1234567891"),
+			},
+			Output: &sqs.DeleteMessageOutput{},
+		})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.settleMessage(ctx, types.Message{
+			MessageId:     aws.String("123"),
+			Body:          aws.String("body"),
+			ReceiptHandle: aws.String("1234567891"),
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": "3",
+			},
+		}, assert.AnError)
+
+		// Assert
+		assert.NoError(t, err)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should keep the message when it cannot be sent to the dead-letter queue", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		raiseErr := &testtools.StubError{Err: errors.New("ClientError")}
+
+		addGetQueueUrlStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "SendMessage",
+			Error:         raiseErr,
+		})
+
+		createPayment := mocks.NewMockCreatePaymentService[create.CreatePaymentDTO](t)
+		createPaymentGateway := mocks.NewMockCreatePaymentGatewayService[gateway.CreatePaymentGatewayDTO](t)
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, createPayment, createPaymentGateway).(*AwsSqsService)
+
+		err := service.UpdateQueueUrl(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		err = service.settleMessage(ctx, types.Message{
+			MessageId:     aws.String("123"),
+			Body:          aws.String("body"),
+			ReceiptHandle: aws.String("1234567891"),
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": "5",
+			},
+		}, assert.AnError)
+
+		// Assert
+		testtools.VerifyError(err, raiseErr, t)
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Should double the backoff for each receive up to the maximum", func(t *testing.T) {
+		// Arrange
+		service := &AwsSqsService{
+			config: newQueueConfig(),
+		}
+
+		// Act & Assert
+		assert.Equal(t, 10*time.Second, service.backoff(1))
+		assert.Equal(t, 20*time.Second, service.backoff(2))
+		assert.Equal(t, 40*time.Second, service.backoff(3))
+		assert.Equal(t, time.Minute, service.backoff(4))
+		assert.Equal(t, time.Minute, service.backoff(100))
+	})
+}
+
+func TestIsPermanentError(t *testing.T) {
+	t.Run("Should classify the errors", func(t *testing.T) {
+		// Arrange
+		cases := []struct {
+			err      error
+			expected bool
+		}{
+			{custom_error.ErrRequestNotValid, false},
+			{custom_error.ErrPaymentAmountMismatch, false},
+			{custom_error.ErrPaymentAlreadyExists, true},
+			{custom_error.ErrQueueMessageNotValid, true},
+			{custom_error.ErrGatewayRequestNotValid, true},
+			{custom_error.ErrGatewayUnavailable, false},
+			{custom_error.ErrGatewayUnauthorized, false},
+			{assert.AnError, false},
+		}
+
+		for _, c := range cases {
+			// Act
+			res := isPermanentError(c.err)
+
+			// Assert
+			assert.Equal(t, c.expected, res, c.err.Error())
+		}
+	})
+}
+
+func TestIsRejectedError(t *testing.T) {
+	t.Run("Should classify the errors", func(t *testing.T) {
+		// Arrange
+		cases := []struct {
+			err      error
+			expected bool
+		}{
+			{custom_error.ErrRequestNotValid, true},
+			{custom_error.ErrPaymentAmountMismatch, true},
+			{fmt.Errorf("%w: amount is 1.00 but the items sum 2.00", custom_error.ErrPaymentAmountMismatch), true},
+			{custom_error.ErrQueueMessageNotValid, false},
+			{custom_error.ErrPaymentAlreadyExists, false},
+			{assert.AnError, false},
+		}
+
+		for _, c := range cases {
+			// Act
+			res := isRejectedError(c.err)
+
+			// Assert
+			assert.Equal(t, c.expected, res, c.err.Error())
+		}
+	})
+}
+
+func TestQueueHealth(t *testing.T) {
+	t.Run("Should return unhealthy when queue url is not resolved", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil)
+
+		// Act
+		status := service.Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		assert.Equal(t, ErrQueueNotResolved.Error(), status.Err)
+	})
+
+	t.Run("Should return healthy when queue attributes are returned", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetQueueAttributes",
+			Input: &sqs.GetQueueAttributesInput{
+				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				AttributeNames: []types.QueueAttributeName{
+					types.QueueAttributeNameQueueArn,
+				},
+			},
+			Output: &sqs.GetQueueAttributesOutput{},
+		})
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, nil, nil).(*AwsSqsService)
+		service.queueUrl = "https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"
+
+		// Act
+		status := service.Health(ctx)
+
+		// Assert
+		assert.False(t, status.HasError())
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return unhealthy when queue attributes fail", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := testtools.NewStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetQueueAttributes",
+			Input: &sqs.GetQueueAttributesInput{
+				QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+				AttributeNames: []types.QueueAttributeName{
+					types.QueueAttributeNameQueueArn,
+				},
+			},
+			Error: &testtools.StubError{Err: errors.New("ClientError")},
+		})
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), *stubber.SdkConfig, nil, nil).(*AwsSqsService)
+		service.queueUrl = "https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"
+
+		// Act
+		status := service.Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestQueueHeartbeat(t *testing.T) {
+	t.Run("Should return unhealthy when the consumer was not started", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil)
+
+		// Act
+		status := service.Heartbeat().Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		assert.Equal(t, ErrConsumerNotStarted.Error(), status.Err)
+	})
+
+	t.Run("Should return healthy when the consumer polled recently", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil).(*AwsSqsService)
+		service.beat()
+
+		// Act
+		status := service.Heartbeat().Health(ctx)
+
+		// Assert
+		assert.False(t, status.HasError())
+	})
+
+	t.Run("Should return unhealthy when the consumer stopped polling", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewQueueService("test-queue", "test-dlq", newQueueConfig(), aws.Config{}, nil, nil).(*AwsSqsService)
+		service.lastPoll.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+		// Act
+		status := service.Heartbeat().Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		assert.Contains(t, status.Err, ErrConsumerStalled.Error())
+	})
 }
@@ -2,12 +2,26 @@ package cloud
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
+)
+
+var (
+	ErrTopicNotResolved = errors.New("topic arn not resolved")
+	ErrInvalidMessage   = errors.New("message is not a valid json")
 )
 
 type TopicService interface {
 	GetTopicName() string
 	UpdateTopicArn(ctx context.Context) error
-	PublishMessage(ctx context.Context, message interface{}) (*string, error)
+	// PublishMessage publishes a contract already encoded, as stored in the outbox, the
+	// deduplication id is the outbox message id, so only its retries are deduplicated
+	PublishMessage(ctx context.Context, deduplicationId string, payload json.RawMessage) (*string, error)
+
+	// Health checks the topic can be reached
+	health.HealthCheck
 }
 
 type TopicNotification struct {
@@ -20,4 +34,11 @@ type TopicNotification struct {
 	Signature        string `json:"Signature"`
 	SigningCertURL   string `json:"SigningCertURL"`
 	UnsubscribeURL   string `json:"UnsubscribeURL"`
+
+	MessageAttributes map[string]TopicNotificationAttribute `json:"MessageAttributes,omitempty"`
+}
+
+type TopicNotificationAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
 }
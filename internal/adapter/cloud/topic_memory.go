@@ -0,0 +1,100 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
+)
+
+// PublishedMessage is a message recorded by the in-memory topic
+type PublishedMessage struct {
+	MessageId   string            `json:"message_id"`
+	TopicName   string            `json:"topic_name"`
+	Message     json.RawMessage   `json:"message"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	PublishedAt time.Time         `json:"published_at"`
+}
+
+// MemoryTopicService replaces SNS for local development and tests, it only records
+// the messages published so they can be inspected
+type MemoryTopicService struct {
+	topicName string
+
+	messages      []PublishedMessage
+	messagesMutex sync.Mutex
+}
+
+func NewMemoryTopicService(topicName string) *MemoryTopicService {
+	return &MemoryTopicService{
+		topicName: topicName,
+	}
+}
+
+func (s *MemoryTopicService) GetTopicName() string {
+	return s.topicName
+}
+
+func (s *MemoryTopicService) UpdateTopicArn(ctx context.Context) error {
+	return nil
+}
+
+func (s *MemoryTopicService) Health(ctx context.Context) *health.HealthStatus {
+	return health.Healthy()
+}
+
+func (s *MemoryTopicService) PublishMessage(ctx context.Context, deduplicationId string, payload json.RawMessage) (*string, error) {
+	ctx, span, attributes := startPublishSpan(ctx, s.topicName)
+	defer span.End()
+
+	// the message is served as is by the admin route, so it must be a valid json
+	if !json.Valid(payload) {
+		recordSpanError(span, ErrInvalidMessage)
+		return nil, ErrInvalidMessage
+	}
+
+	published := PublishedMessage{
+		MessageId:   uuid.NewString(),
+		TopicName:   s.topicName,
+		Message:     append(json.RawMessage(nil), payload...),
+		PublishedAt: time.Now(),
+	}
+
+	if len(attributes) > 0 {
+		published.Attributes = make(map[string]string, len(attributes))
+
+		for key, value := range attributes {
+			published.Attributes[key] = aws.ToString(value.StringValue)
+		}
+	}
+
+	s.messagesMutex.Lock()
+	s.messages = append(s.messages, published)
+	s.messagesMutex.Unlock()
+
+	span.SetAttributes(messageIdAttribute(&published.MessageId))
+
+	slog.InfoContext(ctx, "message published", "topic", s.topicName, "message_id", published.MessageId, "message", string(payload))
+
+	return &published.MessageId, nil
+}
+
+// Messages returns the messages published so far, oldest first
+func (s *MemoryTopicService) Messages() []PublishedMessage {
+	s.messagesMutex.Lock()
+	defer s.messagesMutex.Unlock()
+
+	return append([]PublishedMessage(nil), s.messages...)
+}
+
+func (s *MemoryTopicService) Clear() {
+	s.messagesMutex.Lock()
+	defer s.messagesMutex.Unlock()
+
+	s.messages = nil
+}
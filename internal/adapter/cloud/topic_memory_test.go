@@ -0,0 +1,65 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTopicPublishMessage(t *testing.T) {
+	t.Run("Should record the message published", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryTopicService("test-topic")
+
+		// Act
+		messageId, err := service.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, messageId)
+
+		messages := service.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, *messageId, messages[0].MessageId)
+		assert.Equal(t, "test-topic", messages[0].TopicName)
+		assert.JSONEq(t, `{"order_id":"order_id"}`, string(messages[0].Message))
+		assert.False(t, messages[0].PublishedAt.IsZero())
+	})
+
+	t.Run("Should return an error when the message is not a valid json", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryTopicService("test-topic")
+
+		// Act
+		messageId, err := service.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id":`))
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidMessage)
+		assert.Nil(t, messageId)
+		assert.Empty(t, service.Messages())
+	})
+}
+
+func TestMemoryTopicClear(t *testing.T) {
+	t.Run("Should forget the messages published", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		service := NewMemoryTopicService("test-topic")
+
+		_, err := service.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		assert.NoError(t, err)
+
+		// Act
+		service.Clear()
+
+		// Assert
+		assert.Empty(t, service.Messages())
+	})
+}
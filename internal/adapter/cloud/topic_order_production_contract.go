@@ -2,6 +2,11 @@ package cloud
 
 import "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
 
+const (
+	OrderProductionEventType     = "OrderProductionRequested"
+	OrderProductionSchemaVersion = "1"
+)
+
 type OrderProductionTopicItemContract struct {
 	Id       string `json:"id"`
 	Name     string `json:"name"`
@@ -13,6 +18,18 @@ type OrderProductionTopicContract struct {
 	Items   []OrderProductionTopicItemContract `json:"items"`
 }
 
+func (c OrderProductionTopicContract) GetEventType() string {
+	return OrderProductionEventType
+}
+
+func (c OrderProductionTopicContract) GetSchemaVersion() string {
+	return OrderProductionSchemaVersion
+}
+
+func (c OrderProductionTopicContract) GetOrderId() string {
+	return c.OrderId
+}
+
 func NewOrderProductionContractFromPayment(payment *payment_entity.Payment) *OrderProductionTopicContract {
 	items := make([]OrderProductionTopicItemContract, len(payment.Items))
 
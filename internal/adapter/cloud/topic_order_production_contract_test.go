@@ -0,0 +1,60 @@
+package cloud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrderProductionContractFromPayment(t *testing.T) {
+	t.Run("Should write the items of the order", func(t *testing.T) {
+		// Arrange
+		payment := payment_entity.Payment{
+			OrderId: "order_id",
+			Items: []payment_entity.PaymentItem{
+				{
+					Id:       "item_id",
+					Name:     "item_name",
+					Quantity: 2,
+				},
+			},
+		}
+
+		// Act
+		data, err := json.Marshal(NewOrderProductionContractFromPayment(&payment))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"order_id": "order_id",
+			"items": [
+				{
+					"id": "item_id",
+					"name": "item_name",
+					"quantity": 2
+				}
+			]
+		}`, string(data))
+	})
+}
+
+func TestOrderProductionTopicContract(t *testing.T) {
+	t.Run("Should describe the production of the order", func(t *testing.T) {
+		// Arrange
+		contract := OrderProductionTopicContract{
+			OrderId: "order_id",
+		}
+
+		// Act
+		eventType := contract.GetEventType()
+		schemaVersion := contract.GetSchemaVersion()
+		orderId := contract.GetOrderId()
+
+		// Assert
+		assert.Equal(t, OrderProductionEventType, eventType)
+		assert.Equal(t, OrderProductionSchemaVersion, schemaVersion)
+		assert.Equal(t, "order_id", orderId)
+	})
+}
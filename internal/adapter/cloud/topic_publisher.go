@@ -0,0 +1,273 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	EventTypeAttribute     = "event_type"
+	SchemaVersionAttribute = "schema_version"
+	OrderIdAttribute       = "order_id"
+
+	fifoTopicSuffix = ".fifo"
+
+	// maxBatchEntries is the most messages SNS accepts in a single PublishBatch
+	maxBatchEntries = 10
+)
+
+var (
+	ErrPublishBatchFailed     = errors.New("messages of the batch not published")
+	ErrDeduplicationIdMissing = errors.New("message deduplication id is missing")
+)
+
+// TopicContract is a message published to a topic, described to the subscribers
+// by the attributes of the SNS message so they can filter it without reading its body
+type TopicContract interface {
+	GetEventType() string
+	GetSchemaVersion() string
+	GetOrderId() string
+}
+
+// OutgoingMessage is a contract along with the id that deduplicates it in a FIFO topic, a
+// message published again with the same id within the deduplication interval is dropped,
+// while messages with the same content and different ids are all delivered
+type OutgoingMessage[T TopicContract] struct {
+	DeduplicationId string
+	Contract        T
+}
+
+// TopicPublisher publishes the contracts of a single type to a topic, the messages of
+// a FIFO topic are grouped by order, so the updates of an order are delivered in order
+type TopicPublisher[T TopicContract] struct {
+	topicName string
+	fifo      bool
+
+	client   *sns.Client
+	resolver *TopicResolver
+}
+
+func NewTopicPublisher[T TopicContract](topicName string, config aws.Config) *TopicPublisher[T] {
+	return &TopicPublisher[T]{
+		topicName: topicName,
+		fifo:      strings.HasSuffix(topicName, fifoTopicSuffix),
+
+		client:   sns.NewFromConfig(config),
+		resolver: NewTopicResolver(config),
+	}
+}
+
+func (p *TopicPublisher[T]) GetTopicName() string {
+	return p.topicName
+}
+
+func (p *TopicPublisher[T]) UpdateTopicArn(ctx context.Context) error {
+	_, err := p.resolver.Resolve(ctx, p.topicName)
+
+	return err
+}
+
+func (p *TopicPublisher[T]) Health(ctx context.Context) *health.HealthStatus {
+	topicArn, ok := p.resolver.Cached(p.topicName)
+	if !ok {
+		return health.Unhealthy(ErrTopicNotResolved)
+	}
+
+	_, err := p.client.GetTopicAttributes(ctx, &sns.GetTopicAttributesInput{
+		TopicArn: aws.String(topicArn),
+	})
+	if err != nil {
+		return health.Unhealthy(err)
+	}
+
+	return health.Healthy()
+}
+
+func (p *TopicPublisher[T]) Publish(ctx context.Context, message OutgoingMessage[T]) (*string, error) {
+	body, err := json.Marshal(message.Contract)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.publish(ctx, message.DeduplicationId, message.Contract, body)
+}
+
+// PublishMessage publishes the payload as is, it is only decoded to describe the message
+func (p *TopicPublisher[T]) PublishMessage(ctx context.Context, deduplicationId string, payload json.RawMessage) (*string, error) {
+	var message T
+
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+	}
+
+	return p.publish(ctx, deduplicationId, message, payload)
+}
+
+// PublishBatch publishes the messages in batches of up to ten, the ids returned follow the
+// order of the messages and are empty for the ones not published, the first batch that
+// fails stops the publication
+func (p *TopicPublisher[T]) PublishBatch(ctx context.Context, messages []OutgoingMessage[T]) ([]string, error) {
+	ctx, span, traceAttributes := startPublishSpan(ctx, p.topicName)
+	defer span.End()
+
+	span.SetAttributes(semconv.MessagingBatchMessageCount(len(messages)))
+
+	messageIds := make([]string, len(messages))
+
+	for start := 0; start < len(messages); start += maxBatchEntries {
+		end := min(start+maxBatchEntries, len(messages))
+
+		if err := p.publishBatch(ctx, messages[start:end], messageIds[start:end], traceAttributes); err != nil {
+			recordSpanError(span, err)
+			return messageIds, err
+		}
+	}
+
+	slog.InfoContext(ctx, "batch published", "topic", p.topicName, "messages", len(messages))
+
+	return messageIds, nil
+}
+
+func (p *TopicPublisher[T]) publish(ctx context.Context, deduplicationId string, message T, body []byte) (*string, error) {
+	ctx, span, traceAttributes := startPublishSpan(ctx, p.topicName)
+	defer span.End()
+
+	input := &sns.PublishInput{
+		Message:           aws.String(string(body)),
+		MessageAttributes: p.messageAttributes(message, traceAttributes),
+	}
+
+	if p.fifo {
+		if deduplicationId == "" {
+			recordSpanError(span, ErrDeduplicationIdMissing)
+			return nil, ErrDeduplicationIdMissing
+		}
+
+		input.MessageGroupId = aws.String(message.GetOrderId())
+		input.MessageDeduplicationId = aws.String(deduplicationId)
+	}
+
+	var output *sns.PublishOutput
+
+	err := p.withTopicArn(ctx, func(topicArn string) (err error) {
+		input.TopicArn = aws.String(topicArn)
+
+		output, err = p.client.Publish(ctx, input)
+
+		return err
+	})
+	if err != nil {
+		recordSpanError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(messageIdAttribute(output.MessageId))
+
+	slog.InfoContext(ctx, "message published", "topic", p.topicName, "message_id", aws.ToString(output.MessageId), "message", string(body))
+
+	return output.MessageId, nil
+}
+
+// publishBatch publishes a single batch, writing the id of each message published into messageIds
+func (p *TopicPublisher[T]) publishBatch(ctx context.Context, messages []OutgoingMessage[T], messageIds []string, traceAttributes map[string]types.MessageAttributeValue) error {
+	entries := make([]types.PublishBatchRequestEntry, len(messages))
+
+	for i, message := range messages {
+		body, err := json.Marshal(message.Contract)
+		if err != nil {
+			return err
+		}
+
+		entries[i] = types.PublishBatchRequestEntry{
+			Id:                aws.String(strconv.Itoa(i)),
+			Message:           aws.String(string(body)),
+			MessageAttributes: p.messageAttributes(message.Contract, traceAttributes),
+		}
+
+		if p.fifo {
+			if message.DeduplicationId == "" {
+				return ErrDeduplicationIdMissing
+			}
+
+			entries[i].MessageGroupId = aws.String(message.Contract.GetOrderId())
+			entries[i].MessageDeduplicationId = aws.String(message.DeduplicationId)
+		}
+	}
+
+	var output *sns.PublishBatchOutput
+
+	err := p.withTopicArn(ctx, func(topicArn string) (err error) {
+		output, err = p.client.PublishBatch(ctx, &sns.PublishBatchInput{
+			TopicArn:                   aws.String(topicArn),
+			PublishBatchRequestEntries: entries,
+		})
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range output.Successful {
+		if i, err := strconv.Atoi(aws.ToString(entry.Id)); err == nil && i < len(messageIds) {
+			messageIds[i] = aws.ToString(entry.MessageId)
+		}
+	}
+
+	if len(output.Failed) > 0 {
+		failed := output.Failed[0]
+
+		return fmt.Errorf("%w: %d of %d, %s: %s", ErrPublishBatchFailed, len(output.Failed), len(entries), aws.ToString(failed.Code), aws.ToString(failed.Message))
+	}
+
+	return nil
+}
+
+// withTopicArn calls fn with the ARN resolved for the topic, when the topic is not found
+// its ARN is looked up again, as it may have been recreated, and fn is retried once
+func (p *TopicPublisher[T]) withTopicArn(ctx context.Context, fn func(topicArn string) error) error {
+	topicArn, err := p.resolver.Resolve(ctx, p.topicName)
+	if err != nil {
+		return err
+	}
+
+	err = fn(topicArn)
+	if err == nil || !isTopicNotFound(err) || !p.resolver.Invalidate(p.topicName) {
+		return err
+	}
+
+	slog.WarnContext(ctx, "topic not found, resolving it again", "topic", p.topicName, "topic_arn", topicArn)
+
+	topicArn, err = p.resolver.Resolve(ctx, p.topicName)
+	if err != nil {
+		return err
+	}
+
+	return fn(topicArn)
+}
+
+// messageAttributes describes the message alongside the trace context of the publication
+func (p *TopicPublisher[T]) messageAttributes(message T, traceAttributes map[string]types.MessageAttributeValue) map[string]types.MessageAttributeValue {
+	attributes := make(snsAttributesCarrier, len(traceAttributes)+3)
+
+	for key, value := range traceAttributes {
+		attributes[key] = value
+	}
+
+	attributes.Set(EventTypeAttribute, message.GetEventType())
+	attributes.Set(SchemaVersionAttribute, message.GetSchemaVersion())
+	attributes.Set(OrderIdAttribute, message.GetOrderId())
+
+	return attributes
+}
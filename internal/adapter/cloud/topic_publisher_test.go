@@ -0,0 +1,600 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/awsdocs/aws-doc-sdk-examples/gov2/testtools"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type testContract struct {
+	OrderId string `json:"order_id"`
+}
+
+func (c testContract) GetEventType() string {
+	return "TestEvent"
+}
+
+func (c testContract) GetSchemaVersion() string {
+	return "1"
+}
+
+func (c testContract) GetOrderId() string {
+	return c.OrderId
+}
+
+func testContractAttributes(orderId string) map[string]types.MessageAttributeValue {
+	return map[string]types.MessageAttributeValue{
+		EventTypeAttribute: {
+			DataType:    aws.String("String"),
+			StringValue: aws.String("TestEvent"),
+		},
+		SchemaVersionAttribute: {
+			DataType:    aws.String("String"),
+			StringValue: aws.String("1"),
+		},
+		OrderIdAttribute: {
+			DataType:    aws.String("String"),
+			StringValue: aws.String(orderId),
+		},
+	}
+}
+
+func testOutgoingMessage(orderId string) OutgoingMessage[testContract] {
+	return OutgoingMessage[testContract]{
+		DeduplicationId: "outbox_" + orderId,
+		Contract:        testContract{OrderId: orderId},
+	}
+}
+
+func testPublishInput() *sns.PublishInput {
+	return &sns.PublishInput{
+		TopicArn:          aws.String(testTopicArn),
+		Message:           aws.String(`{"order_id":"order_id"}`),
+		MessageAttributes: testContractAttributes("order_id"),
+	}
+}
+
+func TestTopicPublisherGetTopicName(t *testing.T) {
+	t.Run("Should return topic name", func(t *testing.T) {
+		// Arrange
+		publisher := NewTopicPublisher[testContract]("test-topic", aws.Config{})
+
+		// Act
+		topicName := publisher.GetTopicName()
+
+		// Assert
+		assert.Equal(t, "test-topic", topicName)
+	})
+}
+
+func TestTopicPublisherUpdateTopicArn(t *testing.T) {
+	t.Run("Should return nil when topic is found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addResolveTopicStubs(stubber)
+
+		publisher := NewTopicPublisher[testContract]("test-topic", *stubber.SdkConfig)
+
+		// Act
+		err := publisher.UpdateTopicArn(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when topic is not found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addCallerIdentityStub(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetTopicAttributes",
+			Input: &sns.GetTopicAttributesInput{
+				TopicArn: aws.String(testTopicArn),
+			},
+			Error: &testtools.StubError{Err: &types.NotFoundException{}},
+		})
+
+		publisher := NewTopicPublisher[testContract]("test-topic", *stubber.SdkConfig)
+
+		// Act
+		err := publisher.UpdateTopicArn(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrTopicNotFound)
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestTopicPublisherPublish(t *testing.T) {
+	t.Run("Should publish the message with its attributes", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addResolveTopicStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "Publish",
+			Input:         testPublishInput(),
+			Output: &sns.PublishOutput{
+				MessageId: aws.String("1234"),
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract]("test-topic", *stubber.SdkConfig)
+
+		// Act
+		resp, err := publisher.Publish(ctx, testOutgoingMessage("order_id"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", *resp)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should group the messages by order when the topic is fifo", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		topicArn := testTopicArn + ".fifo"
+
+		stubber.Add(testtools.Stub{
+			OperationName: "Publish",
+			Input: &sns.PublishInput{
+				TopicArn:               aws.String(topicArn),
+				Message:                aws.String(`{"order_id":"order_id"}`),
+				MessageAttributes:      testContractAttributes("order_id"),
+				MessageGroupId:         aws.String("order_id"),
+				MessageDeduplicationId: aws.String("outbox_order_id"),
+			},
+			Output: &sns.PublishOutput{
+				MessageId: aws.String("1234"),
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract](topicArn, *stubber.SdkConfig)
+
+		// Act
+		resp, err := publisher.Publish(ctx, testOutgoingMessage("order_id"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", *resp)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should resolve the topic again when it is not found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addResolveTopicStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "Publish",
+			Input:         testPublishInput(),
+			Error:         &testtools.StubError{Err: &types.NotFoundException{}, ContinueAfter: true},
+		})
+
+		addResolveTopicStubs(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "Publish",
+			Input:         testPublishInput(),
+			Output: &sns.PublishOutput{
+				MessageId: aws.String("1234"),
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract]("test-topic", *stubber.SdkConfig)
+
+		err := publisher.UpdateTopicArn(ctx)
+		assert.NoError(t, err)
+
+		// Act
+		resp, err := publisher.Publish(ctx, testOutgoingMessage("order_id"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", *resp)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when message is not published", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addResolveTopicStubs(stubber)
+
+		raiseErr := &testtools.StubError{Err: errors.New("ClientError")}
+
+		stubber.Add(testtools.Stub{
+			OperationName: "Publish",
+			Input:         testPublishInput(),
+			Error:         raiseErr,
+		})
+
+		publisher := NewTopicPublisher[testContract]("test-topic", *stubber.SdkConfig)
+
+		// Act
+		resp, err := publisher.Publish(ctx, testOutgoingMessage("order_id"))
+
+		// Assert
+		assert.Nil(t, resp)
+		testtools.VerifyError(err, raiseErr, t)
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestTopicPublisherPublishMessage(t *testing.T) {
+	t.Run("Should publish the payload as is", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		payload := `{"order_id": "order_id", "extra": true}`
+
+		stubber.Add(testtools.Stub{
+			OperationName: "Publish",
+			Input: &sns.PublishInput{
+				TopicArn:          aws.String(testTopicArn),
+				Message:           aws.String(payload),
+				MessageAttributes: testContractAttributes("order_id"),
+			},
+			Output: &sns.PublishOutput{
+				MessageId: aws.String("1234"),
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		resp, err := publisher.PublishMessage(ctx, "outbox_id", json.RawMessage(payload))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", *resp)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should not deduplicate the same payload published by different messages", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		topicArn := testTopicArn + ".fifo"
+		payload := `{"order_id":"order_id"}`
+
+		for i, deduplicationId := range []string{"outbox_1", "outbox_2"} {
+			stubber.Add(testtools.Stub{
+				OperationName: "Publish",
+				Input: &sns.PublishInput{
+					TopicArn:               aws.String(topicArn),
+					Message:                aws.String(payload),
+					MessageAttributes:      testContractAttributes("order_id"),
+					MessageGroupId:         aws.String("order_id"),
+					MessageDeduplicationId: aws.String(deduplicationId),
+				},
+				Output: &sns.PublishOutput{
+					MessageId: aws.String("message_" + strconv.Itoa(i)),
+				},
+			})
+		}
+
+		publisher := NewTopicPublisher[testContract](topicArn, *stubber.SdkConfig)
+
+		// Act
+		first, errFirst := publisher.PublishMessage(ctx, "outbox_1", json.RawMessage(payload))
+		second, errSecond := publisher.PublishMessage(ctx, "outbox_2", json.RawMessage(payload))
+
+		// Assert
+		assert.NoError(t, errFirst)
+		assert.NoError(t, errSecond)
+		assert.Equal(t, "message_0", *first)
+		assert.Equal(t, "message_1", *second)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when the topic is fifo and the deduplication id is missing", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		publisher := NewTopicPublisher[testContract](testTopicArn+".fifo", *stubber.SdkConfig)
+
+		// Act
+		resp, err := publisher.PublishMessage(ctx, "", json.RawMessage(`{"order_id":"order_id"}`))
+
+		// Assert
+		assert.ErrorIs(t, err, ErrDeduplicationIdMissing)
+		assert.Nil(t, resp)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when the payload is not the contract", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		resp, err := publisher.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id": 1}`))
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidMessage)
+		assert.Nil(t, resp)
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestTopicPublisherPublishBatch(t *testing.T) {
+	t.Run("Should publish the messages in batches of ten", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		messages := make([]OutgoingMessage[testContract], 12)
+		entries := make([]types.PublishBatchRequestEntry, len(messages))
+		successful := make([]types.PublishBatchResultEntry, len(messages))
+
+		for i := range messages {
+			orderId := "order_" + strconv.Itoa(i)
+
+			messages[i] = testOutgoingMessage(orderId)
+
+			entries[i] = types.PublishBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(i % 10)),
+				Message:           aws.String(`{"order_id":"` + orderId + `"}`),
+				MessageAttributes: testContractAttributes(orderId),
+			}
+
+			successful[i] = types.PublishBatchResultEntry{
+				Id:        aws.String(strconv.Itoa(i % 10)),
+				MessageId: aws.String("message_" + strconv.Itoa(i)),
+			}
+		}
+
+		stubber.Add(testtools.Stub{
+			OperationName: "PublishBatch",
+			Input: &sns.PublishBatchInput{
+				TopicArn:                   aws.String(testTopicArn),
+				PublishBatchRequestEntries: entries[:10],
+			},
+			Output: &sns.PublishBatchOutput{
+				Successful: successful[:10],
+			},
+		})
+
+		stubber.Add(testtools.Stub{
+			OperationName: "PublishBatch",
+			Input: &sns.PublishBatchInput{
+				TopicArn:                   aws.String(testTopicArn),
+				PublishBatchRequestEntries: entries[10:],
+			},
+			Output: &sns.PublishBatchOutput{
+				Successful: successful[10:],
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		messageIds, err := publisher.PublishBatch(ctx, messages)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageIds, 12)
+		assert.Equal(t, "message_0", messageIds[0])
+		assert.Equal(t, "message_11", messageIds[11])
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should deduplicate the entries by their own id when the topic is fifo", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		topicArn := testTopicArn + ".fifo"
+
+		messages := []OutgoingMessage[testContract]{
+			{DeduplicationId: "outbox_1", Contract: testContract{OrderId: "order_id"}},
+			{DeduplicationId: "outbox_2", Contract: testContract{OrderId: "order_id"}},
+		}
+
+		entries := make([]types.PublishBatchRequestEntry, len(messages))
+		successful := make([]types.PublishBatchResultEntry, len(messages))
+
+		for i, message := range messages {
+			entries[i] = types.PublishBatchRequestEntry{
+				Id:                     aws.String(strconv.Itoa(i)),
+				Message:                aws.String(`{"order_id":"order_id"}`),
+				MessageAttributes:      testContractAttributes("order_id"),
+				MessageGroupId:         aws.String("order_id"),
+				MessageDeduplicationId: aws.String(message.DeduplicationId),
+			}
+
+			successful[i] = types.PublishBatchResultEntry{
+				Id:        aws.String(strconv.Itoa(i)),
+				MessageId: aws.String("message_" + strconv.Itoa(i)),
+			}
+		}
+
+		stubber.Add(testtools.Stub{
+			OperationName: "PublishBatch",
+			Input: &sns.PublishBatchInput{
+				TopicArn:                   aws.String(topicArn),
+				PublishBatchRequestEntries: entries,
+			},
+			Output: &sns.PublishBatchOutput{
+				Successful: successful,
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract](topicArn, *stubber.SdkConfig)
+
+		// Act
+		messageIds, err := publisher.PublishBatch(ctx, messages)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"message_0", "message_1"}, messageIds)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when messages of the batch fail", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "PublishBatch",
+			Input: &sns.PublishBatchInput{
+				TopicArn: aws.String(testTopicArn),
+				PublishBatchRequestEntries: []types.PublishBatchRequestEntry{
+					{
+						Id:                aws.String("0"),
+						Message:           aws.String(`{"order_id":"order_0"}`),
+						MessageAttributes: testContractAttributes("order_0"),
+					},
+					{
+						Id:                aws.String("1"),
+						Message:           aws.String(`{"order_id":"order_1"}`),
+						MessageAttributes: testContractAttributes("order_1"),
+					},
+				},
+			},
+			Output: &sns.PublishBatchOutput{
+				Successful: []types.PublishBatchResultEntry{
+					{
+						Id:        aws.String("0"),
+						MessageId: aws.String("message_0"),
+					},
+				},
+				Failed: []types.BatchResultErrorEntry{
+					{
+						Id:          aws.String("1"),
+						Code:        aws.String("InternalError"),
+						Message:     aws.String("internal error"),
+						SenderFault: false,
+					},
+				},
+			},
+		})
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		messageIds, err := publisher.PublishBatch(ctx, []OutgoingMessage[testContract]{
+			testOutgoingMessage("order_0"),
+			testOutgoingMessage("order_1"),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, ErrPublishBatchFailed)
+		assert.Equal(t, []string{"message_0", ""}, messageIds)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when the batch is not published", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		raiseErr := &testtools.StubError{Err: errors.New("ClientError")}
+
+		stubber.Add(testtools.Stub{
+			OperationName: "PublishBatch",
+			Error:         raiseErr,
+		})
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		messageIds, err := publisher.PublishBatch(ctx, []OutgoingMessage[testContract]{testOutgoingMessage("order_0")})
+
+		// Assert
+		assert.Equal(t, []string{""}, messageIds)
+		testtools.VerifyError(err, raiseErr, t)
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestTopicPublisherHealth(t *testing.T) {
+	t.Run("Should return unhealthy when topic arn is not resolved", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		publisher := NewTopicPublisher[testContract]("test-topic", aws.Config{})
+
+		// Act
+		status := publisher.Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		assert.Equal(t, ErrTopicNotResolved.Error(), status.Err)
+	})
+
+	t.Run("Should return healthy when topic attributes are returned", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetTopicAttributes",
+			Input: &sns.GetTopicAttributesInput{
+				TopicArn: aws.String(testTopicArn),
+			},
+			Output: &sns.GetTopicAttributesOutput{},
+		})
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		status := publisher.Health(ctx)
+
+		// Assert
+		assert.False(t, status.HasError())
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return unhealthy when topic attributes fail", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetTopicAttributes",
+			Input: &sns.GetTopicAttributesInput{
+				TopicArn: aws.String(testTopicArn),
+			},
+			Error: &testtools.StubError{Err: errors.New("ClientError")},
+		})
+
+		publisher := NewTopicPublisher[testContract](testTopicArn, *stubber.SdkConfig)
+
+		// Act
+		status := publisher.Health(ctx)
+
+		// Assert
+		assert.True(t, status.HasError())
+		testtools.ExitTest(stubber, t)
+	})
+}
@@ -0,0 +1,165 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+)
+
+var ErrRegionNotSet = errors.New("aws region not set")
+
+// TopicResolver finds the ARN of a topic, the topic may be configured by its full ARN
+// or by its name, in which case the ARN found is cached until it is invalidated
+type TopicResolver struct {
+	snsClient *sns.Client
+	stsClient *sts.Client
+	region    string
+
+	arns      map[string]string
+	arnsMutex sync.RWMutex
+}
+
+func NewTopicResolver(config aws.Config) *TopicResolver {
+	return &TopicResolver{
+		snsClient: sns.NewFromConfig(config),
+		stsClient: sts.NewFromConfig(config),
+		region:    config.Region,
+
+		arns: make(map[string]string),
+	}
+}
+
+func (r *TopicResolver) Resolve(ctx context.Context, topic string) (string, error) {
+	if topicArn, ok := r.Cached(topic); ok {
+		return topicArn, nil
+	}
+
+	topicArn, err := r.lookup(ctx, topic)
+	if err != nil {
+		return "", err
+	}
+
+	r.arnsMutex.Lock()
+	r.arns[topic] = topicArn
+	r.arnsMutex.Unlock()
+
+	slog.InfoContext(ctx, "topic resolved", "topic", topic, "topic_arn", topicArn)
+
+	return topicArn, nil
+}
+
+// Cached returns the ARN of the topic without looking it up
+func (r *TopicResolver) Cached(topic string) (string, bool) {
+	if arn.IsARN(topic) {
+		return topic, true
+	}
+
+	r.arnsMutex.RLock()
+	defer r.arnsMutex.RUnlock()
+
+	topicArn, ok := r.arns[topic]
+
+	return topicArn, ok
+}
+
+// Invalidate forgets the ARN of the topic, so the next Resolve looks it up again,
+// it returns false when there is nothing to look up again
+func (r *TopicResolver) Invalidate(topic string) bool {
+	if arn.IsARN(topic) {
+		return false
+	}
+
+	r.arnsMutex.Lock()
+	defer r.arnsMutex.Unlock()
+
+	delete(r.arns, topic)
+
+	return true
+}
+
+// lookup builds the ARN from the account of the caller, when the account cannot be
+// found the topics are listed instead
+func (r *TopicResolver) lookup(ctx context.Context, name string) (string, error) {
+	topicArn, err := r.buildArn(ctx, name)
+	if err != nil {
+		slog.WarnContext(ctx, "unable to build the topic arn, listing the topics", "topic", name, "error", err)
+		return r.listTopics(ctx, name)
+	}
+
+	_, err = r.snsClient.GetTopicAttributes(ctx, &sns.GetTopicAttributesInput{
+		TopicArn: aws.String(topicArn),
+	})
+	if err != nil {
+		if isTopicNotFound(err) {
+			return "", custom_error.ErrTopicNotFound
+		}
+
+		return "", err
+	}
+
+	return topicArn, nil
+}
+
+func (r *TopicResolver) buildArn(ctx context.Context, name string) (string, error) {
+	if r.region == "" {
+		return "", ErrRegionNotSet
+	}
+
+	identity, err := r.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+
+	// the partition of the caller tells apart the aws-cn and aws-us-gov regions
+	partition := "aws"
+	if callerArn, err := arn.Parse(aws.ToString(identity.Arn)); err == nil {
+		partition = callerArn.Partition
+	}
+
+	return arn.ARN{
+		Partition: partition,
+		Service:   "sns",
+		Region:    r.region,
+		AccountID: aws.ToString(identity.Account),
+		Resource:  name,
+	}.String(), nil
+}
+
+// listTopics goes through every page of topics looking for the one named exactly as the topic
+func (r *TopicResolver) listTopics(ctx context.Context, name string) (string, error) {
+	paginator := sns.NewListTopicsPaginator(r.snsClient, &sns.ListTopicsInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		for _, topic := range output.Topics {
+			topicArn, err := arn.Parse(aws.ToString(topic.TopicArn))
+			if err != nil {
+				continue
+			}
+
+			if topicArn.Resource == name {
+				return topicArn.String(), nil
+			}
+		}
+	}
+
+	return "", custom_error.ErrTopicNotFound
+}
+
+func isTopicNotFound(err error) bool {
+	var notFound *types.NotFoundException
+
+	return errors.As(err, &notFound)
+}
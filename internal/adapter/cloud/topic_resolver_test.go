@@ -0,0 +1,286 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/awsdocs/aws-doc-sdk-examples/gov2/testtools"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTopicArn = "arn:aws:sns:us-east-1:123456789012:test-topic"
+
+// newTopicStubber pins the region, so the arn is built the same way whatever the environment
+func newTopicStubber() *testtools.AwsmStubber {
+	stubber := testtools.NewStubber()
+	stubber.SdkConfig.Region = "us-east-1"
+
+	return stubber
+}
+
+func addCallerIdentityStub(stubber *testtools.AwsmStubber) {
+	stubber.Add(testtools.Stub{
+		OperationName: "GetCallerIdentity",
+		Input:         &sts.GetCallerIdentityInput{},
+		Output: &sts.GetCallerIdentityOutput{
+			Account: aws.String("123456789012"),
+			Arn:     aws.String("arn:aws:iam::123456789012:user/test"),
+		},
+	})
+}
+
+func addResolveTopicStubs(stubber *testtools.AwsmStubber) {
+	addCallerIdentityStub(stubber)
+
+	stubber.Add(testtools.Stub{
+		OperationName: "GetTopicAttributes",
+		Input: &sns.GetTopicAttributesInput{
+			TopicArn: aws.String(testTopicArn),
+		},
+		Output: &sns.GetTopicAttributesOutput{},
+	})
+}
+
+func TestTopicResolverResolve(t *testing.T) {
+	t.Run("Should return the arn configured as is", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		topicArn, err := resolver.Resolve(ctx, testTopicArn)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, testTopicArn, topicArn)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should build the arn from the account of the caller and cache it", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addResolveTopicStubs(stubber)
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		topicArn, err := resolver.Resolve(ctx, "test-topic")
+		assert.NoError(t, err)
+
+		cachedArn, err := resolver.Resolve(ctx, "test-topic")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, testTopicArn, topicArn)
+		assert.Equal(t, testTopicArn, cachedArn)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should build the arn in the partition of the caller", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+		stubber.SdkConfig.Region = "cn-north-1"
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetCallerIdentity",
+			Input:         &sts.GetCallerIdentityInput{},
+			Output: &sts.GetCallerIdentityOutput{
+				Account: aws.String("123456789012"),
+				Arn:     aws.String("arn:aws-cn:iam::123456789012:user/test"),
+			},
+		})
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetTopicAttributes",
+			Input: &sns.GetTopicAttributesInput{
+				TopicArn: aws.String("arn:aws-cn:sns:cn-north-1:123456789012:test-topic"),
+			},
+			Output: &sns.GetTopicAttributesOutput{},
+		})
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		topicArn, err := resolver.Resolve(ctx, "test-topic")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "arn:aws-cn:sns:cn-north-1:123456789012:test-topic", topicArn)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when the arn built does not exist", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addCallerIdentityStub(stubber)
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetTopicAttributes",
+			Input: &sns.GetTopicAttributesInput{
+				TopicArn: aws.String(testTopicArn),
+			},
+			Error: &testtools.StubError{Err: &types.NotFoundException{}},
+		})
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		_, err := resolver.Resolve(ctx, "test-topic")
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrTopicNotFound)
+		_, ok := resolver.Cached("test-topic")
+		assert.False(t, ok)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should go through every page of topics when the caller is unknown", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		stubber.Add(testtools.Stub{
+			OperationName: "GetCallerIdentity",
+			Error:         &testtools.StubError{Err: errors.New("AccessDenied"), ContinueAfter: true},
+		})
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ListTopics",
+			Input:         &sns.ListTopicsInput{},
+			Output: &sns.ListTopicsOutput{
+				Topics: []types.Topic{
+					{
+						TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:test-topic-dlq"),
+					},
+				},
+				NextToken: aws.String("next-page"),
+			},
+		})
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ListTopics",
+			Input: &sns.ListTopicsInput{
+				NextToken: aws.String("next-page"),
+			},
+			Output: &sns.ListTopicsOutput{
+				Topics: []types.Topic{
+					{
+						TopicArn: aws.String(testTopicArn),
+					},
+				},
+			},
+		})
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		topicArn, err := resolver.Resolve(ctx, "test-topic")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, testTopicArn, topicArn)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should not match a topic that only contains the name", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+		stubber.SdkConfig.Region = ""
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ListTopics",
+			Input:         &sns.ListTopicsInput{},
+			Output: &sns.ListTopicsOutput{
+				Topics: []types.Topic{
+					{
+						TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:UpdateOrderTopicDLQ"),
+					},
+				},
+			},
+		})
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		_, err := resolver.Resolve(ctx, "UpdateOrderTopic")
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrTopicNotFound)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should return error when ListTopics operation fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+		stubber.SdkConfig.Region = ""
+
+		raiseErr := &testtools.StubError{Err: errors.New("ClientError")}
+
+		stubber.Add(testtools.Stub{
+			OperationName: "ListTopics",
+			Error:         raiseErr,
+		})
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		// Act
+		_, err := resolver.Resolve(ctx, "test-topic")
+
+		// Assert
+		testtools.VerifyError(err, raiseErr, t)
+		testtools.ExitTest(stubber, t)
+	})
+}
+
+func TestTopicResolverInvalidate(t *testing.T) {
+	t.Run("Should forget the arn of the topic", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		stubber := newTopicStubber()
+
+		addResolveTopicStubs(stubber)
+
+		resolver := NewTopicResolver(*stubber.SdkConfig)
+
+		_, err := resolver.Resolve(ctx, "test-topic")
+		assert.NoError(t, err)
+
+		// Act
+		invalidated := resolver.Invalidate("test-topic")
+
+		// Assert
+		assert.True(t, invalidated)
+		_, ok := resolver.Cached("test-topic")
+		assert.False(t, ok)
+		testtools.ExitTest(stubber, t)
+	})
+
+	t.Run("Should keep the arn configured", func(t *testing.T) {
+		// Arrange
+		resolver := NewTopicResolver(aws.Config{})
+
+		// Act
+		invalidated := resolver.Invalidate(testTopicArn)
+
+		// Assert
+		assert.False(t, invalidated)
+		topicArn, ok := resolver.Cached(testTopicArn)
+		assert.True(t, ok)
+		assert.Equal(t, testTopicArn, topicArn)
+	})
+}
@@ -1,10 +1,20 @@
 package cloud
 
-import "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+import (
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+const (
+	UpdateOrderEventType     = "OrderPaymentUpdated"
+	UpdateOrderSchemaVersion = "1"
+)
 
 type UpdateOrderTopicPaymentContract struct {
-	PaymentId string `json:"id"`
-	State     string `json:"state"`
+	PaymentId      string      `json:"id"`
+	State          string      `json:"state"`
+	Amount         money.Money `json:"amount"`
+	RefundedAmount money.Money `json:"refunded_amount"`
 }
 
 type UpdateOrderTopicContract struct {
@@ -12,14 +22,28 @@ type UpdateOrderTopicContract struct {
 	Payment UpdateOrderTopicPaymentContract `json:"payment"`
 }
 
+func (c UpdateOrderTopicContract) GetEventType() string {
+	return UpdateOrderEventType
+}
+
+func (c UpdateOrderTopicContract) GetSchemaVersion() string {
+	return UpdateOrderSchemaVersion
+}
+
+func (c UpdateOrderTopicContract) GetOrderId() string {
+	return c.OrderId
+}
+
 func NewUpdateOrderContractFromPayment(payment *payment_entity.Payment) *UpdateOrderTopicContract {
 	payment.RefreshStateTitle()
 
 	return &UpdateOrderTopicContract{
 		OrderId: payment.OrderId,
 		Payment: UpdateOrderTopicPaymentContract{
-			PaymentId: payment.PaymentId,
-			State:     payment.StateTitle,
+			PaymentId:      payment.PaymentId,
+			State:          payment.StateTitle,
+			Amount:         payment.Amount,
+			RefundedAmount: payment.RefundedAmount,
 		},
 	}
 }
@@ -0,0 +1,57 @@
+package cloud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUpdateOrderContractFromPayment(t *testing.T) {
+	t.Run("Should write the amounts in major units", func(t *testing.T) {
+		// Arrange
+		payment := payment_entity.Payment{
+			OrderId:        "order_id",
+			PaymentId:      "payment_id",
+			Amount:         money.New(5998, money.BRL),
+			RefundedAmount: money.New(1000, money.BRL),
+			State:          payment_entity.PartiallyRefunded,
+		}
+
+		// Act
+		data, err := json.Marshal(NewUpdateOrderContractFromPayment(&payment))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"order_id": "order_id",
+			"payment": {
+				"id": "payment_id",
+				"state": "PartiallyRefunded",
+				"amount": 59.98,
+				"refunded_amount": 10.00
+			}
+		}`, string(data))
+	})
+}
+
+func TestUpdateOrderTopicContract(t *testing.T) {
+	t.Run("Should describe the update of the order", func(t *testing.T) {
+		// Arrange
+		contract := UpdateOrderTopicContract{
+			OrderId: "order_id",
+		}
+
+		// Act
+		eventType := contract.GetEventType()
+		schemaVersion := contract.GetSchemaVersion()
+		orderId := contract.GetOrderId()
+
+		// Assert
+		assert.Equal(t, UpdateOrderEventType, eventType)
+		assert.Equal(t, UpdateOrderSchemaVersion, schemaVersion)
+		assert.Equal(t, "order_id", orderId)
+	})
+}
@@ -5,9 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
-	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
@@ -23,7 +24,14 @@ type Service struct {
 }
 
 func NewDatabase(config *environment.Config) DatabaseService {
-	client, err := sql.Open("postgres", config.DbConfig.Url)
+	// every query gets a span of its own, the rows are read inside it
+	client, err := otelsql.Open("postgres", config.DbConfig.Url,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
 		panic(fmt.Errorf("error on connect to database: %v", err))
 	}
@@ -37,19 +45,11 @@ func (s *Service) GetInstance() *sql.DB {
 	return s.Client
 }
 
-func (s *Service) Health() *health.HealthStatus {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
-
+func (s *Service) Health(ctx context.Context) *health.HealthStatus {
 	if err := s.Client.PingContext(ctx); err != nil {
-		slog.Error("could not ping the database", "error", err)
-		return &health.HealthStatus{
-			Status: "unhealthy",
-			Err:    err.Error(),
-		}
+		slog.ErrorContext(ctx, "could not ping the database", "error", err)
+		return health.Unhealthy(err)
 	}
 
-	return &health.HealthStatus{
-		Status: "healthy",
-	}
+	return health.Healthy()
 }
@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
@@ -49,11 +51,13 @@ func TestHealth(t *testing.T) {
 			},
 		}
 
+		ctx := context.Background()
+
 		service := NewDatabase(config)
 		service.(*Service).Client = db
 
 		// Act
-		res := service.Health()
+		res := service.Health(ctx)
 
 		// Assert
 		assert.NotNil(mt, res)
@@ -74,10 +78,13 @@ func TestHealth(t *testing.T) {
 			},
 		}
 
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
 		service := NewDatabase(config)
 
 		// Act
-		res := service.Health()
+		res := service.Health(ctx)
 
 		// Assert
 		assert.NotNil(mt, res)
@@ -0,0 +1,326 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// lockId identifies the advisory lock held while migrating,
+// every instance of the service must use the same value
+const lockId int64 = 8_340_512_771
+
+var (
+	ErrInvalidMigration      = errors.New("invalid migration")
+	ErrUnknownMigration      = errors.New("applied migration not found")
+	ErrIrreversibleMigration = errors.New("migration has no down script")
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Migrator struct {
+	conn       *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a migrator with the migrations embedded in the binary
+func NewMigrator(conn *sql.DB) (*Migrator, error) {
+	fsys, err := fs.Sub(files, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return newMigrator(conn, fsys)
+}
+
+func newMigrator(conn *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		conn:       conn,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies every migration not recorded in schema_migrations, in version order,
+// returning how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	var count int
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if applied[migration.Version] {
+				continue
+			}
+
+			slog.InfoContext(ctx, "applying migration", "version", migration.Version, "name", migration.Name)
+
+			query := `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, NOW());`
+
+			if err := apply(ctx, conn, migration.Up, query, migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Down reverts the last applied migrations, up to the given number of steps,
+// returning how many were reverted
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	var count int
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		for _, version := range versions {
+			if count >= steps {
+				break
+			}
+
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("%w: %d", ErrUnknownMigration, version)
+			}
+
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrIrreversibleMigration, migration.Version, migration.Name)
+			}
+
+			slog.InfoContext(ctx, "reverting migration", "version", migration.Version, "name", migration.Name)
+
+			query := `DELETE FROM schema_migrations WHERE version = $1;`
+
+			if err := apply(ctx, conn, migration.Down, query, migration.Version); err != nil {
+				return fmt.Errorf("error reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Version returns the highest applied migration, zero when none was applied
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	var version int64
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		return conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations;`).Scan(&version)
+	})
+
+	return version, err
+}
+
+// Latest returns the highest known migration
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// withLock runs fn holding the advisory lock on a dedicated connection,
+// so pods starting at the same time apply the migrations one after the other
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, lockId); err != nil {
+		return err
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1);`, lockId); err != nil {
+			slog.ErrorContext(ctx, "error releasing the migration lock", "error", err)
+		}
+	}()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT,
+			name varchar(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (version)
+		);
+	`
+
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]bool, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+
+	for rows.Next() {
+		var version int64
+
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// apply runs the script and records it in schema_migrations in the same transaction
+func apply(ctx context.Context, conn *sql.Conn, script string, query string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, script)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// load reads the migrations named <version>_<name>.up.sql and <version>_<name>.down.sql
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		base, direction, found := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		if !found || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("%w: %s must end with .up.sql or .down.sql", ErrInvalidMigration, entry.Name())
+		}
+
+		rawVersion, name, found := strings.Cut(base, "_")
+		if !found || name == "" {
+			return nil, fmt.Errorf("%w: %s must be named <version>_<name>", ErrInvalidMigration, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s has an invalid version", ErrInvalidMigration, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{
+				Version: version,
+				Name:    name,
+			}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: version %d has no up script", ErrInvalidMigration, migration.Version)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
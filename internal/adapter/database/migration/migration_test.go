@@ -0,0 +1,314 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFS() fstest.MapFS {
+	return fstest.MapFS{
+		"000001_create_payments.up.sql":   {Data: []byte("CREATE TABLE payments (id int);")},
+		"000001_create_payments.down.sql": {Data: []byte("DROP TABLE payments;")},
+		"000002_create_refunds.up.sql":    {Data: []byte("CREATE TABLE refunds (id int);")},
+		"000002_create_refunds.down.sql":  {Data: []byte("DROP TABLE refunds;")},
+	}
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_lock(.+)").
+		WithArgs(lockId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations(.+)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock(.+)").
+		WithArgs(lockId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestNewMigrator(t *testing.T) {
+	t.Run("Should load the embedded migrations in order", func(t *testing.T) {
+		// Act
+		migrator, err := NewMigrator(nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, migrator.migrations)
+
+		for i, migration := range migrator.migrations {
+			assert.Equal(t, int64(i+1), migration.Version)
+			assert.NotEmpty(t, migration.Up)
+			assert.NotEmpty(t, migration.Down)
+		}
+	})
+
+	t.Run("Should return error when a migration is not valid", func(t *testing.T) {
+		// Arrange
+		cases := map[string]fstest.MapFS{
+			"missing direction": {"000001_payments.sql": {}},
+			"unknown direction": {"000001_payments.sideways.sql": {}},
+			"missing name":      {"000001.up.sql": {}},
+			"invalid version":   {"abc_payments.up.sql": {}},
+			"missing up":        {"000001_payments.down.sql": {Data: []byte("DROP TABLE payments;")}},
+			"duplicated version": {
+				"000001_payments.up.sql": {Data: []byte("SELECT 1;")},
+				"000001_refunds.up.sql":  {Data: []byte("SELECT 1;")},
+			},
+		}
+
+		for name, fsys := range cases {
+			// Act
+			_, err := newMigrator(nil, fsys)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidMigration, name)
+		}
+	})
+}
+
+func TestUp(t *testing.T) {
+	t.Run("Should apply the pending migrations holding the lock", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE refunds").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations(.+)").
+			WithArgs(int64(2), "create_refunds").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		count, err := migrator.Up(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should do nothing when every migration was applied", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		count, err := migrator.Up(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback and stop when a migration fails", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE payments").
+			WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		count, err := migrator.Up(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error when the lock cannot be acquired", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectExec("SELECT pg_advisory_lock(.+)").
+			WillReturnError(assert.AnError)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		_, err = migrator.Up(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDown(t *testing.T) {
+	t.Run("Should revert the last applied migrations", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DROP TABLE refunds").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM schema_migrations(.+)").
+			WithArgs(int64(2)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		count, err := migrator.Down(ctx, 1)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error when the migration has no down script", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, fstest.MapFS{
+			"000001_create_payments.up.sql": {Data: []byte("CREATE TABLE payments (id int);")},
+		})
+		assert.NoError(t, err)
+
+		// Act
+		count, err := migrator.Down(ctx, 1)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrIrreversibleMigration)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error when the applied migration is unknown", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT version FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		_, err = migrator.Down(ctx, 1)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrUnknownMigration)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestVersion(t *testing.T) {
+	t.Run("Should return the highest applied migration", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		expectLock(mock)
+
+		mock.ExpectQuery("SELECT COALESCE(.+) FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+
+		expectUnlock(mock)
+
+		migrator, err := newMigrator(db, newFS())
+		assert.NoError(t, err)
+
+		// Act
+		version, err := migrator.Version(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+		assert.Equal(t, int64(2), migrator.Latest())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,6 +1,3 @@
-DROP TABLE IF EXISTS payments;
-DROP TABLE IF EXISTS payment_items;
-
 CREATE TABLE IF NOT EXISTS payments (
     order_id varchar(255),
     payment_id varchar(255),
@@ -19,4 +16,4 @@ CREATE TABLE IF NOT EXISTS payment_items (
     name varchar(255),
     quantity int,
     PRIMARY KEY (id, order_id, payment_id)
-);
\ No newline at end of file
+);
@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS qr_code;
+ALTER TABLE payments DROP COLUMN IF EXISTS charge_id;
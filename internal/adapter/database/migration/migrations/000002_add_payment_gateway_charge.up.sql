@@ -0,0 +1,2 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS charge_id varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS qr_code TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id varchar(255),
+    order_id varchar(255) NOT NULL,
+    payment_id varchar(255) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    gateway_refund_id varchar(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS refunds_payment_id_idx ON refunds (payment_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id varchar(255),
+    topic varchar(255) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at) WHERE sent_at IS NULL;
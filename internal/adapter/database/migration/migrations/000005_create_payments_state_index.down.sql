@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payments_state_created_at_idx;
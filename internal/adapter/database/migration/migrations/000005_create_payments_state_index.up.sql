@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS payments_state_created_at_idx ON payments (state, created_at);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS inbox (
+    id varchar(255),
+    payment_id varchar(255) NOT NULL,
+    response TEXT NOT NULL,
+    processed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payment_items_order_id_payment_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS payment_items_order_id_payment_id_idx ON payment_items (order_id, payment_id);
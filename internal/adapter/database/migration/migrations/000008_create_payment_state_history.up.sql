@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS payment_state_history (
+    id varchar(255),
+    payment_id varchar(255) NOT NULL,
+    from_state INT NOT NULL,
+    to_state INT NOT NULL,
+    source varchar(50) NOT NULL,
+    actor_id varchar(255) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS payment_state_history_payment_id_idx ON payment_state_history (payment_id, created_at);
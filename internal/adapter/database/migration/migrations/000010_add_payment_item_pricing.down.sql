@@ -0,0 +1,3 @@
+ALTER TABLE payment_items DROP COLUMN IF EXISTS total;
+ALTER TABLE payment_items DROP COLUMN IF EXISTS discount;
+ALTER TABLE payment_items DROP COLUMN IF EXISTS unit_price;
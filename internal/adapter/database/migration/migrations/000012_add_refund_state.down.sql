@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS refunds_pending_payment_idx;
+
+ALTER TABLE refunds DROP COLUMN IF EXISTS state;
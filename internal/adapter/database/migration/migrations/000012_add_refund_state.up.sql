@@ -0,0 +1,3 @@
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS state varchar(32) NOT NULL DEFAULT 'Completed';
+
+CREATE UNIQUE INDEX IF NOT EXISTS refunds_pending_payment_idx ON refunds (payment_id) WHERE state = 'Pending';
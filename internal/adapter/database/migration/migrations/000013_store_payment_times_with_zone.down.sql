@@ -0,0 +1,14 @@
+ALTER TABLE outbox
+    ALTER COLUMN next_attempt_at TYPE TIMESTAMP USING next_attempt_at AT TIME ZONE 'America/Sao_Paulo',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'America/Sao_Paulo',
+    ALTER COLUMN sent_at TYPE TIMESTAMP USING sent_at AT TIME ZONE 'America/Sao_Paulo';
+
+ALTER TABLE inbox
+    ALTER COLUMN processed_at TYPE TIMESTAMP USING processed_at AT TIME ZONE 'America/Sao_Paulo';
+
+ALTER TABLE refunds
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'America/Sao_Paulo';
+
+ALTER TABLE payments
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'America/Sao_Paulo',
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'America/Sao_Paulo';
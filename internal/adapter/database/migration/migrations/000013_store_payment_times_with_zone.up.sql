@@ -0,0 +1,38 @@
+-- the times were stored as wall time, of the service zone when bound as parameters and of UTC
+-- when written as literals by the updates, they are kept as the same instants
+ALTER TABLE payments
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'America/Sao_Paulo',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING CASE
+        WHEN updated_at = created_at THEN updated_at AT TIME ZONE 'America/Sao_Paulo'
+        ELSE updated_at AT TIME ZONE 'UTC'
+    END;
+
+ALTER TABLE refunds
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'America/Sao_Paulo';
+
+ALTER TABLE inbox
+    ALTER COLUMN processed_at TYPE TIMESTAMPTZ USING processed_at AT TIME ZONE 'America/Sao_Paulo';
+
+-- the history is created with its zone, only the tables created before that are converted
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM information_schema.columns
+        WHERE table_name = 'payment_state_history'
+            AND column_name = 'created_at'
+            AND data_type = 'timestamp without time zone'
+    ) THEN
+        ALTER TABLE payment_state_history
+            ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'America/Sao_Paulo';
+    END IF;
+END $$;
+
+-- a pending message that already failed was rescheduled by an update
+ALTER TABLE outbox
+    ALTER COLUMN next_attempt_at TYPE TIMESTAMPTZ USING CASE
+        WHEN attempts > 0 AND sent_at IS NULL THEN next_attempt_at AT TIME ZONE 'UTC'
+        ELSE next_attempt_at AT TIME ZONE 'America/Sao_Paulo'
+    END,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'America/Sao_Paulo',
+    ALTER COLUMN sent_at TYPE TIMESTAMPTZ USING sent_at AT TIME ZONE 'UTC';
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
+
 	health "github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
+
 	mock "github.com/stretchr/testify/mock"
 
 	sql "database/sql"
@@ -34,17 +37,17 @@ func (_m *MockDatabaseService) GetInstance() *sql.DB {
 	return r0
 }
 
-// Health provides a mock function with given fields:
-func (_m *MockDatabaseService) Health() *health.HealthStatus {
-	ret := _m.Called()
+// Health provides a mock function with given fields: ctx
+func (_m *MockDatabaseService) Health(ctx context.Context) *health.HealthStatus {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Health")
 	}
 
 	var r0 *health.HealthStatus
-	if rf, ok := ret.Get(0).(func() *health.HealthStatus); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *health.HealthStatus); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*health.HealthStatus)
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "payment_management"
+
+const (
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	paymentsCreated     prometheus.Counter
+	paymentStateChanges *prometheus.CounterVec
+
+	webhookDuration *prometheus.HistogramVec
+
+	queueReceives        *prometheus.CounterVec
+	queueMessages        prometheus.Counter
+	queueDeletes         *prometheus.CounterVec
+	queueProcessDuration *prometheus.HistogramVec
+
+	topicPublishes *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		paymentsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_created_total",
+			Help:      "Number of payments created.",
+		}),
+		paymentStateChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payment_state_changes_total",
+			Help:      "Number of payments moved to each state, like Approved, Rejected or Expired.",
+		}, []string{"state"}),
+
+		webhookDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "webhook_duration_seconds",
+			Help:      "Time taken to handle the payment gateway webhook.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"status"}),
+
+		queueReceives: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_receives_total",
+			Help:      "Number of SQS receive calls by outcome.",
+		}, []string{"outcome"}),
+		queueMessages: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_messages_received_total",
+			Help:      "Number of SQS messages received.",
+		}),
+		queueDeletes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_deletes_total",
+			Help:      "Number of SQS delete calls by outcome.",
+		}, []string{"outcome"}),
+		queueProcessDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "queue_message_processing_duration_seconds",
+			Help:      "Time taken to process a SQS message by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+
+		topicPublishes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "topic_publishes_total",
+			Help:      "Number of SNS publish calls by topic and outcome.",
+		}, []string{"topic", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+
+		m.paymentsCreated,
+		m.paymentStateChanges,
+		m.webhookDuration,
+		m.queueReceives,
+		m.queueMessages,
+		m.queueDeletes,
+		m.queueProcessDuration,
+		m.topicPublishes,
+	)
+
+	return m
+}
+
+// RegisterDatabase exposes the connection pool stats of the database
+func (m *Metrics) RegisterDatabase(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return outcomeFailure
+	}
+
+	return outcomeSuccess
+}
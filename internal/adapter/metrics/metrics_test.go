@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("Should expose the registered metrics", func(t *testing.T) {
+		// Arrange
+		db, _, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		metrics := NewMetrics()
+		metrics.RegisterDatabase(db, "payment_db")
+		metrics.paymentsCreated.Inc()
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		metrics.Handler().ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "payment_management_payments_created_total 1")
+		assert.Contains(t, rec.Body.String(), `go_sql_open_connections{db_name="payment_db"}`)
+		assert.Contains(t, rec.Body.String(), "go_goroutines")
+	})
+}
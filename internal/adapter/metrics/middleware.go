@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookMiddleware observes the latency of the webhook by response status
+func (m *Metrics) WebhookMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+
+			m.webhookDuration.
+				WithLabelValues(strconv.Itoa(responseStatus(c, err))).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// responseStatus returns the status echo will answer with, an error
+// not written yet is answered by the error handler
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
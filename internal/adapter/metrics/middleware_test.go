@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		handler echo.HandlerFunc
+		status  string
+	}{
+		"Should observe the status written by the handler": {
+			handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			},
+			status: "204",
+		},
+		"Should observe the status of a http error": {
+			handler: func(c echo.Context) error {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			},
+			status: "401",
+		},
+		"Should observe an unknown error as an internal error": {
+			handler: func(c echo.Context) error {
+				return assert.AnError
+			},
+			status: "500",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			metrics := NewMetrics()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			// Act
+			_ = metrics.WebhookMiddleware()(tc.handler)(ctx)
+
+			// Assert
+			var observed dto.Metric
+			err := metrics.webhookDuration.WithLabelValues(tc.status).(prometheus.Metric).Write(&observed)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, testutil.CollectAndCount(metrics.webhookDuration))
+			assert.Equal(t, uint64(1), observed.GetHistogram().GetSampleCount())
+		})
+	}
+}
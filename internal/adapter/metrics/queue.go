@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+)
+
+type observableQueueService interface {
+	SetObserver(observer cloud.QueueObserver)
+}
+
+type QueueService struct {
+	cloud.QueueService
+
+	metrics *Metrics
+}
+
+// NewQueueService observes the receive, delete and processing outcomes of the queue,
+// when the queue does not accept an observer only the calls are forwarded
+func NewQueueService(queue cloud.QueueService, metrics *Metrics) cloud.QueueService {
+	service := &QueueService{
+		QueueService: queue,
+		metrics:      metrics,
+	}
+
+	if observable, ok := queue.(observableQueueService); ok {
+		observable.SetObserver(service)
+	}
+
+	return service
+}
+
+func (s *QueueService) ObserveReceive(messages int, err error) {
+	s.metrics.queueReceives.WithLabelValues(outcome(err)).Inc()
+	s.metrics.queueMessages.Add(float64(messages))
+}
+
+func (s *QueueService) ObserveDelete(err error) {
+	s.metrics.queueDeletes.WithLabelValues(outcome(err)).Inc()
+}
+
+func (s *QueueService) ObserveProcess(duration time.Duration, err error) {
+	s.metrics.queueProcessDuration.WithLabelValues(outcome(err)).Observe(duration.Seconds())
+}
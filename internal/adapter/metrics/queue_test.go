@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type observableQueue struct {
+	*mocks.MockQueueService
+
+	observer cloud.QueueObserver
+}
+
+func (q *observableQueue) SetObserver(observer cloud.QueueObserver) {
+	q.observer = observer
+}
+
+func TestQueueService(t *testing.T) {
+	t.Run("Should register itself as the observer of the queue", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+
+		inner := &observableQueue{MockQueueService: mocks.NewMockQueueService(t)}
+		inner.On("GetQueueName").
+			Return("order-payment-queue").
+			Once()
+
+		// Act
+		queue := NewQueueService(inner, metrics)
+
+		// Assert
+		assert.Equal(t, queue, inner.observer)
+		assert.Equal(t, "order-payment-queue", queue.GetQueueName())
+		inner.AssertExpectations(t)
+	})
+
+	t.Run("Should count the queue outcomes", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+
+		inner := &observableQueue{MockQueueService: mocks.NewMockQueueService(t)}
+
+		NewQueueService(inner, metrics)
+
+		// Act
+		inner.observer.ObserveReceive(3, nil)
+		inner.observer.ObserveReceive(0, assert.AnError)
+		inner.observer.ObserveDelete(nil)
+		inner.observer.ObserveDelete(assert.AnError)
+		inner.observer.ObserveProcess(time.Second, nil)
+		inner.observer.ObserveProcess(time.Second, assert.AnError)
+
+		// Assert
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.queueReceives.WithLabelValues(outcomeSuccess)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.queueReceives.WithLabelValues(outcomeFailure)))
+		assert.Equal(t, float64(3), testutil.ToFloat64(metrics.queueMessages))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.queueDeletes.WithLabelValues(outcomeSuccess)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.queueDeletes.WithLabelValues(outcomeFailure)))
+		assert.Equal(t, 2, testutil.CollectAndCount(metrics.queueProcessDuration))
+	})
+
+	t.Run("Should forward the calls when the queue does not accept an observer", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+
+		inner := mocks.NewMockQueueService(t)
+		inner.On("GetQueueName").
+			Return("order-payment-queue").
+			Once()
+
+		queue := NewQueueService(inner, metrics)
+
+		// Act
+		queueName := queue.GetQueueName()
+
+		// Assert
+		assert.Equal(t, "order-payment-queue", queueName)
+		inner.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
+)
+
+type PaymentRepository struct {
+	repository.PaymentRepository
+
+	metrics *Metrics
+}
+
+// NewPaymentRepository counts the payments created and the state changes stored
+func NewPaymentRepository(repository repository.PaymentRepository, metrics *Metrics) repository.PaymentRepository {
+	return &PaymentRepository{
+		PaymentRepository: repository,
+		metrics:           metrics,
+	}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *payment_entity.Payment) error {
+	if err := r.PaymentRepository.Create(ctx, payment); err != nil {
+		return err
+	}
+
+	r.metrics.paymentsCreated.Inc()
+
+	return nil
+}
+
+func (r *PaymentRepository) Update(ctx context.Context, payment *payment_entity.Payment, messages ...outbox_entity.Message) error {
+	changes := payment.StateChanges
+
+	if err := r.PaymentRepository.Update(ctx, payment, messages...); err != nil {
+		return err
+	}
+
+	r.countStateChanges(changes)
+
+	return nil
+}
+
+func (r *PaymentRepository) UpdateFromEvent(ctx context.Context, payment *payment_entity.Payment, event *inbox_entity.Event, messages ...outbox_entity.Message) error {
+	changes := payment.StateChanges
+
+	if err := r.PaymentRepository.UpdateFromEvent(ctx, payment, event, messages...); err != nil {
+		return err
+	}
+
+	r.countStateChanges(changes)
+
+	return nil
+}
+
+func (r *PaymentRepository) CompleteRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund, messages ...outbox_entity.Message) error {
+	changes := payment.StateChanges
+
+	if err := r.PaymentRepository.CompleteRefund(ctx, payment, refund, messages...); err != nil {
+		return err
+	}
+
+	r.countStateChanges(changes)
+
+	return nil
+}
+
+// countStateChanges is given the changes taken before the update,
+// the repository clears them from the payment once they are stored
+func (r *PaymentRepository) countStateChanges(changes []payment_entity.StateChange) {
+	for _, change := range changes {
+		r.metrics.paymentStateChanges.WithLabelValues(change.To.String()).Inc()
+	}
+}
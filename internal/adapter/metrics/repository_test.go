@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPaymentRepository(t *testing.T) {
+	t.Run("Should count the payments created", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		metrics := NewMetrics()
+
+		inner := mocks.NewMockPaymentRepository(t)
+		inner.On("Create", ctx, mock.Anything).
+			Return(nil).
+			Once()
+		inner.On("Create", ctx, mock.Anything).
+			Return(assert.AnError).
+			Once()
+
+		repository := NewPaymentRepository(inner, metrics)
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+
+		// Act
+		errCreated := repository.Create(ctx, &payment)
+		errFailed := repository.Create(ctx, &payment)
+
+		// Assert
+		assert.NoError(t, errCreated)
+		assert.ErrorIs(t, errFailed, assert.AnError)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.paymentsCreated))
+		inner.AssertExpectations(t)
+	})
+
+	t.Run("Should count the state changes stored", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		metrics := NewMetrics()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		err := payment.TransitionTo(payment_entity.Approved, time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceWebhook})
+		assert.NoError(t, err)
+
+		inner := mocks.NewMockPaymentRepository(t)
+		inner.On("Update", ctx, &payment).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*payment_entity.Payment).StateChanges = nil
+			}).
+			Return(nil).
+			Once()
+
+		repository := NewPaymentRepository(inner, metrics)
+
+		// Act
+		err = repository.Update(ctx, &payment)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.paymentStateChanges.WithLabelValues("Approved")))
+		inner.AssertExpectations(t)
+	})
+
+	t.Run("Should not count the state changes when the update fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		metrics := NewMetrics()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		err := payment.TransitionTo(payment_entity.Expired, time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceExpiration})
+		assert.NoError(t, err)
+
+		inner := mocks.NewMockPaymentRepository(t)
+		inner.On("UpdateFromEvent", ctx, &payment, mock.Anything).
+			Return(assert.AnError).
+			Once()
+
+		repository := NewPaymentRepository(inner, metrics)
+
+		// Act
+		err = repository.UpdateFromEvent(ctx, &payment, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 0, testutil.CollectAndCount(metrics.paymentStateChanges))
+		inner.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+)
+
+type TopicService struct {
+	cloud.TopicService
+
+	metrics *Metrics
+}
+
+// NewTopicService counts the publish outcomes of the topic
+func NewTopicService(topic cloud.TopicService, metrics *Metrics) cloud.TopicService {
+	return &TopicService{
+		TopicService: topic,
+		metrics:      metrics,
+	}
+}
+
+func (s *TopicService) PublishMessage(ctx context.Context, deduplicationId string, payload json.RawMessage) (*string, error) {
+	messageId, err := s.TopicService.PublishMessage(ctx, deduplicationId, payload)
+
+	s.metrics.topicPublishes.WithLabelValues(s.GetTopicName(), outcome(err)).Inc()
+
+	return messageId, err
+}
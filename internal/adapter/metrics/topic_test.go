@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTopicService(t *testing.T) {
+	t.Run("Should count the publish outcomes per topic", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		metrics := NewMetrics()
+
+		inner := mocks.NewMockTopicService(t)
+		inner.On("GetTopicName").
+			Return("update-order-topic")
+		inner.On("PublishMessage", ctx, "outbox_id", mock.Anything).
+			Return(aws.String("message_id"), nil).
+			Twice()
+		inner.On("PublishMessage", ctx, "outbox_id", mock.Anything).
+			Return(nil, assert.AnError).
+			Once()
+
+		topic := NewTopicService(inner, metrics)
+
+		// Act
+		messageId, err := topic.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		_, _ = topic.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		_, errFailed := topic.PublishMessage(ctx, "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "message_id", *messageId)
+		assert.ErrorIs(t, errFailed, assert.AnError)
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.topicPublishes.WithLabelValues("update-order-topic", outcomeSuccess)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.topicPublishes.WithLabelValues("update-order-topic", outcomeFailure)))
+		inner.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,323 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+type Option func(*Server)
+
+// WithApiKey makes the server reject requests without the given bearer token
+func WithApiKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithWebhook sets the base url used to notify the payment results,
+// the payment id is appended to it (e.g. http://host/api/v1/payments/webhook)
+func WithWebhook(url string, header http.Header) Option {
+	return func(s *Server) {
+		s.webhookUrl = strings.TrimSuffix(url, "/")
+		s.webhookHeader = header
+	}
+}
+
+// WithWebhookSecret signs the webhook notifications with the given secret
+func WithWebhookSecret(secret string) Option {
+	return func(s *Server) {
+		s.webhookSecret = secret
+	}
+}
+
+// Server is an in-process payment gateway used to exercise the
+// charge and webhook flows without reaching an external provider
+type Server struct {
+	*httptest.Server
+
+	apiKey        string
+	webhookUrl    string
+	webhookHeader http.Header
+	webhookSecret string
+
+	mutex    sync.Mutex
+	charges  map[string]payment_gateway.Charge
+	refunds  map[string]payment_gateway.Refund
+	failures []int
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		charges:       make(map[string]payment_gateway.Charge),
+		refunds:       make(map[string]payment_gateway.Refund),
+		webhookHeader: http.Header{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/charges", s.createCharge)
+	mux.HandleFunc("GET /v1/charges/{reference_id}", s.getCharge)
+	mux.HandleFunc("POST /v1/charges/{charge_id}/refunds", s.refundCharge)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// FailNext makes the next request answer with the given status code
+func (s *Server) FailNext(status int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.failures = append(s.failures, status)
+}
+
+// Charge returns the charge created for the given reference id
+func (s *Server) Charge(referenceId string) (payment_gateway.Charge, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	charge, ok := s.charges[referenceId]
+	return charge, ok
+}
+
+// Refund returns the refund created for the given reference id
+func (s *Server) Refund(referenceId string) (payment_gateway.Refund, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	refund, ok := s.refunds[referenceId]
+	return refund, ok
+}
+
+// Notify settles the charge and sends the result to the webhook,
+// returning the status code answered by the webhook
+func (s *Server) Notify(ctx context.Context, referenceId string, approved bool) (int, error) {
+	return s.NotifyEvent(ctx, uuid.NewString(), referenceId, approved)
+}
+
+// NotifyEvent works like Notify but with the given event id,
+// so the same notification can be delivered more than once
+func (s *Server) NotifyEvent(ctx context.Context, eventId string, referenceId string, approved bool) (int, error) {
+	s.mutex.Lock()
+	if charge, ok := s.charges[referenceId]; ok {
+		charge.Status = payment_gateway.ChargeStatusRejected
+		if approved {
+			charge.Status = payment_gateway.ChargeStatusApproved
+		}
+		s.charges[referenceId] = charge
+	}
+	s.mutex.Unlock()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event_id": eventId,
+		"approved": approved,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/%s", s.webhookUrl, referenceId), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	for key, values := range s.webhookHeader {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if s.webhookSecret != "" {
+		req.Header.Set(payment_gateway.WebhookSignatureHeader, payment_gateway.SignWebhook(s.webhookSecret, time.Now(), payment_gateway.WebhookRequest{
+			Method: req.Method,
+			Target: req.URL.RequestURI(),
+			Body:   body,
+		}))
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return res.StatusCode, nil
+}
+
+func (s *Server) createCharge(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
+	var request payment_gateway.ChargeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+
+	if request.ReferenceId == "" || !request.Amount.IsPositive() {
+		writeError(w, http.StatusUnprocessableEntity, "invalid_charge", "reference_id and a positive amount are required")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if charge, ok := s.charges[request.ReferenceId]; ok {
+		writeJSON(w, http.StatusOK, charge)
+		return
+	}
+
+	id := uuid.NewString()
+
+	charge := payment_gateway.Charge{
+		Id:          fmt.Sprintf("ch_%s", id),
+		ReferenceId: request.ReferenceId,
+		Amount:      request.Amount,
+		Method:      request.Method,
+		Status:      payment_gateway.ChargeStatusPending,
+		QrCode:      fmt.Sprintf("00020126580014br.gov.bcb.pix0136%s5204000053039865406%s5802BR6304", id, request.Amount),
+		CreatedAt:   time.Now(),
+	}
+
+	s.charges[request.ReferenceId] = charge
+
+	writeJSON(w, http.StatusCreated, charge)
+}
+
+func (s *Server) getCharge(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
+	charge, ok := s.Charge(r.PathValue("reference_id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "charge_not_found", "charge not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, charge)
+}
+
+func (s *Server) refundCharge(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
+	var request payment_gateway.RefundRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+
+	if request.ReferenceId == "" || !request.Amount.IsPositive() {
+		writeError(w, http.StatusUnprocessableEntity, "invalid_refund", "reference_id and a positive amount are required")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if refund, ok := s.refunds[request.ReferenceId]; ok {
+		writeJSON(w, http.StatusOK, refund)
+		return
+	}
+
+	chargeId := r.PathValue("charge_id")
+
+	var charge *payment_gateway.Charge
+
+	for _, c := range s.charges {
+		if c.Id == chargeId {
+			charge = &c
+			break
+		}
+	}
+
+	if charge == nil {
+		writeError(w, http.StatusNotFound, "charge_not_found", "charge not found")
+		return
+	}
+
+	if charge.Status != payment_gateway.ChargeStatusApproved {
+		writeError(w, http.StatusUnprocessableEntity, "charge_not_refundable", "only approved charges can be refunded")
+		return
+	}
+
+	refunded := money.New(0, charge.Amount.Currency())
+	for _, refund := range s.refunds {
+		if refund.ChargeId == chargeId {
+			refunded = refunded.Add(refund.Amount)
+		}
+	}
+
+	if refunded.Add(request.Amount).Cmp(charge.Amount) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, "refund_amount_exceeded", "refund amount exceeds the charge amount")
+		return
+	}
+
+	refund := payment_gateway.Refund{
+		Id:          fmt.Sprintf("re_%s", uuid.NewString()),
+		ReferenceId: request.ReferenceId,
+		ChargeId:    chargeId,
+		Amount:      request.Amount,
+		Status:      payment_gateway.RefundStatusSucceeded,
+		CreatedAt:   time.Now(),
+	}
+
+	s.refunds[request.ReferenceId] = refund
+
+	writeJSON(w, http.StatusCreated, refund)
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) bool {
+	s.mutex.Lock()
+	var status int
+	if len(s.failures) > 0 {
+		status, s.failures = s.failures[0], s.failures[1:]
+	}
+	s.mutex.Unlock()
+
+	if status != 0 {
+		writeError(w, status, "forced_failure", http.StatusText(status))
+		return false
+	}
+
+	if s.apiKey != "" && r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", s.apiKey) {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "invalid api key")
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, payment_gateway.ErrorResponse{
+		Code:    code,
+		Message: message,
+	})
+}
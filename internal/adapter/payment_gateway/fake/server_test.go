@@ -0,0 +1,370 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGatewayService(server *Server, apiKey string) payment_gateway.GatewayService {
+	return payment_gateway.NewHttpGatewayService(&environment.GatewayConfig{
+		BaseUrl: server.URL,
+		ApiKey:  apiKey,
+		Timeout: time.Second,
+	})
+}
+
+func TestCreateCharge(t *testing.T) {
+	t.Run("Should create a charge with a qr code", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := NewServer(WithApiKey("api-key"))
+		defer server.Close()
+
+		service := newGatewayService(server, "api-key")
+
+		referenceId := uuid.NewString()
+
+		// Act
+		charge, err := service.CreateCharge(ctx, payment_gateway.ChargeRequest{
+			ReferenceId: referenceId,
+			Amount:      money.New(5998, money.BRL),
+			Method:      payment_gateway.MethodPix,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, charge.Id)
+		assert.NotEmpty(t, charge.QrCode)
+		assert.Equal(t, payment_gateway.ChargeStatusPending, charge.Status)
+
+		stored, ok := server.Charge(referenceId)
+		assert.True(t, ok)
+		assert.Equal(t, charge.Id, stored.Id)
+	})
+
+	t.Run("Should return the same charge for the same reference", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := NewServer()
+		defer server.Close()
+
+		service := newGatewayService(server, "")
+
+		request := payment_gateway.ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(1000, money.BRL),
+		}
+
+		first, err := service.CreateCharge(ctx, request)
+		assert.NoError(t, err)
+
+		// Act
+		second, err := service.CreateCharge(ctx, request)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, first.Id, second.Id)
+	})
+
+	t.Run("Should reject an invalid api key", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := NewServer(WithApiKey("api-key"))
+		defer server.Close()
+
+		service := newGatewayService(server, "wrong-key")
+
+		// Act
+		charge, err := service.CreateCharge(ctx, payment_gateway.ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(1000, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayUnauthorized)
+		assert.Nil(t, charge)
+	})
+
+	t.Run("Should reject an invalid charge", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := NewServer()
+		defer server.Close()
+
+		service := newGatewayService(server, "")
+
+		// Act
+		charge, err := service.CreateCharge(ctx, payment_gateway.ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(0, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayRequestNotValid)
+		assert.Nil(t, charge)
+	})
+
+	t.Run("Should answer with the forced failure", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := NewServer()
+		defer server.Close()
+
+		server.FailNext(http.StatusServiceUnavailable)
+
+		service := newGatewayService(server, "")
+
+		request := payment_gateway.ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(1000, money.BRL),
+		}
+
+		// Act
+		_, errFailure := service.CreateCharge(ctx, request)
+		charge, err := service.CreateCharge(ctx, request)
+
+		// Assert
+		assert.ErrorIs(t, errFailure, custom_error.ErrGatewayUnavailable)
+		assert.NoError(t, err)
+		assert.NotNil(t, charge)
+	})
+}
+
+func TestNotify(t *testing.T) {
+	t.Run("Should settle the charge and notify the webhook", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		referenceId := uuid.NewString()
+
+		var received map[string]interface{}
+
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPatch, r.Method)
+			assert.Equal(t, "/payments/webhook/"+referenceId, r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer webhook.Close()
+
+		server := NewServer(WithWebhook(webhook.URL+"/payments/webhook", http.Header{
+			"Authorization": []string{"Bearer token"},
+		}))
+		defer server.Close()
+
+		_, err := newGatewayService(server, "").CreateCharge(ctx, payment_gateway.ChargeRequest{
+			ReferenceId: referenceId,
+			Amount:      money.New(1000, money.BRL),
+		})
+		assert.NoError(t, err)
+
+		// Act
+		status, err := server.Notify(ctx, referenceId, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, true, received["approved"])
+		assert.NotEmpty(t, received["event_id"])
+
+		charge, ok := server.Charge(referenceId)
+		assert.True(t, ok)
+		assert.Equal(t, payment_gateway.ChargeStatusApproved, charge.Status)
+	})
+
+	t.Run("Should sign the notification when a webhook secret is set", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		referenceId := uuid.NewString()
+
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			err = payment_gateway.VerifyWebhook("webhook-secret", r.Header.Get(payment_gateway.WebhookSignatureHeader), payment_gateway.WebhookRequest{
+				Method: r.Method,
+				Target: r.URL.RequestURI(),
+				Body:   body,
+			}, time.Now(), time.Minute)
+			assert.NoError(t, err)
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer webhook.Close()
+
+		server := NewServer(WithWebhook(webhook.URL, nil), WithWebhookSecret("webhook-secret"))
+		defer server.Close()
+
+		// Act
+		status, err := server.Notify(ctx, referenceId, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, status)
+	})
+
+	t.Run("Should return an error when the webhook cannot be reached", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		webhook := httptest.NewServer(http.NotFoundHandler())
+		webhook.Close()
+
+		server := NewServer(WithWebhook(webhook.URL, nil))
+		defer server.Close()
+
+		// Act
+		_, err := server.Notify(ctx, uuid.NewString(), false)
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestRefundCharge(t *testing.T) {
+	newApprovedCharge := func(t *testing.T, ctx context.Context, amount money.Money) (*Server, *payment_gateway.Charge) {
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+		t.Cleanup(webhook.Close)
+
+		server := NewServer(WithWebhook(webhook.URL, nil))
+		t.Cleanup(server.Close)
+
+		charge, err := newGatewayService(server, "").CreateCharge(ctx, payment_gateway.ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      amount,
+		})
+		assert.NoError(t, err)
+
+		_, err = server.Notify(ctx, charge.ReferenceId, true)
+		assert.NoError(t, err)
+
+		return server, charge
+	}
+
+	t.Run("Should refund an approved charge", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server, charge := newApprovedCharge(t, ctx, money.New(1000, money.BRL))
+
+		referenceId := uuid.NewString()
+
+		// Act
+		refund, err := newGatewayService(server, "").RefundCharge(ctx, payment_gateway.RefundRequest{
+			ReferenceId: referenceId,
+			ChargeId:    charge.Id,
+			Amount:      money.New(400, money.BRL),
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, refund.Id)
+		assert.Equal(t, charge.Id, refund.ChargeId)
+		assert.Equal(t, payment_gateway.RefundStatusSucceeded, refund.Status)
+
+		stored, ok := server.Refund(referenceId)
+		assert.True(t, ok)
+		assert.Equal(t, refund.Id, stored.Id)
+	})
+
+	t.Run("Should return the same refund for the same reference", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server, charge := newApprovedCharge(t, ctx, money.New(1000, money.BRL))
+
+		service := newGatewayService(server, "")
+
+		request := payment_gateway.RefundRequest{
+			ReferenceId: uuid.NewString(),
+			ChargeId:    charge.Id,
+			Amount:      money.New(1000, money.BRL),
+		}
+
+		first, err := service.RefundCharge(ctx, request)
+		assert.NoError(t, err)
+
+		// Act
+		second, err := service.RefundCharge(ctx, request)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, first.Id, second.Id)
+	})
+
+	t.Run("Should reject a refund greater than the remaining amount", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server, charge := newApprovedCharge(t, ctx, money.New(1000, money.BRL))
+
+		service := newGatewayService(server, "")
+
+		_, err := service.RefundCharge(ctx, payment_gateway.RefundRequest{
+			ReferenceId: uuid.NewString(),
+			ChargeId:    charge.Id,
+			Amount:      money.New(600, money.BRL),
+		})
+		assert.NoError(t, err)
+
+		// Act
+		refund, err := service.RefundCharge(ctx, payment_gateway.RefundRequest{
+			ReferenceId: uuid.NewString(),
+			ChargeId:    charge.Id,
+			Amount:      money.New(500, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayRequestNotValid)
+		assert.Nil(t, refund)
+	})
+
+	t.Run("Should reject a refund of a charge that is not approved", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := NewServer()
+		defer server.Close()
+
+		service := newGatewayService(server, "")
+
+		charge, err := service.CreateCharge(ctx, payment_gateway.ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(1000, money.BRL),
+		})
+		assert.NoError(t, err)
+
+		// Act
+		refund, err := service.RefundCharge(ctx, payment_gateway.RefundRequest{
+			ReferenceId: uuid.NewString(),
+			ChargeId:    charge.Id,
+			Amount:      money.New(1000, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayRequestNotValid)
+		assert.Nil(t, refund)
+	})
+}
@@ -0,0 +1,112 @@
+package payment_gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type HttpGatewayService struct {
+	baseUrl string
+	apiKey  string
+	client  *http.Client
+}
+
+func NewHttpGatewayService(config *environment.GatewayConfig) GatewayService {
+	return &HttpGatewayService{
+		baseUrl: strings.TrimSuffix(config.BaseUrl, "/"),
+		apiKey:  config.ApiKey,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			// propagates the trace context to the gateway
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (s *HttpGatewayService) CreateCharge(ctx context.Context, request ChargeRequest) (*Charge, error) {
+	var charge Charge
+
+	if err := s.send(ctx, "/v1/charges", request.ReferenceId, request, &charge); err != nil {
+		return nil, err
+	}
+
+	return &charge, nil
+}
+
+func (s *HttpGatewayService) RefundCharge(ctx context.Context, request RefundRequest) (*Refund, error) {
+	var refund Refund
+
+	if err := s.send(ctx, fmt.Sprintf("/v1/charges/%s/refunds", url.PathEscape(request.ChargeId)), request.ReferenceId, request, &refund); err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+func (s *HttpGatewayService) send(ctx context.Context, path string, idempotencyKey string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", s.baseUrl, path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		slog.ErrorContext(ctx, "error sending request to gateway", "path", path, "reference_id", idempotencyKey, "error", err)
+		return custom_error.ErrGatewayUnavailable
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return mapError(ctx, res)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
+		slog.ErrorContext(ctx, "error decoding gateway response", "path", path, "reference_id", idempotencyKey, "error", err)
+		return custom_error.ErrGatewayUnavailable
+	}
+
+	return nil
+}
+
+func mapError(ctx context.Context, res *http.Response) error {
+	var errResponse ErrorResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&errResponse); err != nil {
+		slog.WarnContext(ctx, "unable to decode gateway error response", "status", res.StatusCode, "error", err)
+	}
+
+	slog.ErrorContext(ctx, "gateway returned an error", "status", res.StatusCode, "code", errResponse.Code, "message", errResponse.Message)
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		return custom_error.ErrGatewayUnauthorized
+	case res.StatusCode == http.StatusRequestTimeout ||
+		res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode >= http.StatusInternalServerError:
+		return custom_error.ErrGatewayUnavailable
+	default:
+		return custom_error.ErrGatewayRequestNotValid
+	}
+}
@@ -0,0 +1,234 @@
+package payment_gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCharge(t *testing.T) {
+	t.Run("Should create a charge", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		request := ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			OrderId:     uuid.NewString(),
+			Amount:      money.New(1050, money.BRL),
+			Method:      MethodPix,
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/charges", r.URL.Path)
+			assert.Equal(t, "Bearer api-key", r.Header.Get("Authorization"))
+			assert.Equal(t, request.ReferenceId, r.Header.Get("Idempotency-Key"))
+
+			var body ChargeRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, request, body)
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(Charge{
+				Id:          "charge_id",
+				ReferenceId: request.ReferenceId,
+				Amount:      request.Amount,
+				Status:      ChargeStatusPending,
+				QrCode:      "qr_code",
+			})
+		}))
+		defer server.Close()
+
+		service := NewHttpGatewayService(&environment.GatewayConfig{
+			BaseUrl: server.URL,
+			ApiKey:  "api-key",
+			Timeout: time.Second,
+		})
+
+		// Act
+		charge, err := service.CreateCharge(ctx, request)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "charge_id", charge.Id)
+		assert.Equal(t, "qr_code", charge.QrCode)
+	})
+
+	t.Run("Should map the gateway errors to business errors", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		cases := []struct {
+			status   int
+			expected error
+		}{
+			{http.StatusBadRequest, custom_error.ErrGatewayRequestNotValid},
+			{http.StatusUnprocessableEntity, custom_error.ErrGatewayRequestNotValid},
+			{http.StatusUnauthorized, custom_error.ErrGatewayUnauthorized},
+			{http.StatusForbidden, custom_error.ErrGatewayUnauthorized},
+			{http.StatusTooManyRequests, custom_error.ErrGatewayUnavailable},
+			{http.StatusInternalServerError, custom_error.ErrGatewayUnavailable},
+			{http.StatusServiceUnavailable, custom_error.ErrGatewayUnavailable},
+		}
+
+		for _, c := range cases {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				_ = json.NewEncoder(w).Encode(ErrorResponse{
+					Code:    "error",
+					Message: "error",
+				})
+			}))
+
+			service := NewHttpGatewayService(&environment.GatewayConfig{
+				BaseUrl: server.URL,
+				Timeout: time.Second,
+			})
+
+			// Act
+			charge, err := service.CreateCharge(ctx, ChargeRequest{
+				ReferenceId: uuid.NewString(),
+				Amount:      money.New(1000, money.BRL),
+			})
+
+			// Assert
+			assert.ErrorIs(t, err, c.expected)
+			assert.Nil(t, charge)
+
+			server.Close()
+		}
+	})
+
+	t.Run("Should return unavailable error when the gateway cannot be reached", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		service := NewHttpGatewayService(&environment.GatewayConfig{
+			BaseUrl: server.URL,
+			Timeout: time.Second,
+		})
+
+		// Act
+		charge, err := service.CreateCharge(ctx, ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(1000, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayUnavailable)
+		assert.Nil(t, charge)
+	})
+
+	t.Run("Should return unavailable error when the response is not valid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("not a json"))
+		}))
+		defer server.Close()
+
+		service := NewHttpGatewayService(&environment.GatewayConfig{
+			BaseUrl: server.URL,
+			Timeout: time.Second,
+		})
+
+		// Act
+		charge, err := service.CreateCharge(ctx, ChargeRequest{
+			ReferenceId: uuid.NewString(),
+			Amount:      money.New(1000, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayUnavailable)
+		assert.Nil(t, charge)
+	})
+}
+
+func TestRefundCharge(t *testing.T) {
+	t.Run("Should refund a charge", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		request := RefundRequest{
+			ReferenceId: uuid.NewString(),
+			ChargeId:    "charge_id",
+			Amount:      money.New(525, money.BRL),
+			Reason:      "order cancelled",
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/charges/charge_id/refunds", r.URL.Path)
+			assert.Equal(t, "Bearer api-key", r.Header.Get("Authorization"))
+			assert.Equal(t, request.ReferenceId, r.Header.Get("Idempotency-Key"))
+
+			var body RefundRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, request, body)
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(Refund{
+				Id:          "refund_id",
+				ReferenceId: request.ReferenceId,
+				ChargeId:    request.ChargeId,
+				Amount:      request.Amount,
+				Status:      RefundStatusSucceeded,
+			})
+		}))
+		defer server.Close()
+
+		service := NewHttpGatewayService(&environment.GatewayConfig{
+			BaseUrl: server.URL,
+			ApiKey:  "api-key",
+			Timeout: time.Second,
+		})
+
+		// Act
+		refund, err := service.RefundCharge(ctx, request)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "refund_id", refund.Id)
+		assert.Equal(t, RefundStatusSucceeded, refund.Status)
+	})
+
+	t.Run("Should map the gateway errors to business errors", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}))
+		defer server.Close()
+
+		service := NewHttpGatewayService(&environment.GatewayConfig{
+			BaseUrl: server.URL,
+			Timeout: time.Second,
+		})
+
+		// Act
+		refund, err := service.RefundCharge(ctx, RefundRequest{
+			ReferenceId: uuid.NewString(),
+			ChargeId:    "charge_id",
+			Amount:      money.New(1000, money.BRL),
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayRequestNotValid)
+		assert.Nil(t, refund)
+	})
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.42.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	payment_gateway "github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockGatewayService is an autogenerated mock type for the GatewayService type
+type MockGatewayService struct {
+	mock.Mock
+}
+
+// CreateCharge provides a mock function with given fields: ctx, request
+func (_m *MockGatewayService) CreateCharge(ctx context.Context, request payment_gateway.ChargeRequest) (*payment_gateway.Charge, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCharge")
+	}
+
+	var r0 *payment_gateway.Charge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payment_gateway.ChargeRequest) (*payment_gateway.Charge, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payment_gateway.ChargeRequest) *payment_gateway.Charge); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payment_gateway.Charge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payment_gateway.ChargeRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefundCharge provides a mock function with given fields: ctx, request
+func (_m *MockGatewayService) RefundCharge(ctx context.Context, request payment_gateway.RefundRequest) (*payment_gateway.Refund, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefundCharge")
+	}
+
+	var r0 *payment_gateway.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payment_gateway.RefundRequest) (*payment_gateway.Refund, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payment_gateway.RefundRequest) *payment_gateway.Refund); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*payment_gateway.Refund)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payment_gateway.RefundRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockGatewayService creates a new instance of MockGatewayService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGatewayService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGatewayService {
+	mock := &MockGatewayService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,61 @@
+package payment_gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+const (
+	MethodPix = "pix"
+
+	ChargeStatusPending  = "pending"
+	ChargeStatusApproved = "approved"
+	ChargeStatusRejected = "rejected"
+
+	RefundStatusSucceeded = "succeeded"
+)
+
+type ChargeRequest struct {
+	ReferenceId string      `json:"reference_id"`
+	OrderId     string      `json:"order_id"`
+	Amount      money.Money `json:"amount"`
+	Method      string      `json:"method"`
+}
+
+type Charge struct {
+	Id          string      `json:"id"`
+	ReferenceId string      `json:"reference_id"`
+	Amount      money.Money `json:"amount"`
+	Method      string      `json:"method"`
+	Status      string      `json:"status"`
+	QrCode      string      `json:"qr_code"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type RefundRequest struct {
+	ReferenceId string      `json:"reference_id"`
+	ChargeId    string      `json:"charge_id"`
+	Amount      money.Money `json:"amount"`
+	Reason      string      `json:"reason,omitempty"`
+}
+
+type Refund struct {
+	Id          string      `json:"id"`
+	ReferenceId string      `json:"reference_id"`
+	ChargeId    string      `json:"charge_id"`
+	Amount      money.Money `json:"amount"`
+	Status      string      `json:"status"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type GatewayService interface {
+	CreateCharge(ctx context.Context, request ChargeRequest) (*Charge, error)
+	RefundCharge(ctx context.Context, request RefundRequest) (*Refund, error)
+}
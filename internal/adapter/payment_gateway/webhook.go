@@ -0,0 +1,98 @@
+package payment_gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader carries the timestamp and the signatures of a webhook
+// notification, formatted as t=<unix timestamp>,v1=<hex hmac-sha256>
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+var (
+	ErrWebhookSignatureMissing  = errors.New("webhook signature is missing")
+	ErrWebhookSignatureMismatch = errors.New("webhook signature does not match")
+	ErrWebhookTimestampExpired  = errors.New("webhook timestamp is outside the tolerance window")
+)
+
+// WebhookRequest is the part of a notification covered by the signature, the target is
+// the path and query it was sent to, so the notification of a payment is not valid for another
+type WebhookRequest struct {
+	Method string
+	Target string
+	Body   []byte
+}
+
+// SignWebhook returns the signature header value for a request sent at the given time
+func SignWebhook(secret string, timestamp time.Time, request WebhookRequest) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), hex.EncodeToString(computeSignature(secret, timestamp.Unix(), request)))
+}
+
+// VerifyWebhook checks that one of the signatures of the header was made with the secret
+// and that the timestamp is within the tolerance, so captured notifications cannot be replayed
+func VerifyWebhook(secret string, header string, request WebhookRequest, now time.Time, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures [][]byte
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrWebhookSignatureMissing
+			}
+			timestamp = parsed
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrWebhookSignatureMissing
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrWebhookTimestampExpired
+	}
+
+	expected := computeSignature(secret, timestamp, request)
+
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+
+	return ErrWebhookSignatureMismatch
+}
+
+// computeSignature signs "<timestamp>.<method>\n<target>\n<body>", neither the method nor
+// the target can hold a line break, so the fields cannot be shifted from one to another
+func computeSignature(secret string, timestamp int64, request WebhookRequest) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(strings.ToUpper(request.Method)))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(request.Target))
+	mac.Write([]byte("\n"))
+	mac.Write(request.Body)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,138 @@
+package payment_gateway
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	now := time.Date(2024, 5, 19, 2, 1, 36, 0, time.UTC)
+	request := WebhookRequest{
+		Method: "PATCH",
+		Target: "/api/v1/payments/webhook/payment-id",
+		Body:   []byte(`{"approved":true}`),
+	}
+
+	t.Run("Should accept a signature made with the secret", func(t *testing.T) {
+		// Arrange
+		header := SignWebhook("secret", now.Add(-time.Minute), request)
+
+		// Act
+		err := VerifyWebhook("secret", header, request, now, 5*time.Minute)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should accept when any of the signatures matches", func(t *testing.T) {
+		// Arrange
+		header := fmt.Sprintf("t=%d,v1=%s,v1=%s",
+			now.Unix(),
+			hex.EncodeToString(computeSignature("old-secret", now.Unix(), request)),
+			hex.EncodeToString(computeSignature("secret", now.Unix(), request)))
+
+		// Act
+		err := VerifyWebhook("secret", header, request, now, 5*time.Minute)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should reject a signature made with another secret", func(t *testing.T) {
+		// Arrange
+		header := SignWebhook("other-secret", now, request)
+
+		// Act
+		err := VerifyWebhook("secret", header, request, now, 5*time.Minute)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrWebhookSignatureMismatch)
+	})
+
+	t.Run("Should reject a body that was tampered", func(t *testing.T) {
+		// Arrange
+		header := SignWebhook("secret", now, request)
+
+		tampered := request
+		tampered.Body = []byte(`{"approved":false}`)
+
+		// Act
+		err := VerifyWebhook("secret", header, tampered, now, 5*time.Minute)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrWebhookSignatureMismatch)
+	})
+
+	t.Run("Should reject a signature sent to another target or method", func(t *testing.T) {
+		// Arrange
+		header := SignWebhook("secret", now, request)
+
+		otherPayment := request
+		otherPayment.Target = "/api/v1/payments/webhook/other-payment-id"
+
+		withQuery := request
+		withQuery.Target = request.Target + "?resend=true"
+
+		otherMethod := request
+		otherMethod.Method = "POST"
+
+		for _, other := range []WebhookRequest{otherPayment, withQuery, otherMethod} {
+			// Act
+			err := VerifyWebhook("secret", header, other, now, 5*time.Minute)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrWebhookSignatureMismatch, other)
+		}
+	})
+
+	t.Run("Should accept the method in any case", func(t *testing.T) {
+		// Arrange
+		header := SignWebhook("secret", now, request)
+
+		lower := request
+		lower.Method = "patch"
+
+		// Act
+		err := VerifyWebhook("secret", header, lower, now, 5*time.Minute)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should reject a timestamp outside the tolerance window", func(t *testing.T) {
+		// Arrange
+		past := SignWebhook("secret", now.Add(-6*time.Minute), request)
+		future := SignWebhook("secret", now.Add(6*time.Minute), request)
+
+		// Act
+		errPast := VerifyWebhook("secret", past, request, now, 5*time.Minute)
+		errFuture := VerifyWebhook("secret", future, request, now, 5*time.Minute)
+
+		// Assert
+		assert.ErrorIs(t, errPast, ErrWebhookTimestampExpired)
+		assert.ErrorIs(t, errFuture, ErrWebhookTimestampExpired)
+	})
+
+	t.Run("Should reject a header without timestamp or signature", func(t *testing.T) {
+		// Arrange
+		headers := []string{
+			"",
+			"v1=abc",
+			fmt.Sprintf("t=%d", now.Unix()),
+			fmt.Sprintf("t=%d,v1=not-hex", now.Unix()),
+			"t=abc,v1=abcd",
+		}
+
+		for _, header := range headers {
+			// Act
+			err := VerifyWebhook("secret", header, request, now, 5*time.Minute)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrWebhookSignatureMissing, header)
+		}
+	})
+}
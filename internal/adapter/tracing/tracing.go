@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/jfelipearaujo-org/ms-payment-management"
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOtlp   = "otlp"
+)
+
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Tracer returns the tracer of the service from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// NewTracerProvider builds the provider of the configured exporter and sets it, along with
+// the W3C trace context propagator, as the global ones
+func NewTracerProvider(ctx context.Context, config *environment.TracingConfig) (*sdktrace.TracerProvider, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}
+
+	switch config.Exporter {
+	case "", ExporterNone:
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case ExporterOtlp:
+		exporter, err := otlptracehttp.New(ctx, otlpOptions(config)...)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExporter, config.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+
+	setGlobals(provider)
+
+	return provider, nil
+}
+
+func otlpOptions(config *environment.TracingConfig) []otlptracehttp.Option {
+	var options []otlptracehttp.Option
+
+	if config.Endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpoint(config.Endpoint))
+	}
+
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	return options
+}
+
+// NewSyncTracerProvider exports every span to the exporter as soon as it ends, so the tests
+// can assert on the spans of an in-memory exporter, it is also set as the global provider
+func NewSyncTracerProvider(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	setGlobals(provider)
+
+	return provider
+}
+
+func setGlobals(provider *sdktrace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Inject serializes the trace context, so the work can be continued later by a worker,
+// it is empty when there is no trace
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(carrier)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+// Extract continues the trace context serialized by Inject, the context
+// is returned unchanged when there is none
+func Extract(ctx context.Context, traceContext string) context.Context {
+	if traceContext == "" {
+		return ctx
+	}
+
+	var carrier propagation.MapCarrier
+
+	if err := json.Unmarshal([]byte(traceContext), &carrier); err != nil {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
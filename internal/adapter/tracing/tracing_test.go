@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Run("Should create a provider for each exporter", func(t *testing.T) {
+		for _, exporter := range []string{"", ExporterNone, ExporterStdout, ExporterOtlp} {
+			// Arrange
+			ctx := context.Background()
+
+			config := &environment.TracingConfig{
+				Exporter:    exporter,
+				Endpoint:    "localhost:4318",
+				Insecure:    true,
+				ServiceName: "ms-payment-management",
+				SampleRatio: 1,
+			}
+
+			// Act
+			provider, err := NewTracerProvider(ctx, config)
+
+			// Assert
+			assert.NoError(t, err, exporter)
+			assert.NotNil(t, provider, exporter)
+			assert.Equal(t, provider, otel.GetTracerProvider(), exporter)
+			assert.NoError(t, provider.Shutdown(ctx), exporter)
+		}
+	})
+
+	t.Run("Should return error when the exporter is unknown", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		config := &environment.TracingConfig{
+			Exporter: "jaeger",
+		}
+
+		// Act
+		provider, err := NewTracerProvider(ctx, config)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrUnknownExporter)
+		assert.Nil(t, provider)
+	})
+}
+
+func TestNewSyncTracerProvider(t *testing.T) {
+	t.Run("Should export the ended spans", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		exporter := tracetest.NewInMemoryExporter()
+
+		provider := NewSyncTracerProvider(exporter)
+		defer provider.Shutdown(ctx)
+
+		// Act
+		_, span := Tracer().Start(ctx, "operation")
+		span.End()
+
+		// Assert
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "operation", spans[0].Name)
+	})
+}
+
+func TestInjectExtract(t *testing.T) {
+	t.Run("Should continue the injected trace", func(t *testing.T) {
+		// Arrange
+		provider := NewSyncTracerProvider(tracetest.NewInMemoryExporter())
+		defer provider.Shutdown(context.Background())
+
+		ctx, span := Tracer().Start(context.Background(), "operation")
+		defer span.End()
+
+		// Act
+		traceContext := Inject(ctx)
+		continued := Extract(context.Background(), traceContext)
+
+		// Assert
+		assert.NotEmpty(t, traceContext)
+		assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(continued).TraceID())
+	})
+
+	t.Run("Should return the context unchanged when there is no trace", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		// Act
+		empty := Extract(ctx, "")
+		invalid := Extract(ctx, "not json")
+
+		// Assert
+		assert.Equal(t, ctx, empty)
+		assert.Equal(t, ctx, invalid)
+	})
+}
@@ -0,0 +1,35 @@
+package inbox_entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event records a notification already processed, keeping the response
+// given to it so a redelivery can be answered the same way
+type Event struct {
+	Id        string `json:"id"`
+	PaymentId string `json:"payment_id"`
+	Response  string `json:"response"`
+
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+func NewEvent(id string, paymentId string, response interface{}, now time.Time) (Event, error) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Id:        id,
+		PaymentId: paymentId,
+		Response:  string(body),
+
+		ProcessedAt: now,
+	}, nil
+}
+
+func (e *Event) DecodeResponse(response interface{}) error {
+	return json.Unmarshal([]byte(e.Response), response)
+}
@@ -0,0 +1,50 @@
+package inbox_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEvent(t *testing.T) {
+	t.Run("Should create a new event with the encoded response", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		// Act
+		event, err := NewEvent("event_id", "payment_id", map[string]string{"state_title": "Approved"}, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "event_id", event.Id)
+		assert.Equal(t, "payment_id", event.PaymentId)
+		assert.JSONEq(t, `{"state_title":"Approved"}`, event.Response)
+		assert.Equal(t, now, event.ProcessedAt)
+	})
+
+	t.Run("Should return error when the response cannot be encoded", func(t *testing.T) {
+		// Act
+		_, err := NewEvent("event_id", "payment_id", make(chan int), time.Now())
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeResponse(t *testing.T) {
+	t.Run("Should decode the stored response", func(t *testing.T) {
+		// Arrange
+		event, err := NewEvent("event_id", "payment_id", map[string]string{"state_title": "Approved"}, time.Now())
+		assert.NoError(t, err)
+
+		var response map[string]string
+
+		// Act
+		err = event.DecodeResponse(&response)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Approved", response["state_title"])
+	})
+}
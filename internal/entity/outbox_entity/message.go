@@ -0,0 +1,57 @@
+package outbox_entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Message struct {
+	Id      string `json:"id"`
+	Topic   string `json:"topic"`
+	Payload string `json:"payload"`
+
+	// TraceContext is the serialized trace of the change that originated the message
+	TraceContext string `json:"trace_context"`
+
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `json:"sent_at"`
+}
+
+func NewMessage(topic string, payload interface{}, now time.Time) (Message, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Id:      uuid.NewString(),
+		Topic:   topic,
+		Payload: string(body),
+
+		NextAttemptAt: now,
+
+		CreatedAt: now,
+	}, nil
+}
+
+func (m *Message) MarkAsSent(now time.Time) {
+	m.Attempts++
+	m.LastError = ""
+	m.SentAt = &now
+}
+
+func (m *Message) MarkAsFailed(err error, nextAttemptAt time.Time) {
+	m.Attempts++
+	m.LastError = err.Error()
+	m.NextAttemptAt = nextAttemptAt
+}
+
+func (m *Message) IsSent() bool {
+	return m.SentAt != nil
+}
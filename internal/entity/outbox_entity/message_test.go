@@ -0,0 +1,83 @@
+package outbox_entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessage(t *testing.T) {
+	t.Run("Should create a message with the payload as json", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		payload := map[string]string{
+			"order_id": "order_id",
+		}
+
+		// Act
+		message, err := NewMessage("topic", payload, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, message.Id)
+		assert.Equal(t, "topic", message.Topic)
+		assert.JSONEq(t, `{"order_id":"order_id"}`, message.Payload)
+		assert.Equal(t, now, message.NextAttemptAt)
+		assert.Equal(t, now, message.CreatedAt)
+		assert.False(t, message.IsSent())
+	})
+
+	t.Run("Should return error if the payload cannot be marshalled", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		// Act
+		_, err := NewMessage("topic", make(chan int), now)
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestMarkAsSent(t *testing.T) {
+	t.Run("Should mark the message as sent", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		message, err := NewMessage("topic", "payload", now)
+		assert.NoError(t, err)
+
+		message.LastError = "error"
+
+		// Act
+		message.MarkAsSent(now)
+
+		// Assert
+		assert.True(t, message.IsSent())
+		assert.Equal(t, 1, message.Attempts)
+		assert.Empty(t, message.LastError)
+	})
+}
+
+func TestMarkAsFailed(t *testing.T) {
+	t.Run("Should mark the message as failed", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+		next := now.Add(time.Minute)
+
+		message, err := NewMessage("topic", "payload", now)
+		assert.NoError(t, err)
+
+		// Act
+		message.MarkAsFailed(errors.New("error"), next)
+
+		// Assert
+		assert.False(t, message.IsSent())
+		assert.Equal(t, 1, message.Attempts)
+		assert.Equal(t, "error", message.LastError)
+		assert.Equal(t, next, message.NextAttemptAt)
+	})
+}
@@ -1,6 +1,10 @@
 package payment_entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
 
 type Payment struct {
 	OrderId   string `json:"order_id"`
@@ -9,15 +13,28 @@ type Payment struct {
 	Items []PaymentItem `json:"items"`
 
 	TotalItems int          `json:"total_items"`
-	Amount     float64      `json:"amount"`
+	Amount     money.Money  `json:"amount"`
 	State      PaymentState `json:"state"`
 	StateTitle string       `json:"state_title"`
 
+	RefundedAmount money.Money `json:"refunded_amount"`
+
+	ChargeId string `json:"charge_id,omitempty"`
+	QrCode   string `json:"qr_code,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version is incremented on every update, an update made with
+	// an outdated version is rejected instead of overwriting a concurrent one
+	Version int `json:"-"`
+
+	// StateChanges holds the transitions not yet persisted,
+	// they are stored in the same transaction as the payment
+	StateChanges []StateChange `json:"-"`
 }
 
-func NewPayment(orderId string, paymentId string, items []PaymentItem, totalItems int, amount float64, now time.Time) Payment {
+func NewPayment(orderId string, paymentId string, items []PaymentItem, totalItems int, amount money.Money, now time.Time) Payment {
 	return Payment{
 		OrderId:   orderId,
 		PaymentId: paymentId,
@@ -47,13 +64,56 @@ func (p *Payment) RefreshStateTitle() {
 	p.StateTitle = p.State.String()
 }
 
-func (p *Payment) UpdateState(newState PaymentState, now time.Time) {
-	p.State = newState
-	p.StateTitle = p.State.String()
+// TransitionTo moves the payment to the given state through the state machine,
+// returning an InvalidTransitionError or a TransitionRejectedError when it is not allowed
+func (p *Payment) TransitionTo(to PaymentState, now time.Time, origin ChangeOrigin) error {
+	return defaultStateMachine.Transition(p, to, now, origin)
+}
+
+func (p *Payment) ClearStateChanges() {
+	p.StateChanges = nil
+}
+
+func (p *Payment) SetCharge(chargeId string, qrCode string, now time.Time) {
+	p.ChargeId = chargeId
+	p.QrCode = qrCode
 
 	p.UpdatedAt = now
 }
 
+func (p *Payment) HasCharge() bool {
+	return p.ChargeId != ""
+}
+
+func (p *Payment) RemainingAmount() money.Money {
+	return p.Amount.Sub(p.RefundedAmount)
+}
+
+func (p *Payment) IsRefundable() bool {
+	return p.IsInState(Approved, PartiallyRefunded) && p.HasCharge() && p.RemainingAmount().IsPositive()
+}
+
+// ApplyRefund accumulates the refunded amount, moving the payment to
+// Refunded when nothing is left to refund or to PartiallyRefunded otherwise,
+// the refunded amount is kept when the transition is not allowed
+func (p *Payment) ApplyRefund(amount money.Money, now time.Time, origin ChangeOrigin) error {
+	refundedAmount := p.RefundedAmount
+
+	p.RefundedAmount = p.RefundedAmount.Add(amount)
+
+	to := PartiallyRefunded
+	if !p.RemainingAmount().IsPositive() {
+		to = Refunded
+	}
+
+	if err := p.TransitionTo(to, now, origin); err != nil {
+		p.RefundedAmount = refundedAmount
+		return err
+	}
+
+	return nil
+}
+
 func (p *Payment) Exists() bool {
 	return p.OrderId != "" && p.PaymentId != ""
 }
@@ -1,26 +1,40 @@
 package payment_entity
 
 import (
+	"fmt"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 )
 
 type PaymentItem struct {
 	Id       string `json:"id" validate:"required,uuid4"`
 	Name     string `json:"name" validate:"required"`
 	Quantity int    `json:"quantity" validate:"required,gte=1"`
+
+	UnitPrice money.Money `json:"unit_price" validate:"required,gt=0"`
+	Discount  money.Money `json:"discount" validate:"gte=0"`
+	Total     money.Money `json:"total"`
 }
 
-func NewPaymentItem(id, name string, quantity int) PaymentItem {
+// NewPaymentItem computes the line total as the unit price times the quantity minus the discount,
+// the discount applies to the whole line
+func NewPaymentItem(id, name string, quantity int, unitPrice money.Money, discount money.Money) PaymentItem {
 	return PaymentItem{
 		Id:       id,
 		Name:     name,
 		Quantity: quantity,
+
+		UnitPrice: unitPrice,
+		Discount:  discount,
+		Total:     unitPrice.Multiply(int64(quantity)).Sub(discount),
 	}
 }
 
 func (p *PaymentItem) Validate() error {
 	validator := validator.New()
+	validator.RegisterCustomTypeFunc(money.ValidatorValue, money.Money{})
 
 	if err := validator.Struct(p); err != nil {
 		return custom_error.ErrRequestNotValid
@@ -28,3 +42,29 @@ func (p *PaymentItem) Validate() error {
 
 	return nil
 }
+
+// ReconcileItems checks the totals sent along with the items against the items themselves:
+// the total items must be the sum of the quantities and the amount the sum of the line totals
+func ReconcileItems(items []PaymentItem, totalItems int, amount money.Money) error {
+	quantity := 0
+	total := money.New(0, amount.Currency())
+
+	for _, item := range items {
+		if item.Total.IsNegative() {
+			return fmt.Errorf("%w: discount of item %s exceeds its price", custom_error.ErrPaymentAmountMismatch, item.Id)
+		}
+
+		quantity += item.Quantity
+		total = total.Add(item.Total)
+	}
+
+	if quantity != totalItems {
+		return fmt.Errorf("%w: total items is %d but the items sum %d", custom_error.ErrPaymentAmountMismatch, totalItems, quantity)
+	}
+
+	if total.Cmp(amount) != 0 {
+		return fmt.Errorf("%w: amount is %s but the items sum %s", custom_error.ErrPaymentAmountMismatch, amount, total)
+	}
+
+	return nil
+}
@@ -4,13 +4,25 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestNewPaymentItem(t *testing.T) {
+	t.Run("Should compute the line total", func(t *testing.T) {
+		// Act
+		item := NewPaymentItem(uuid.NewString(), "item1", 3, money.New(1999, money.BRL), money.New(500, money.BRL))
+
+		// Assert
+		assert.Equal(t, money.New(5497, money.BRL), item.Total)
+	})
+}
+
 func TestValidate(t *testing.T) {
 	t.Run("Should return nil if request is valid", func(t *testing.T) {
 		// Arrange
-		item := NewPaymentItem(uuid.NewString(), "item1", 1)
+		item := NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL))
 
 		// Act
 		err := item.Validate()
@@ -21,7 +33,7 @@ func TestValidate(t *testing.T) {
 
 	t.Run("Should return error if request is invalid", func(t *testing.T) {
 		// Arrange
-		item := NewPaymentItem("", "item1", 1)
+		item := NewPaymentItem("", "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL))
 
 		// Act
 		err := item.Validate()
@@ -29,4 +41,70 @@ func TestValidate(t *testing.T) {
 		// Assert
 		assert.Error(t, err)
 	})
+
+	t.Run("Should return error if the unit price is missing", func(t *testing.T) {
+		// Arrange
+		item := NewPaymentItem(uuid.NewString(), "item1", 1, money.New(0, money.BRL), money.New(0, money.BRL))
+
+		// Act
+		err := item.Validate()
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return error if the discount is negative", func(t *testing.T) {
+		// Arrange
+		item := NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(-1, money.BRL))
+
+		// Act
+		err := item.Validate()
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestReconcileItems(t *testing.T) {
+	items := []PaymentItem{
+		NewPaymentItem(uuid.NewString(), "item1", 2, money.New(1999, money.BRL), money.New(0, money.BRL)),
+		NewPaymentItem(uuid.NewString(), "item2", 1, money.New(3999, money.BRL), money.New(1999, money.BRL)),
+	}
+
+	t.Run("Should return nil when the totals match the items", func(t *testing.T) {
+		// Act
+		err := ReconcileItems(items, 3, money.New(5998, money.BRL))
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error when the total items does not match", func(t *testing.T) {
+		// Act
+		err := ReconcileItems(items, 2, money.New(5998, money.BRL))
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentAmountMismatch)
+		assert.Contains(t, err.Error(), "total items is 2 but the items sum 3")
+	})
+
+	t.Run("Should return error when the amount does not match", func(t *testing.T) {
+		// Act
+		err := ReconcileItems(items, 3, money.New(5999, money.BRL))
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentAmountMismatch)
+		assert.Contains(t, err.Error(), "amount is 59.99 but the items sum 59.98")
+	})
+
+	t.Run("Should return error when a discount exceeds the price", func(t *testing.T) {
+		// Arrange
+		item := NewPaymentItem(uuid.NewString(), "item1", 1, money.New(100, money.BRL), money.New(200, money.BRL))
+
+		// Act
+		err := ReconcileItems([]PaymentItem{item}, 1, money.New(-100, money.BRL))
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentAmountMismatch)
+	})
 }
@@ -7,14 +7,20 @@ const (
 	WaitingForApproval              // When the payment request is sent to the payment gateway
 	Approved                        // When the payment is approved by the payment gateway
 	Rejected                        // When the payment is rejected by the payment gateway
+	PartiallyRefunded               // When part of the approved amount is refunded to the customer
+	Refunded                        // When the whole approved amount is refunded to the customer
+	Expired                         // When the payment is not approved or rejected before its TTL
 )
 
 var (
 	payment_state_machine = map[PaymentState][]PaymentState{
 		None:               {WaitingForApproval},
-		WaitingForApproval: {Approved, Rejected},
-		Approved:           {},
+		WaitingForApproval: {Approved, Rejected, Expired},
+		Approved:           {PartiallyRefunded, Refunded},
 		Rejected:           {},
+		PartiallyRefunded:  {PartiallyRefunded, Refunded},
+		Refunded:           {},
+		Expired:            {},
 	}
 )
 
@@ -23,6 +29,9 @@ func NewPaymentState(title string) PaymentState {
 		"WaitingForApproval": WaitingForApproval,
 		"Approved":           Approved,
 		"Rejected":           Rejected,
+		"PartiallyRefunded":  PartiallyRefunded,
+		"Refunded":           Refunded,
+		"Expired":            Expired,
 	}[title]
 	if !ok {
 		return None
@@ -31,21 +40,15 @@ func NewPaymentState(title string) PaymentState {
 	return state
 }
 
-func (s PaymentState) CanTransitionTo(to PaymentState) bool {
-	for _, allowed := range payment_state_machine[s] {
-		if to == allowed {
-			return true
-		}
-	}
-	return false
-}
-
 func (s PaymentState) String() string {
 	text, ok := map[PaymentState]string{
 		None:               "None",
 		WaitingForApproval: "WaitingForApproval",
 		Approved:           "Approved",
 		Rejected:           "Rejected",
+		PartiallyRefunded:  "PartiallyRefunded",
+		Refunded:           "Refunded",
+		Expired:            "Expired",
 	}[s]
 	if !ok {
 		return "Unknown"
@@ -16,6 +16,9 @@ func TestNewPaymentState(t *testing.T) {
 			{"WaitingForApproval", WaitingForApproval},
 			{"Approved", Approved},
 			{"Rejected", Rejected},
+			{"PartiallyRefunded", PartiallyRefunded},
+			{"Refunded", Refunded},
+			{"Expired", Expired},
 		}
 
 		for _, c := range cases {
@@ -50,6 +53,9 @@ func TestString(t *testing.T) {
 			{WaitingForApproval, "WaitingForApproval"},
 			{Approved, "Approved"},
 			{Rejected, "Rejected"},
+			{PartiallyRefunded, "PartiallyRefunded"},
+			{Refunded, "Refunded"},
+			{Expired, "Expired"},
 			{PaymentState(100), "Unknown"},
 		}
 
@@ -62,53 +68,3 @@ func TestString(t *testing.T) {
 		}
 	})
 }
-
-func TestCanTransitionTo(t *testing.T) {
-	t.Run("Should return true when the transition is valid", func(t *testing.T) {
-		// Arrange
-		cases := []struct {
-			from     PaymentState
-			to       PaymentState
-			expected bool
-		}{
-			{None, WaitingForApproval, true},
-			{WaitingForApproval, Approved, true},
-			{WaitingForApproval, Rejected, true},
-			{Approved, Approved, false},
-			{Approved, Rejected, false},
-			{Rejected, Approved, false},
-			{Rejected, Rejected, false},
-		}
-
-		for _, c := range cases {
-			// Act
-			res := c.from.CanTransitionTo(c.to)
-
-			// Assert
-			assert.Equal(t, c.expected, res)
-		}
-	})
-
-	t.Run("Should return false when the transition is invalid", func(t *testing.T) {
-		// Arrange
-		cases := []struct {
-			from     PaymentState
-			to       PaymentState
-			expected bool
-		}{
-			{None, Approved, false},
-			{None, Rejected, false},
-			{WaitingForApproval, None, false},
-			{Approved, None, false},
-			{Rejected, None, false},
-		}
-
-		for _, c := range cases {
-			// Act
-			res := c.from.CanTransitionTo(c.to)
-
-			// Assert
-			assert.Equal(t, c.expected, res)
-		}
-	})
-}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,11 +19,11 @@ func TestIsInState(t *testing.T) {
 
 		for _, state := range states {
 			items := []PaymentItem{
-				NewPaymentItem(uuid.NewString(), "item1", 1),
-				NewPaymentItem(uuid.NewString(), "item2", 1),
+				NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+				NewPaymentItem(uuid.NewString(), "item2", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 			}
 
-			payment := NewPayment("order_id", "payment_id", items, 1, 1.23, now)
+			payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), now)
 			payment.State = state
 
 			// Act
@@ -40,11 +42,11 @@ func TestIsInState(t *testing.T) {
 
 		for _, state := range states {
 			items := []PaymentItem{
-				NewPaymentItem(uuid.NewString(), "item1", 1),
-				NewPaymentItem(uuid.NewString(), "item2", 1),
+				NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+				NewPaymentItem(uuid.NewString(), "item2", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 			}
 
-			payment := NewPayment("order_id", "payment_id", items, 1, 1.23, now)
+			payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), now)
 			payment.State = state
 
 			// Act
@@ -62,11 +64,11 @@ func TestRefreshStateTitle(t *testing.T) {
 		now := time.Now()
 
 		items := []PaymentItem{
-			NewPaymentItem(uuid.NewString(), "item1", 1),
-			NewPaymentItem(uuid.NewString(), "item2", 1),
+			NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+			NewPaymentItem(uuid.NewString(), "item2", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 		}
 
-		payment := NewPayment("order_id", "payment_id", items, 1, 1.23, now)
+		payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), now)
 		payment.State = Approved
 
 		// Act
@@ -77,25 +79,256 @@ func TestRefreshStateTitle(t *testing.T) {
 	})
 }
 
-func TestUpdateState(t *testing.T) {
+func TestTransitionTo(t *testing.T) {
 	t.Run("Should update the state", func(t *testing.T) {
 		// Arrange
 		now := time.Now()
 
 		items := []PaymentItem{
-			NewPaymentItem(uuid.NewString(), "item1", 1),
-			NewPaymentItem(uuid.NewString(), "item2", 1),
+			NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+			NewPaymentItem(uuid.NewString(), "item2", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 		}
 
-		payment := NewPayment("order_id", "payment_id", items, 1, 1.23, now)
+		payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), now)
+
+		origin := ChangeOrigin{
+			Source:  SourceWebhook,
+			Payload: `{"approved":true}`,
+		}
 
 		// Act
-		payment.UpdateState(Approved, now)
+		err := payment.TransitionTo(Approved, now, origin)
 
 		// Assert
+		assert.NoError(t, err)
 		assert.Equal(t, Approved, payment.State)
 		assert.Equal(t, "Approved", payment.StateTitle)
 		assert.Equal(t, now, payment.UpdatedAt)
+		assert.Len(t, payment.StateChanges, 1)
+		assert.Equal(t, "payment_id", payment.StateChanges[0].PaymentId)
+		assert.Equal(t, WaitingForApproval, payment.StateChanges[0].From)
+		assert.Equal(t, Approved, payment.StateChanges[0].To)
+		assert.Equal(t, SourceWebhook, payment.StateChanges[0].Source)
+		assert.Equal(t, `{"approved":true}`, payment.StateChanges[0].Payload)
+		assert.Equal(t, now, payment.StateChanges[0].CreatedAt)
+	})
+
+	t.Run("Should return an error when the state machine does not allow the transition", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		payment := Payment{
+			PaymentId: "payment_id",
+			State:     None,
+		}
+
+		// Act
+		err := payment.TransitionTo(Approved, now, ChangeOrigin{Source: SourceWebhook})
+
+		// Assert
+		var transitionErr *InvalidTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.ErrorIs(t, err, custom_error.ErrPaymentInvalidStateTransition)
+		assert.Equal(t, None, transitionErr.From)
+		assert.Equal(t, Approved, transitionErr.To)
+		assert.Equal(t, None, payment.State)
+		assert.Empty(t, payment.StateChanges)
+		assert.True(t, payment.UpdatedAt.IsZero())
+	})
+
+	t.Run("Should not leave a final state", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(123, money.BRL), time.Now())
+		assert.NoError(t, payment.TransitionTo(Rejected, time.Now(), ChangeOrigin{Source: SourceWebhook}))
+
+		// Act
+		err := payment.TransitionTo(Approved, time.Now(), ChangeOrigin{Source: SourceWebhook})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentInvalidStateTransition)
+		assert.Equal(t, Rejected, payment.State)
+		assert.Len(t, payment.StateChanges, 1)
+	})
+}
+
+func TestClearStateChanges(t *testing.T) {
+	t.Run("Should clear the recorded state changes", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(123, money.BRL), time.Now())
+		assert.NoError(t, payment.TransitionTo(Approved, time.Now(), ChangeOrigin{Source: SourceWebhook}))
+
+		// Act
+		payment.ClearStateChanges()
+
+		// Assert
+		assert.Empty(t, payment.StateChanges)
+		assert.Equal(t, Approved, payment.State)
+	})
+}
+
+func TestSetCharge(t *testing.T) {
+	t.Run("Should set the charge information", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		items := []PaymentItem{
+			NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+		}
+
+		payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), now)
+
+		later := now.Add(time.Minute)
+
+		// Act
+		payment.SetCharge("charge_id", "qr_code", later)
+
+		// Assert
+		assert.Equal(t, "charge_id", payment.ChargeId)
+		assert.Equal(t, "qr_code", payment.QrCode)
+		assert.Equal(t, later, payment.UpdatedAt)
+		assert.True(t, payment.HasCharge())
+	})
+}
+
+func TestHasCharge(t *testing.T) {
+	t.Run("Should return false if the payment has no charge", func(t *testing.T) {
+		// Arrange
+		items := []PaymentItem{
+			NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+		}
+
+		payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), time.Now())
+
+		// Act
+		res := payment.HasCharge()
+
+		// Assert
+		assert.False(t, res)
+	})
+}
+
+func TestIsRefundable(t *testing.T) {
+	t.Run("Should return true if the payment is approved and has a charge", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		payment.SetCharge("charge_id", "qr_code", time.Now())
+		assert.NoError(t, payment.TransitionTo(Approved, time.Now(), ChangeOrigin{Source: SourceWebhook}))
+
+		// Act
+		res := payment.IsRefundable()
+
+		// Assert
+		assert.True(t, res)
+	})
+
+	t.Run("Should return false if the payment is not approved", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		payment.SetCharge("charge_id", "qr_code", time.Now())
+
+		// Act
+		res := payment.IsRefundable()
+
+		// Assert
+		assert.False(t, res)
+	})
+
+	t.Run("Should return false if the payment has no charge", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		assert.NoError(t, payment.TransitionTo(Approved, time.Now(), ChangeOrigin{Source: SourceWebhook}))
+
+		// Act
+		res := payment.IsRefundable()
+
+		// Assert
+		assert.False(t, res)
+	})
+}
+
+func TestApplyRefund(t *testing.T) {
+	t.Run("Should partially refund the payment", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1030, money.BRL), now)
+		payment.SetCharge("charge_id", "qr_code", now)
+		assert.NoError(t, payment.TransitionTo(Approved, now, ChangeOrigin{Source: SourceWebhook}))
+
+		later := now.Add(time.Minute)
+
+		origin := ChangeOrigin{
+			Source:  SourceAdmin,
+			ActorId: "user_id",
+		}
+
+		// Act
+		err := payment.ApplyRefund(money.New(10, money.BRL), later, origin)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, PartiallyRefunded, payment.State)
+		assert.Equal(t, "PartiallyRefunded", payment.StateTitle)
+		assert.Equal(t, money.New(10, money.BRL), payment.RefundedAmount)
+		assert.Equal(t, money.New(1020, money.BRL), payment.RemainingAmount())
+		assert.Equal(t, later, payment.UpdatedAt)
+		assert.Len(t, payment.StateChanges, 2)
+		assert.Equal(t, Approved, payment.StateChanges[1].From)
+		assert.Equal(t, PartiallyRefunded, payment.StateChanges[1].To)
+		assert.Equal(t, SourceAdmin, payment.StateChanges[1].Source)
+		assert.Equal(t, "user_id", payment.StateChanges[1].ActorId)
+	})
+
+	t.Run("Should fully refund the payment after multiple refunds", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(30, money.BRL), now)
+		payment.SetCharge("charge_id", "qr_code", now)
+		assert.NoError(t, payment.TransitionTo(Approved, now, ChangeOrigin{Source: SourceWebhook}))
+
+		// Act
+		errFirst := payment.ApplyRefund(money.New(10, money.BRL), now, ChangeOrigin{Source: SourceAdmin})
+		errSecond := payment.ApplyRefund(money.New(20, money.BRL), now, ChangeOrigin{Source: SourceAdmin})
+
+		// Assert
+		assert.NoError(t, errFirst)
+		assert.NoError(t, errSecond)
+		assert.Equal(t, Refunded, payment.State)
+		assert.Equal(t, money.New(30, money.BRL), payment.RefundedAmount)
+		assert.Zero(t, payment.RemainingAmount())
+	})
+
+	t.Run("Should keep the refunded amount when the payment is not approved", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		payment.SetCharge("charge_id", "qr_code", time.Now())
+
+		// Act
+		err := payment.ApplyRefund(money.New(400, money.BRL), time.Now(), ChangeOrigin{Source: SourceAdmin})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentInvalidStateTransition)
+		assert.Equal(t, WaitingForApproval, payment.State)
+		assert.Zero(t, payment.RefundedAmount)
+	})
+
+	t.Run("Should keep the refunded amount when the refund exceeds the remaining amount", func(t *testing.T) {
+		// Arrange
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		payment.SetCharge("charge_id", "qr_code", time.Now())
+		assert.NoError(t, payment.TransitionTo(Approved, time.Now(), ChangeOrigin{Source: SourceWebhook}))
+
+		// Act
+		err := payment.ApplyRefund(money.New(1100, money.BRL), time.Now(), ChangeOrigin{Source: SourceAdmin})
+
+		// Assert
+		var rejectedErr *TransitionRejectedError
+		assert.ErrorAs(t, err, &rejectedErr)
+		assert.ErrorIs(t, err, custom_error.ErrRefundAmountExceeded)
+		assert.Equal(t, Refunded, rejectedErr.To)
+		assert.Equal(t, Approved, payment.State)
+		assert.Zero(t, payment.RefundedAmount)
 	})
 }
 
@@ -103,11 +336,11 @@ func TestExists(t *testing.T) {
 	t.Run("Should return true if the payment exists", func(t *testing.T) {
 		// Arrange
 		items := []PaymentItem{
-			NewPaymentItem(uuid.NewString(), "item1", 1),
-			NewPaymentItem(uuid.NewString(), "item2", 1),
+			NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+			NewPaymentItem(uuid.NewString(), "item2", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 		}
 
-		payment := NewPayment("order_id", "payment_id", items, 1, 1.23, time.Now())
+		payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), time.Now())
 
 		// Act
 		res := payment.Exists()
@@ -119,11 +352,11 @@ func TestExists(t *testing.T) {
 	t.Run("Should return false if the payment does not exist", func(t *testing.T) {
 		// Arrange
 		items := []PaymentItem{
-			NewPaymentItem(uuid.NewString(), "item1", 1),
-			NewPaymentItem(uuid.NewString(), "item2", 1),
+			NewPaymentItem(uuid.NewString(), "item1", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+			NewPaymentItem(uuid.NewString(), "item2", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 		}
 
-		payment := NewPayment("order_id", "payment_id", items, 1, 1.23, time.Now())
+		payment := NewPayment("order_id", "payment_id", items, 1, money.New(123, money.BRL), time.Now())
 		payment.OrderId = ""
 		payment.PaymentId = ""
 
@@ -0,0 +1,58 @@
+package payment_entity
+
+import (
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByAmount    SortField = "amount"
+)
+
+// SearchKey is the position of a payment in a search, used to continue the search
+// right after it without skipping or repeating payments
+type SearchKey struct {
+	PaymentId string      `json:"payment_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Amount    money.Money `json:"amount"`
+}
+
+func NewSearchKey(payment *Payment) SearchKey {
+	return SearchKey{
+		PaymentId: payment.PaymentId,
+		CreatedAt: payment.CreatedAt,
+		UpdatedAt: payment.UpdatedAt,
+		Amount:    payment.Amount,
+	}
+}
+
+// SearchFilter selects the payments matching every field that is set,
+// sorted by the given field and the payment id
+type SearchFilter struct {
+	States []PaymentState
+
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+
+	MinAmount *money.Money
+	MaxAmount *money.Money
+
+	SortBy     SortField
+	Descending bool
+
+	After *SearchKey
+	Limit int
+}
+
+type SearchPage struct {
+	Payments   []Payment `json:"payments"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,63 @@
+package payment_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeSource string
+
+const (
+	SourceWebhook    ChangeSource = "webhook"
+	SourceExpiration ChangeSource = "expiration"
+	SourceAdmin      ChangeSource = "admin"
+)
+
+// ChangeOrigin tells who caused a state change: the actor is the user of the token
+// when the change was requested through the API, the payload is the raw notification
+// sent by the gateway when it came from a webhook
+type ChangeOrigin struct {
+	Source  ChangeSource
+	ActorId string
+	Payload string
+}
+
+type StateChange struct {
+	Id        string `json:"id"`
+	PaymentId string `json:"payment_id"`
+
+	From      PaymentState `json:"from_state"`
+	FromTitle string       `json:"from_state_title"`
+	To        PaymentState `json:"to_state"`
+	ToTitle   string       `json:"to_state_title"`
+
+	Source  ChangeSource `json:"source"`
+	ActorId string       `json:"actor_id,omitempty"`
+	Payload string       `json:"payload,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewStateChange(paymentId string, from PaymentState, to PaymentState, origin ChangeOrigin, now time.Time) StateChange {
+	return StateChange{
+		Id:        uuid.NewString(),
+		PaymentId: paymentId,
+
+		From:      from,
+		FromTitle: from.String(),
+		To:        to,
+		ToTitle:   to.String(),
+
+		Source:  origin.Source,
+		ActorId: origin.ActorId,
+		Payload: origin.Payload,
+
+		CreatedAt: now,
+	}
+}
+
+func (c *StateChange) RefreshStateTitles() {
+	c.FromTitle = c.From.String()
+	c.ToTitle = c.To.String()
+}
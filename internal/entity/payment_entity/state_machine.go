@@ -0,0 +1,143 @@
+package payment_entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+)
+
+// Guard is consulted before an allowed transition is applied,
+// returning an error vetoes it and leaves the payment untouched
+type Guard func(payment *Payment, to PaymentState) error
+
+// Hook runs after a transition is applied
+type Hook func(payment *Payment, change StateChange)
+
+// InvalidTransitionError is returned when the state machine has no edge between the states
+type InvalidTransitionError struct {
+	From PaymentState
+	To   PaymentState
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s to %s", custom_error.ErrPaymentInvalidStateTransition.Error(), e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Unwrap() error {
+	return custom_error.ErrPaymentInvalidStateTransition
+}
+
+// TransitionRejectedError is returned when a guard vetoes an allowed transition,
+// it wraps the error given by the guard
+type TransitionRejectedError struct {
+	From PaymentState
+	To   PaymentState
+	Err  error
+}
+
+func (e *TransitionRejectedError) Error() string {
+	return fmt.Sprintf("transition from %s to %s rejected: %s", e.From, e.To, e.Err)
+}
+
+func (e *TransitionRejectedError) Unwrap() error {
+	return e.Err
+}
+
+type StateMachine struct {
+	transitions map[PaymentState][]PaymentState
+	guards      map[PaymentState][]Guard
+	hooks       []Hook
+}
+
+type StateMachineOption func(machine *StateMachine)
+
+// WithGuard adds a guard to the transitions into the given state
+func WithGuard(to PaymentState, guard Guard) StateMachineOption {
+	return func(machine *StateMachine) {
+		machine.guards[to] = append(machine.guards[to], guard)
+	}
+}
+
+func WithHook(hook Hook) StateMachineOption {
+	return func(machine *StateMachine) {
+		machine.hooks = append(machine.hooks, hook)
+	}
+}
+
+// NewStateMachine returns the payment state machine with the refund guards,
+// plus the guards and hooks given
+func NewStateMachine(options ...StateMachineOption) *StateMachine {
+	machine := &StateMachine{
+		transitions: payment_state_machine,
+		guards:      make(map[PaymentState][]Guard),
+	}
+
+	WithGuard(PartiallyRefunded, refundGuard)(machine)
+	WithGuard(Refunded, refundGuard)(machine)
+
+	for _, option := range options {
+		option(machine)
+	}
+
+	return machine
+}
+
+var defaultStateMachine = NewStateMachine()
+
+func (m *StateMachine) CanTransition(from PaymentState, to PaymentState) bool {
+	for _, allowed := range m.transitions[from] {
+		if to == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Transition moves the payment to the given state when the state machine allows it
+// and every guard agrees, recording the change and then running the hooks
+func (m *StateMachine) Transition(payment *Payment, to PaymentState, now time.Time, origin ChangeOrigin) error {
+	from := payment.State
+
+	if !m.CanTransition(from, to) {
+		return &InvalidTransitionError{From: from, To: to}
+	}
+
+	for _, guard := range m.guards[to] {
+		if err := guard(payment, to); err != nil {
+			return &TransitionRejectedError{From: from, To: to, Err: err}
+		}
+	}
+
+	change := NewStateChange(payment.PaymentId, from, to, origin, now)
+
+	payment.State = to
+	payment.StateTitle = to.String()
+	payment.UpdatedAt = now
+	payment.StateChanges = append(payment.StateChanges, change)
+
+	for _, hook := range m.hooks {
+		hook(payment, change)
+	}
+
+	return nil
+}
+
+// refundGuard keeps the refund states consistent with the refunded amount:
+// Refunded only when nothing is left, PartiallyRefunded only when something is
+func refundGuard(payment *Payment, to PaymentState) error {
+	if !payment.HasCharge() {
+		return custom_error.ErrPaymentNotRefundable
+	}
+
+	if payment.RemainingAmount().IsNegative() {
+		return custom_error.ErrRefundAmountExceeded
+	}
+
+	if !payment.RefundedAmount.IsPositive() || (to == Refunded) != payment.RemainingAmount().IsZero() {
+		return custom_error.ErrPaymentInvalidStateTransition
+	}
+
+	return nil
+}
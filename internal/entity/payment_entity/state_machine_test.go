@@ -0,0 +1,368 @@
+package payment_entity
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateMachine(t *testing.T) {
+	t.Run("Should run the hooks after the transition", func(t *testing.T) {
+		// Arrange
+		var changes []StateChange
+
+		machine := NewStateMachine(WithHook(func(payment *Payment, change StateChange) {
+			assert.Equal(t, change.To, payment.State)
+			changes = append(changes, change)
+		}))
+
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+
+		// Act
+		err := machine.Transition(&payment, Approved, time.Now(), ChangeOrigin{Source: SourceWebhook})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, WaitingForApproval, changes[0].From)
+		assert.Equal(t, Approved, changes[0].To)
+	})
+
+	t.Run("Should not run the hooks when the transition is not allowed", func(t *testing.T) {
+		// Arrange
+		called := false
+
+		machine := NewStateMachine(WithHook(func(payment *Payment, change StateChange) {
+			called = true
+		}))
+
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+
+		// Act
+		err := machine.Transition(&payment, Refunded, time.Now(), ChangeOrigin{Source: SourceAdmin})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentInvalidStateTransition)
+		assert.False(t, called)
+	})
+
+	t.Run("Should reject the transition when a guard vetoes it", func(t *testing.T) {
+		// Arrange
+		errClosed := errors.New("gateway is closed")
+
+		machine := NewStateMachine(WithGuard(Approved, func(payment *Payment, to PaymentState) error {
+			return errClosed
+		}))
+
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		updatedAt := payment.UpdatedAt
+
+		// Act
+		err := machine.Transition(&payment, Approved, time.Now().Add(time.Minute), ChangeOrigin{Source: SourceWebhook})
+
+		// Assert
+		var rejectedErr *TransitionRejectedError
+		assert.ErrorAs(t, err, &rejectedErr)
+		assert.ErrorIs(t, err, errClosed)
+		assert.Equal(t, WaitingForApproval, rejectedErr.From)
+		assert.Equal(t, Approved, rejectedErr.To)
+		assert.Equal(t, WaitingForApproval, payment.State)
+		assert.Equal(t, updatedAt, payment.UpdatedAt)
+		assert.Empty(t, payment.StateChanges)
+	})
+
+	t.Run("Should only consult the guards of the target state", func(t *testing.T) {
+		// Arrange
+		machine := NewStateMachine(WithGuard(Rejected, func(payment *Payment, to PaymentState) error {
+			return assert.AnError
+		}))
+
+		payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+
+		// Act
+		err := machine.Transition(&payment, Approved, time.Now(), ChangeOrigin{Source: SourceWebhook})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Approved, payment.State)
+	})
+
+	t.Run("Should map the errors to business errors", func(t *testing.T) {
+		// Arrange
+		payment := Payment{State: Expired}
+
+		// Act
+		err := payment.TransitionTo(Approved, time.Now(), ChangeOrigin{Source: SourceWebhook})
+
+		// Assert
+		assert.True(t, custom_error.IsBusinessErr(err))
+		assert.Equal(t, "invalid state transition: Expired to Approved", err.Error())
+	})
+}
+
+func TestCanTransition(t *testing.T) {
+	t.Run("Should return true when the transition is valid", func(t *testing.T) {
+		// Arrange
+		cases := []struct {
+			from     PaymentState
+			to       PaymentState
+			expected bool
+		}{
+			{None, WaitingForApproval, true},
+			{WaitingForApproval, Approved, true},
+			{WaitingForApproval, Rejected, true},
+			{WaitingForApproval, Expired, true},
+			{Approved, PartiallyRefunded, true},
+			{Approved, Refunded, true},
+			{PartiallyRefunded, PartiallyRefunded, true},
+			{PartiallyRefunded, Refunded, true},
+			{Approved, Approved, false},
+			{Approved, Rejected, false},
+			{Rejected, Approved, false},
+			{Rejected, Rejected, false},
+		}
+
+		for _, c := range cases {
+			// Act
+			res := defaultStateMachine.CanTransition(c.from, c.to)
+
+			// Assert
+			assert.Equal(t, c.expected, res)
+		}
+	})
+
+	t.Run("Should return false when the transition is invalid", func(t *testing.T) {
+		// Arrange
+		cases := []struct {
+			from     PaymentState
+			to       PaymentState
+			expected bool
+		}{
+			{None, Approved, false},
+			{None, Rejected, false},
+			{WaitingForApproval, None, false},
+			{Approved, None, false},
+			{Rejected, None, false},
+			{Rejected, Refunded, false},
+			{WaitingForApproval, Refunded, false},
+			{Refunded, PartiallyRefunded, false},
+			{Refunded, Approved, false},
+			{Expired, Approved, false},
+			{Approved, Expired, false},
+		}
+
+		for _, c := range cases {
+			// Act
+			res := defaultStateMachine.CanTransition(c.from, c.to)
+
+			// Assert
+			assert.Equal(t, c.expected, res)
+		}
+	})
+}
+
+func TestRefundGuard(t *testing.T) {
+	t.Run("Should reject a refund of a payment without charge", func(t *testing.T) {
+		// Arrange
+		payment := Payment{Amount: money.New(1000, money.BRL), RefundedAmount: money.New(400, money.BRL)}
+
+		// Act
+		err := refundGuard(&payment, PartiallyRefunded)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentNotRefundable)
+	})
+
+	t.Run("Should reject a state that does not match the refunded amount", func(t *testing.T) {
+		// Arrange
+		partial := Payment{Amount: money.New(1000, money.BRL), RefundedAmount: money.New(400, money.BRL), ChargeId: "charge_id"}
+		full := Payment{Amount: money.New(1000, money.BRL), RefundedAmount: money.New(1000, money.BRL), ChargeId: "charge_id"}
+		none := Payment{Amount: money.New(1000, money.BRL), ChargeId: "charge_id"}
+
+		// Act
+		errPartial := refundGuard(&partial, Refunded)
+		errFull := refundGuard(&full, PartiallyRefunded)
+		errNone := refundGuard(&none, PartiallyRefunded)
+
+		// Assert
+		assert.ErrorIs(t, errPartial, custom_error.ErrPaymentInvalidStateTransition)
+		assert.ErrorIs(t, errFull, custom_error.ErrPaymentInvalidStateTransition)
+		assert.ErrorIs(t, errNone, custom_error.ErrPaymentInvalidStateTransition)
+	})
+}
+
+// operation is a random step applied to a payment: a transition to any state,
+// known or not, or a refund of a random amount
+type operation struct {
+	To     PaymentState
+	Refund money.Money
+}
+
+type operations []operation
+
+func (operations) Generate(r *rand.Rand, size int) reflect.Value {
+	ops := make(operations, r.Intn(size+1))
+
+	for i := range ops {
+		if r.Intn(3) == 0 {
+			ops[i] = operation{Refund: money.New(int64(r.Intn(1200)), money.BRL)}
+			continue
+		}
+
+		ops[i] = operation{To: PaymentState(r.Intn(int(Expired) + 2))}
+	}
+
+	return reflect.ValueOf(ops)
+}
+
+func isFinal(state PaymentState) bool {
+	return len(payment_state_machine[state]) == 0
+}
+
+func TestStateMachineProperties(t *testing.T) {
+	config := &quick.Config{MaxCount: 2000}
+
+	apply := func(payment *Payment, op operation, now time.Time) error {
+		if op.Refund.IsPositive() {
+			return payment.ApplyRefund(op.Refund, now, ChangeOrigin{Source: SourceAdmin})
+		}
+
+		return payment.TransitionTo(op.To, now, ChangeOrigin{Source: SourceWebhook})
+	}
+
+	t.Run("Should leave the payment untouched when a transition fails", func(t *testing.T) {
+		property := func(ops operations) bool {
+			payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+			payment.SetCharge("charge_id", "qr_code", time.Now())
+
+			for i, op := range ops {
+				before := payment
+				before.StateChanges = append([]StateChange(nil), payment.StateChanges...)
+
+				if err := apply(&payment, op, before.UpdatedAt.Add(time.Duration(i+1)*time.Second)); err != nil {
+					if !reflect.DeepEqual(before, payment) {
+						return false
+					}
+
+					var invalidErr *InvalidTransitionError
+					var rejectedErr *TransitionRejectedError
+					if !errors.As(err, &invalidErr) && !errors.As(err, &rejectedErr) {
+						return false
+					}
+				}
+			}
+
+			return true
+		}
+
+		assert.NoError(t, quick.Check(property, config))
+	})
+
+	t.Run("Should only follow the edges of the state machine", func(t *testing.T) {
+		property := func(ops operations) bool {
+			payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+			payment.SetCharge("charge_id", "qr_code", time.Now())
+
+			for _, op := range ops {
+				from := payment.State
+
+				err := apply(&payment, op, time.Now())
+
+				if err == nil && !defaultStateMachine.CanTransition(from, payment.State) {
+					return false
+				}
+
+				// a transition the table does not have must always fail
+				if op.Refund.IsZero() && !defaultStateMachine.CanTransition(from, op.To) && err == nil {
+					return false
+				}
+
+				if isFinal(from) && payment.State != from {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		assert.NoError(t, quick.Check(property, config))
+	})
+
+	t.Run("Should record a chain of changes ending in the current state", func(t *testing.T) {
+		property := func(ops operations) bool {
+			payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+			payment.SetCharge("charge_id", "qr_code", time.Now())
+
+			applied := 0
+
+			for _, op := range ops {
+				if err := apply(&payment, op, time.Now()); err == nil {
+					applied++
+				}
+			}
+
+			if len(payment.StateChanges) != applied {
+				return false
+			}
+
+			from := WaitingForApproval
+
+			for _, change := range payment.StateChanges {
+				if change.From != from || !defaultStateMachine.CanTransition(change.From, change.To) || change.PaymentId != payment.PaymentId {
+					return false
+				}
+
+				from = change.To
+			}
+
+			if applied > 0 && payment.StateTitle != payment.State.String() {
+				return false
+			}
+
+			return from == payment.State
+		}
+
+		assert.NoError(t, quick.Check(property, config))
+	})
+
+	t.Run("Should keep the refunded amount consistent with the state", func(t *testing.T) {
+		property := func(ops operations) bool {
+			payment := NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+			payment.SetCharge("charge_id", "qr_code", time.Now())
+
+			for _, op := range ops {
+				_ = apply(&payment, op, time.Now())
+
+				if payment.RefundedAmount.IsNegative() || payment.RemainingAmount().IsNegative() {
+					return false
+				}
+
+				switch payment.State {
+				case Refunded:
+					if !payment.RemainingAmount().IsZero() {
+						return false
+					}
+				case PartiallyRefunded:
+					if !payment.RefundedAmount.IsPositive() || !payment.RemainingAmount().IsPositive() {
+						return false
+					}
+				default:
+					if !payment.RefundedAmount.IsZero() {
+						return false
+					}
+				}
+			}
+
+			return true
+		}
+
+		assert.NoError(t, quick.Check(property, config))
+	})
+}
@@ -0,0 +1,64 @@
+package refund_entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+type RefundState string
+
+const (
+	// RefundPending is recorded before the gateway is called, so a refund made by the gateway
+	// whose outcome could not be stored is found and settled by the next request
+	RefundPending   RefundState = "Pending"
+	RefundCompleted RefundState = "Completed"
+	RefundFailed    RefundState = "Failed"
+)
+
+type Refund struct {
+	Id        string `json:"id"`
+	OrderId   string `json:"order_id"`
+	PaymentId string `json:"payment_id"`
+
+	Amount money.Money `json:"amount"`
+	Reason string      `json:"reason"`
+	State  RefundState `json:"state"`
+
+	GatewayRefundId string `json:"gateway_refund_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewRefund(orderId string, paymentId string, amount money.Money, reason string, now time.Time) Refund {
+	return Refund{
+		Id:        uuid.NewString(),
+		OrderId:   orderId,
+		PaymentId: paymentId,
+
+		Amount: amount,
+		Reason: reason,
+		State:  RefundPending,
+
+		CreatedAt: now,
+	}
+}
+
+func (r *Refund) Complete(gatewayRefundId string) {
+	r.GatewayRefundId = gatewayRefundId
+	r.State = RefundCompleted
+}
+
+func (r *Refund) Fail() {
+	r.State = RefundFailed
+}
+
+func (r *Refund) IsPending() bool {
+	return r.State == RefundPending
+}
+
+// IsSameRequest tells whether the refund was created by a request asking for the same refund
+func (r *Refund) IsSameRequest(amount money.Money, reason string) bool {
+	return r.Amount == amount && r.Reason == reason
+}
@@ -0,0 +1,82 @@
+package refund_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefund(t *testing.T) {
+	t.Run("Should create a new refund", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		// Act
+		refund := NewRefund("order_id", "payment_id", money.New(1050, money.BRL), "order cancelled", now)
+
+		// Assert
+		assert.NotEmpty(t, refund.Id)
+		assert.Equal(t, "order_id", refund.OrderId)
+		assert.Equal(t, "payment_id", refund.PaymentId)
+		assert.Equal(t, money.New(1050, money.BRL), refund.Amount)
+		assert.Equal(t, "order cancelled", refund.Reason)
+		assert.Empty(t, refund.GatewayRefundId)
+		assert.Equal(t, RefundPending, refund.State)
+		assert.Equal(t, now, refund.CreatedAt)
+	})
+}
+
+func TestComplete(t *testing.T) {
+	t.Run("Should set the gateway refund id and complete the refund", func(t *testing.T) {
+		// Arrange
+		refund := NewRefund("order_id", "payment_id", money.New(1050, money.BRL), "", time.Now())
+
+		// Act
+		refund.Complete("refund_id")
+
+		// Assert
+		assert.Equal(t, "refund_id", refund.GatewayRefundId)
+		assert.Equal(t, RefundCompleted, refund.State)
+		assert.False(t, refund.IsPending())
+	})
+}
+
+func TestFail(t *testing.T) {
+	t.Run("Should fail the refund", func(t *testing.T) {
+		// Arrange
+		refund := NewRefund("order_id", "payment_id", money.New(1050, money.BRL), "", time.Now())
+
+		// Act
+		refund.Fail()
+
+		// Assert
+		assert.Equal(t, RefundFailed, refund.State)
+		assert.False(t, refund.IsPending())
+	})
+}
+
+func TestIsSameRequest(t *testing.T) {
+	t.Run("Should match the request with the same amount and reason", func(t *testing.T) {
+		// Arrange
+		refund := NewRefund("order_id", "payment_id", money.New(1050, money.BRL), "order cancelled", time.Now())
+
+		// Act
+		same := refund.IsSameRequest(money.New(1050, money.BRL), "order cancelled")
+
+		// Assert
+		assert.True(t, same)
+	})
+
+	t.Run("Should not match the request with another amount", func(t *testing.T) {
+		// Arrange
+		refund := NewRefund("order_id", "payment_id", money.New(1050, money.BRL), "order cancelled", time.Now())
+
+		// Act
+		same := refund.IsSameRequest(money.New(1000, money.BRL), "order cancelled")
+
+		// Assert
+		assert.False(t, same)
+	})
+}
@@ -2,6 +2,7 @@ package environment
 
 import (
 	"context"
+	"time"
 )
 
 type ApiConfig struct {
@@ -17,27 +18,127 @@ func (c *ApiConfig) IsDevelopment() bool {
 type DatabaseConfig struct {
 	Url           string `env:"URL, required"`
 	UrlSecretName string `env:"URL_SECRET_NAME, required"`
+
+	MigrateOnStartup bool `env:"MIGRATE_ON_STARTUP, default=false"`
+}
+
+const (
+	CloudDriverAws    = "aws"
+	CloudDriverMemory = "memory"
+)
+
+// CloudEndpointConfig tells how the cloud services are reached, apart from the topics
+// and queues of the server, so the migrations can read the database secret without them
+type CloudEndpointConfig struct {
+	BaseEndpoint string `env:"BASE_ENDPOINT"`
+
+	// Driver selects the AWS services or the in-memory ones used for local development
+	Driver string `env:"DRIVER, default=aws"`
+}
+
+func (c *CloudEndpointConfig) IsBaseEndpointSet() bool {
+	return c.BaseEndpoint != ""
+}
+
+func (c *CloudEndpointConfig) IsMemoryDriver() bool {
+	return c.Driver == CloudDriverMemory
 }
 
 type CloudConfig struct {
 	OrderProductionTopic string `env:"ORDER_PRODUCTION_TOPIC_NAME, required"`
 	UpdateOrderTopic     string `env:"UPDATE_ORDER_TOPIC_NAME, required"`
 	OrderPaymentQueue    string `env:"ORDER_PAYMENT_QUEUE_NAME, required"`
+	OrderPaymentDlq      string `env:"ORDER_PAYMENT_DLQ_NAME, required"`
 
-	BaseEndpoint string `env:"BASE_ENDPOINT"`
+	CloudEndpointConfig
 }
 
-func (c *CloudConfig) IsBaseEndpointSet() bool {
-	return c.BaseEndpoint != ""
+type QueueConfig struct {
+	Concurrency     int           `env:"CONCURRENCY, default=10"`
+	MaxReceiveCount int           `env:"MAX_RECEIVE_COUNT, default=5"`
+	BaseBackoff     time.Duration `env:"BASE_BACKOFF, default=10s"`
+	MaxBackoff      time.Duration `env:"MAX_BACKOFF, default=15m"`
+
+	// HeartbeatTimeout is how long the consumer may go without polling before it is reported unhealthy
+	HeartbeatTimeout time.Duration `env:"HEARTBEAT_TIMEOUT, default=2m"`
+}
+
+type GatewayConfig struct {
+	BaseUrl string        `env:"BASE_URL, required"`
+	ApiKey  string        `env:"API_KEY"`
+	Timeout time.Duration `env:"TIMEOUT, default=10s"`
+
+	WebhookSecret    string        `env:"WEBHOOK_SECRET, required"`
+	WebhookTolerance time.Duration `env:"WEBHOOK_TOLERANCE, default=5m"`
+
+	// WebhookBasePath is the prefix the ingress strips from the path before the webhook
+	// reaches the service, it is added back since the gateway signs the public path
+	WebhookBasePath string `env:"WEBHOOK_BASE_PATH"`
+}
+
+type AuthConfig struct {
+	JwksUrl                string        `env:"JWKS_URL"`
+	JwksCacheTtl           time.Duration `env:"JWKS_CACHE_TTL, default=10m"`
+	JwksMinRefreshInterval time.Duration `env:"JWKS_MIN_REFRESH_INTERVAL, default=1m"`
+	PublicKeys             string        `env:"PUBLIC_KEYS"`
+	Issuer                 string        `env:"ISSUER"`
+	Audience               string        `env:"AUDIENCE"`
+	Leeway                 time.Duration `env:"LEEWAY, default=30s"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `env:"POLL_INTERVAL, default=5s"`
+	BatchSize    int           `env:"BATCH_SIZE, default=10"`
+	LockTimeout  time.Duration `env:"LOCK_TIMEOUT, default=30s"`
+	BaseBackoff  time.Duration `env:"BASE_BACKOFF, default=1s"`
+	MaxBackoff   time.Duration `env:"MAX_BACKOFF, default=5m"`
+}
+
+type ExpirationConfig struct {
+	PaymentTtl   time.Duration `env:"PAYMENT_TTL, default=30m"`
+	PollInterval time.Duration `env:"POLL_INTERVAL, default=1m"`
+	BatchSize    int           `env:"BATCH_SIZE, default=50"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of none, stdout or otlp
+	Exporter    string  `env:"EXPORTER, default=none"`
+	Endpoint    string  `env:"ENDPOINT"`
+	Insecure    bool    `env:"INSECURE, default=false"`
+	ServiceName string  `env:"SERVICE_NAME, default=ms-payment-management"`
+	SampleRatio float64 `env:"SAMPLE_RATIO, default=1"`
+}
+
+type HealthConfig struct {
+	Timeout  time.Duration `env:"TIMEOUT, default=2s"`
+	CacheTtl time.Duration `env:"CACHE_TTL, default=5s"`
 }
 
 type Config struct {
-	ApiConfig   *ApiConfig      `env:",prefix=API_"`
-	DbConfig    *DatabaseConfig `env:",prefix=DB_"`
-	CloudConfig *CloudConfig    `env:",prefix=AWS_"`
+	ApiConfig     *ApiConfig      `env:",prefix=API_"`
+	DbConfig      *DatabaseConfig `env:",prefix=DB_"`
+	CloudConfig   *CloudConfig    `env:",prefix=AWS_"`
+	QueueConfig   *QueueConfig    `env:",prefix=QUEUE_"`
+	GatewayConfig *GatewayConfig  `env:",prefix=GATEWAY_"`
+	AuthConfig    *AuthConfig     `env:",prefix=AUTH_"`
+	OutboxConfig  *OutboxConfig   `env:",prefix=OUTBOX_"`
+
+	ExpirationConfig *ExpirationConfig `env:",prefix=EXPIRATION_"`
+	TracingConfig    *TracingConfig    `env:",prefix=TRACING_"`
+	HealthConfig     *HealthConfig     `env:",prefix=HEALTH_"`
+}
+
+// MigrationConfig is the part of the environment needed to migrate the database,
+// the settings only the server uses are neither required nor loaded
+type MigrationConfig struct {
+	ApiConfig   *ApiConfig           `env:",prefix=API_"`
+	DbConfig    *DatabaseConfig      `env:",prefix=DB_"`
+	CloudConfig *CloudEndpointConfig `env:",prefix=AWS_"`
 }
 
 type Environment interface {
 	GetEnvironmentFromFile(ctx context.Context, fileName string) (*Config, error)
 	GetEnvironment(ctx context.Context) (*Config, error)
+	GetMigrationEnvironmentFromFile(ctx context.Context, fileName string) (*MigrationConfig, error)
+	GetMigrationEnvironment(ctx context.Context) (*MigrationConfig, error)
 }
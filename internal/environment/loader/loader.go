@@ -23,7 +23,23 @@ func (l *Loader) GetEnvironmentFromFile(ctx context.Context, fileName string) (*
 }
 
 func (l *Loader) GetEnvironment(ctx context.Context) (*environment.Config, error) {
-	var env environment.Config
+	return process[environment.Config](ctx)
+}
+
+func (l *Loader) GetMigrationEnvironmentFromFile(ctx context.Context, fileName string) (*environment.MigrationConfig, error) {
+	if err := godotenv.Load(fileName); err != nil {
+		return nil, err
+	}
+
+	return l.GetMigrationEnvironment(ctx)
+}
+
+func (l *Loader) GetMigrationEnvironment(ctx context.Context) (*environment.MigrationConfig, error) {
+	return process[environment.MigrationConfig](ctx)
+}
+
+func process[T any](ctx context.Context) (*T, error) {
+	var env T
 	if err := envconfig.Process(ctx, &env); err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/stretchr/testify/assert"
@@ -18,9 +19,15 @@ func cleanEnv() {
 		"DB_URL",
 		"DB_URL_SECRET_NAME",
 		"AWS_BASE_ENDPOINT",
+		"AWS_DRIVER",
 		"AWS_ORDER_PRODUCTION_TOPIC_NAME",
 		"AWS_UPDATE_ORDER_TOPIC_NAME",
 		"AWS_ORDER_PAYMENT_QUEUE_NAME",
+		"AWS_ORDER_PAYMENT_DLQ_NAME",
+		"GATEWAY_BASE_URL",
+		"GATEWAY_API_KEY",
+		"GATEWAY_TIMEOUT",
+		"GATEWAY_WEBHOOK_SECRET",
 	}
 
 	for _, env := range envs {
@@ -44,6 +51,10 @@ func TestGetEnvironment(t *testing.T) {
 			{"AWS_ORDER_PRODUCTION_TOPIC_NAME", "order_payment"},
 			{"AWS_UPDATE_ORDER_TOPIC_NAME", "update_order"},
 			{"AWS_ORDER_PAYMENT_QUEUE_NAME", "order_payment"},
+			{"AWS_ORDER_PAYMENT_DLQ_NAME", "order_payment_dlq"},
+			{"GATEWAY_BASE_URL", "http://localhost:8081"},
+			{"GATEWAY_API_KEY", "api-key"},
+			{"GATEWAY_WEBHOOK_SECRET", "webhook-secret"},
 		}
 
 		for _, env := range envs {
@@ -64,7 +75,53 @@ func TestGetEnvironment(t *testing.T) {
 				OrderProductionTopic: "order_payment",
 				UpdateOrderTopic:     "update_order",
 				OrderPaymentQueue:    "order_payment",
-				BaseEndpoint:         "http://localhost:4566",
+				OrderPaymentDlq:      "order_payment_dlq",
+				CloudEndpointConfig: environment.CloudEndpointConfig{
+					BaseEndpoint: "http://localhost:4566",
+					Driver:       "aws",
+				},
+			},
+			QueueConfig: &environment.QueueConfig{
+				Concurrency:     10,
+				MaxReceiveCount: 5,
+				BaseBackoff:     10 * time.Second,
+				MaxBackoff:      15 * time.Minute,
+
+				HeartbeatTimeout: 2 * time.Minute,
+			},
+			GatewayConfig: &environment.GatewayConfig{
+				BaseUrl: "http://localhost:8081",
+				ApiKey:  "api-key",
+				Timeout: 10 * time.Second,
+
+				WebhookSecret:    "webhook-secret",
+				WebhookTolerance: 5 * time.Minute,
+			},
+			AuthConfig: &environment.AuthConfig{
+				JwksCacheTtl:           10 * time.Minute,
+				JwksMinRefreshInterval: time.Minute,
+				Leeway:                 30 * time.Second,
+			},
+			OutboxConfig: &environment.OutboxConfig{
+				PollInterval: 5 * time.Second,
+				BatchSize:    10,
+				LockTimeout:  30 * time.Second,
+				BaseBackoff:  time.Second,
+				MaxBackoff:   5 * time.Minute,
+			},
+			ExpirationConfig: &environment.ExpirationConfig{
+				PaymentTtl:   30 * time.Minute,
+				PollInterval: time.Minute,
+				BatchSize:    50,
+			},
+			TracingConfig: &environment.TracingConfig{
+				Exporter:    "none",
+				ServiceName: "ms-payment-management",
+				SampleRatio: 1,
+			},
+			HealthConfig: &environment.HealthConfig{
+				Timeout:  2 * time.Second,
+				CacheTtl: 5 * time.Second,
 			},
 		}
 
@@ -90,6 +147,7 @@ func TestGetEnvironment(t *testing.T) {
 			{"AWS_BASE_ENDPOINT", "http://localhost:4566"},
 			{"AWS_ORDER_PRODUCTION_TOPIC_NAME", "order_payment"},
 			{"AWS_ORDER_PAYMENT_QUEUE_NAME", "order_payment"},
+			{"AWS_ORDER_PAYMENT_DLQ_NAME", "order_payment_dlq"},
 		}
 
 		for _, env := range envs {
@@ -124,7 +182,53 @@ func TestGetEnvironmentFromFile(t *testing.T) {
 				OrderProductionTopic: "order_payment",
 				UpdateOrderTopic:     "update_order",
 				OrderPaymentQueue:    "order_payment",
-				BaseEndpoint:         "http://localhost:4566",
+				OrderPaymentDlq:      "order_payment_dlq",
+				CloudEndpointConfig: environment.CloudEndpointConfig{
+					BaseEndpoint: "http://localhost:4566",
+					Driver:       "aws",
+				},
+			},
+			QueueConfig: &environment.QueueConfig{
+				Concurrency:     10,
+				MaxReceiveCount: 5,
+				BaseBackoff:     10 * time.Second,
+				MaxBackoff:      15 * time.Minute,
+
+				HeartbeatTimeout: 2 * time.Minute,
+			},
+			GatewayConfig: &environment.GatewayConfig{
+				BaseUrl: "http://localhost:8081",
+				ApiKey:  "api-key",
+				Timeout: 10 * time.Second,
+
+				WebhookSecret:    "webhook-secret",
+				WebhookTolerance: 5 * time.Minute,
+			},
+			AuthConfig: &environment.AuthConfig{
+				JwksCacheTtl:           10 * time.Minute,
+				JwksMinRefreshInterval: time.Minute,
+				Leeway:                 30 * time.Second,
+			},
+			OutboxConfig: &environment.OutboxConfig{
+				PollInterval: 5 * time.Second,
+				BatchSize:    10,
+				LockTimeout:  30 * time.Second,
+				BaseBackoff:  time.Second,
+				MaxBackoff:   5 * time.Minute,
+			},
+			ExpirationConfig: &environment.ExpirationConfig{
+				PaymentTtl:   30 * time.Minute,
+				PollInterval: time.Minute,
+				BatchSize:    50,
+			},
+			TracingConfig: &environment.TracingConfig{
+				Exporter:    "none",
+				ServiceName: "ms-payment-management",
+				SampleRatio: 1,
+			},
+			HealthConfig: &environment.HealthConfig{
+				Timeout:  2 * time.Second,
+				CacheTtl: 5 * time.Second,
 			},
 		}
 
@@ -161,3 +265,91 @@ func TestGetEnvironmentFromFile(t *testing.T) {
 		assert.Nil(t, env)
 	})
 }
+
+func TestGetMigrationEnvironment(t *testing.T) {
+	t.Run("Should load the environment of the migrations without the settings of the server", func(t *testing.T) {
+		// Arrange
+		cleanEnv()
+
+		t.Setenv("DB_URL", "db://host:1234")
+		t.Setenv("DB_URL_SECRET_NAME", "db-secret-url")
+
+		expected := &environment.MigrationConfig{
+			ApiConfig: &environment.ApiConfig{
+				Port:       8080,
+				EnvName:    "development",
+				ApiVersion: "v1",
+			},
+			DbConfig: &environment.DatabaseConfig{
+				Url:           "db://host:1234",
+				UrlSecretName: "db-secret-url",
+			},
+			CloudConfig: &environment.CloudEndpointConfig{
+				Driver: "aws",
+			},
+		}
+
+		// Act
+		env, err := NewLoader().GetMigrationEnvironment(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expected, env)
+	})
+
+	t.Run("Should return error if the database is not set", func(t *testing.T) {
+		// Arrange
+		cleanEnv()
+
+		t.Setenv("DB_URL_SECRET_NAME", "db-secret-url")
+
+		// Act
+		env, err := NewLoader().GetMigrationEnvironment(context.Background())
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, env)
+	})
+}
+
+func TestGetMigrationEnvironmentFromFile(t *testing.T) {
+	t.Run("Should load the environment of the migrations from file", func(t *testing.T) {
+		// Arrange
+		cleanEnv()
+
+		expected := &environment.MigrationConfig{
+			ApiConfig: &environment.ApiConfig{
+				Port:       8080,
+				EnvName:    "development",
+				ApiVersion: "v1",
+			},
+			DbConfig: &environment.DatabaseConfig{
+				Url:           "db://host:1234",
+				UrlSecretName: "db-secret-url",
+			},
+			CloudConfig: &environment.CloudEndpointConfig{
+				BaseEndpoint: "http://localhost:4566",
+				Driver:       "memory",
+			},
+		}
+
+		// Act
+		env, err := NewLoader().GetMigrationEnvironmentFromFile(context.Background(), filepath.Join("testdata", "test_migration.env"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expected, env)
+	})
+
+	t.Run("Should return error try to load from an invalid file", func(t *testing.T) {
+		// Arrange
+		cleanEnv()
+
+		// Act
+		env, err := NewLoader().GetMigrationEnvironmentFromFile(context.Background(), filepath.Join("testdata", "non_exists.env"))
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, env)
+	})
+}
@@ -74,6 +74,66 @@ func (_m *MockEnvironment) GetEnvironmentFromFile(ctx context.Context, fileName
 	return r0, r1
 }
 
+// GetMigrationEnvironment provides a mock function with given fields: ctx
+func (_m *MockEnvironment) GetMigrationEnvironment(ctx context.Context) (*environment.MigrationConfig, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMigrationEnvironment")
+	}
+
+	var r0 *environment.MigrationConfig
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*environment.MigrationConfig, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *environment.MigrationConfig); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*environment.MigrationConfig)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMigrationEnvironmentFromFile provides a mock function with given fields: ctx, fileName
+func (_m *MockEnvironment) GetMigrationEnvironmentFromFile(ctx context.Context, fileName string) (*environment.MigrationConfig, error) {
+	ret := _m.Called(ctx, fileName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMigrationEnvironmentFromFile")
+	}
+
+	var r0 *environment.MigrationConfig
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*environment.MigrationConfig, error)); ok {
+		return rf(ctx, fileName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *environment.MigrationConfig); ok {
+		r0 = rf(ctx, fileName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*environment.MigrationConfig)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, fileName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMockEnvironment creates a new instance of MockEnvironment. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockEnvironment(t interface {
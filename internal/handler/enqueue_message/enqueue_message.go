@@ -0,0 +1,50 @@
+package enqueue_message
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+)
+
+type EnqueueMessageResponse struct {
+	MessageId string `json:"message_id"`
+}
+
+type Handler struct {
+	queue *cloud.MemoryQueueService
+}
+
+func NewHandler(queue *cloud.MemoryQueueService) *Handler {
+	return &Handler{
+		queue: queue,
+	}
+}
+
+// Handle enqueues a SNS notification envelope, the same one scripts/cloud/send-message.sh sends to localstack
+func (h *Handler) Handle(ctx echo.Context) error {
+	var notification cloud.TopicNotification
+
+	if err := ctx.Bind(&notification); err != nil {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
+	}
+
+	if notification.Message == "" {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", errors.New("message is required"))
+	}
+
+	messageId, err := h.queue.Enqueue(ctx.Request().Context(), notification)
+	if err != nil {
+		if errors.Is(err, cloud.ErrMemoryQueueFull) {
+			return custom_error.NewHttpAppError(http.StatusServiceUnavailable, "queue is full", err)
+		}
+
+		return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
+	}
+
+	return ctx.JSON(http.StatusAccepted, EnqueueMessageResponse{
+		MessageId: messageId,
+	})
+}
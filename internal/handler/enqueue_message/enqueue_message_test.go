@@ -0,0 +1,99 @@
+package enqueue_message
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueue() *cloud.MemoryQueueService {
+	return cloud.NewMemoryQueueService("test-queue", &environment.QueueConfig{
+		MaxReceiveCount: 3,
+		BaseBackoff:     time.Second,
+		MaxBackoff:      time.Minute,
+	}, nil, nil)
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("Should enqueue the notification", func(t *testing.T) {
+		// Arrange
+		body := `{"Type":"Notification","MessageId":"fc8e9ffd-6122-5c52-8fb9-c13e3ee2629a","Message":"{\"order_id\":\"order_id\"}"}`
+
+		req := httptest.NewRequest(echo.POST, "/admin/queue/messages", bytes.NewBuffer([]byte(body)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(newQueue())
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+
+		var response EnqueueMessageResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+		assert.Equal(t, "fc8e9ffd-6122-5c52-8fb9-c13e3ee2629a", response.MessageId)
+	})
+
+	t.Run("Should return an error if the message is missing", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(echo.POST, "/admin/queue/messages", bytes.NewBuffer([]byte(`{"Type":"Notification"}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(newQueue())
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+	})
+
+	t.Run("Should return an error if the body is invalid", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(echo.POST, "/admin/queue/messages", bytes.NewBuffer([]byte(`not a json`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(newQueue())
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+	})
+}
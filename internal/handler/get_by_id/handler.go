@@ -0,0 +1,41 @@
+package get_by_id
+
+import (
+	"net/http"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_id"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	getById service.GetPaymentByIDService[get_by_id.GetByIdDTO]
+}
+
+func NewHandler(getById service.GetPaymentByIDService[get_by_id.GetByIdDTO]) *Handler {
+	return &Handler{
+		getById: getById,
+	}
+}
+
+func (h *Handler) Handle(ctx echo.Context) error {
+	var request get_by_id.GetByIdDTO
+
+	if err := ctx.Bind(&request); err != nil {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
+	}
+
+	context := ctx.Request().Context()
+
+	payment, err := h.getById.Handle(context, request)
+	if err != nil {
+		if custom_error.IsBusinessErr(err) {
+			return custom_error.NewHttpAppErrorFromBusinessError(err)
+		}
+
+		return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
+	}
+
+	return ctx.JSON(http.StatusOK, payment)
+}
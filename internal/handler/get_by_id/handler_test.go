@@ -0,0 +1,174 @@
+package get_by_id
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_id"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandle(t *testing.T) {
+	t.Run("Should return the payment", func(t *testing.T) {
+		// Arrange
+		getByIdService := mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
+
+		paymentId := uuid.NewString()
+
+		getByIdService.On("Handle", mock.Anything, get_by_id.GetByIdDTO{
+			PaymentId: paymentId,
+		}).
+			Return(payment_entity.Payment{
+				PaymentId:  paymentId,
+				State:      payment_entity.Approved,
+				StateTitle: "Approved",
+				Items: []payment_entity.PaymentItem{
+					payment_entity.NewPaymentItem(uuid.NewString(), "Hamburger", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
+				},
+			}, nil).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(paymentId)
+
+		handler := NewHandler(getByIdService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"state_title":"Approved"`)
+		assert.Contains(t, resp.Body.String(), `"name":"Hamburger"`)
+		getByIdService.AssertExpectations(t)
+	})
+
+	t.Run("Should return not found if the payment does not exist", func(t *testing.T) {
+		// Arrange
+		getByIdService := mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
+
+		getByIdService.On("Handle", mock.Anything, mock.Anything).
+			Return(payment_entity.Payment{}, custom_error.ErrPaymentNotFound).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(getByIdService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusNotFound, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusNotFound,
+			Message: "unable to find the payment",
+			Details: "payment not found",
+		}, he.Message)
+
+		getByIdService.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
+		// Arrange
+		getByIdService := mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
+
+		getByIdService.On("Handle", mock.Anything, mock.Anything).
+			Return(payment_entity.Payment{}, custom_error.ErrRequestNotValid).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues("invalid")
+
+		handler := NewHandler(getByIdService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Details: "request not valid, please check the fields",
+		}, he.Message)
+
+		getByIdService.AssertExpectations(t)
+	})
+
+	t.Run("Should return internal server error when an unexpected error occurs", func(t *testing.T) {
+		// Arrange
+		getByIdService := mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
+
+		getByIdService.On("Handle", mock.Anything, mock.Anything).
+			Return(payment_entity.Payment{}, assert.AnError).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(getByIdService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "internal server error",
+			Details: "assert.AnError general error for testing",
+		}, he.Message)
+
+		getByIdService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,41 @@
+package get_history
+
+import (
+	"net/http"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	getHistory service.GetPaymentHistoryService[get_history.GetHistoryDTO]
+}
+
+func NewHandler(getHistory service.GetPaymentHistoryService[get_history.GetHistoryDTO]) *Handler {
+	return &Handler{
+		getHistory: getHistory,
+	}
+}
+
+func (h *Handler) Handle(ctx echo.Context) error {
+	var request get_history.GetHistoryDTO
+
+	if err := ctx.Bind(&request); err != nil {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
+	}
+
+	context := ctx.Request().Context()
+
+	changes, err := h.getHistory.Handle(context, request)
+	if err != nil {
+		if custom_error.IsBusinessErr(err) {
+			return custom_error.NewHttpAppErrorFromBusinessError(err)
+		}
+
+		return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
+	}
+
+	return ctx.JSON(http.StatusOK, changes)
+}
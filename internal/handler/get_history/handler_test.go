@@ -0,0 +1,128 @@
+package get_history
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandle(t *testing.T) {
+	t.Run("Should return the history of the payment", func(t *testing.T) {
+		// Arrange
+		getHistoryService := mocks.NewMockGetPaymentHistoryService[get_history.GetHistoryDTO](t)
+
+		paymentId := uuid.NewString()
+
+		getHistoryService.On("Handle", mock.Anything, get_history.GetHistoryDTO{
+			PaymentId: paymentId,
+		}).
+			Return([]payment_entity.StateChange{
+				{
+					PaymentId: paymentId,
+					From:      payment_entity.WaitingForApproval,
+					FromTitle: "WaitingForApproval",
+					To:        payment_entity.Approved,
+					ToTitle:   "Approved",
+					Source:    payment_entity.SourceWebhook,
+					Payload:   `{"approved":true}`,
+				},
+			}, nil).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(paymentId)
+
+		handler := NewHandler(getHistoryService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"to_state_title":"Approved"`)
+		assert.Contains(t, resp.Body.String(), `"source":"webhook"`)
+		getHistoryService.AssertExpectations(t)
+	})
+
+	t.Run("Should return not found if the payment does not exist", func(t *testing.T) {
+		// Arrange
+		getHistoryService := mocks.NewMockGetPaymentHistoryService[get_history.GetHistoryDTO](t)
+
+		getHistoryService.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, custom_error.ErrPaymentNotFound).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(getHistoryService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusNotFound, he.Code)
+
+		getHistoryService.AssertExpectations(t)
+	})
+
+	t.Run("Should return internal server error when an unexpected error occurs", func(t *testing.T) {
+		// Arrange
+		getHistoryService := mocks.NewMockGetPaymentHistoryService[get_history.GetHistoryDTO](t)
+
+		getHistoryService.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, assert.AnError).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(getHistoryService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+
+		getHistoryService.AssertExpectations(t)
+	})
+}
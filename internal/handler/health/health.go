@@ -3,33 +3,34 @@ package health
 import (
 	"net/http"
 
-	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/database"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 	"github.com/labstack/echo/v4"
 )
 
 type Handler struct {
-	db database.DatabaseService
+	checker *health.Checker
 }
 
-func NewHandler(db database.DatabaseService) *Handler {
+func NewHandler(checker *health.Checker) *Handler {
 	return &Handler{
-		db: db,
+		checker: checker,
 	}
 }
 
-func (h *Handler) Handle(ctx echo.Context) error {
-	dbStatus := h.db.Health()
+// Live only tells the process is serving requests, the dependencies are left to Ready
+// so an outage of one of them does not restart every pod
+func (h *Handler) Live(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, health.Healthy())
+}
 
-	data := map[string]*health.HealthStatus{
-		"database": dbStatus,
-	}
+func (h *Handler) Ready(ctx echo.Context) error {
+	report := h.checker.Check(ctx.Request().Context())
 
 	code := http.StatusOK
 
-	if dbStatus.HasError() {
-		code = http.StatusBadRequest
+	if report.HasError() {
+		code = http.StatusServiceUnavailable
 	}
 
-	return ctx.JSON(code, data)
+	return ctx.JSON(code, report)
 }
@@ -1,78 +1,126 @@
 package health
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
-	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/database/mocks"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
+func healthyCheck() health.HealthCheck {
+	return health.CheckFunc(func(ctx context.Context) *health.HealthStatus {
+		return health.Healthy()
+	})
+}
+
+func unhealthyCheck(err error) health.HealthCheck {
+	return health.CheckFunc(func(ctx context.Context) *health.HealthStatus {
+		return health.Unhealthy(err)
+	})
+}
+
 func TestNewHandler(t *testing.T) {
 	t.Run("Should return a new handler", func(t *testing.T) {
 		// Arrange
-		db := mocks.NewMockDatabaseService(t)
+		checker := health.NewChecker(time.Second, time.Second)
 
 		// Act
-		handler := NewHandler(db)
+		handler := NewHandler(checker)
 
 		// Assert
 		assert.NotNil(t, handler)
 	})
 }
 
-func TestHandler_Handle(t *testing.T) {
-	t.Run("Should return a map with healthy database status", func(t *testing.T) {
+func TestHandler_Live(t *testing.T) {
+	t.Run("Should return healthy without running the checks", func(t *testing.T) {
 		// Arrange
-		db := mocks.NewMockDatabaseService(t)
+		checker := health.NewChecker(time.Second, time.Second,
+			health.Component{Name: "database", Check: unhealthyCheck(errors.New("error"))},
+		)
 
-		db.On("Health").Return(&health.HealthStatus{
-			Status: "healthy",
-		}, nil)
-
-		req := httptest.NewRequest(echo.GET, "/health", nil)
+		req := httptest.NewRequest(echo.GET, "/health/live", nil)
 		resp := httptest.NewRecorder()
 
 		echo := echo.New()
 		ctx := echo.NewContext(req, resp)
 
-		handler := NewHandler(db)
+		handler := NewHandler(checker)
 
 		// Act
-		err := handler.Handle(ctx)
+		err := handler.Live(ctx)
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusOK, resp.Code)
-		assert.JSONEq(t, `{"database": {"status":"healthy"}}`, resp.Body.String())
+		assert.JSONEq(t, `{"status":"healthy"}`, resp.Body.String())
 	})
+}
 
-	t.Run("Should return a map with unhealthy database status", func(t *testing.T) {
+func TestHandler_Ready(t *testing.T) {
+	t.Run("Should return the status of every component", func(t *testing.T) {
 		// Arrange
-		db := mocks.NewMockDatabaseService(t)
+		checker := health.NewChecker(time.Second, time.Second,
+			health.Component{Name: "database", Check: healthyCheck()},
+			health.Component{Name: "queue", Check: healthyCheck()},
+		)
+
+		req := httptest.NewRequest(echo.GET, "/health/ready", nil)
+		resp := httptest.NewRecorder()
+
+		echo := echo.New()
+		ctx := echo.NewContext(req, resp)
+
+		handler := NewHandler(checker)
 
-		db.On("Health").Return(&health.HealthStatus{
-			Status: "unhealthy",
-			Err:    "error",
-		}, nil)
+		// Act
+		err := handler.Ready(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{
+			"status": "healthy",
+			"components": {
+				"database": {"status":"healthy"},
+				"queue": {"status":"healthy"}
+			}
+		}`, resp.Body.String())
+	})
+
+	t.Run("Should return service unavailable when a component is unhealthy", func(t *testing.T) {
+		// Arrange
+		checker := health.NewChecker(time.Second, time.Second,
+			health.Component{Name: "database", Check: healthyCheck()},
+			health.Component{Name: "queue", Check: unhealthyCheck(errors.New("error"))},
+		)
 
-		req := httptest.NewRequest(echo.GET, "/health", nil)
+		req := httptest.NewRequest(echo.GET, "/health/ready", nil)
 		resp := httptest.NewRecorder()
 
 		echo := echo.New()
 		ctx := echo.NewContext(req, resp)
 
-		handler := NewHandler(db)
+		handler := NewHandler(checker)
 
 		// Act
-		err := handler.Handle(ctx)
+		err := handler.Ready(ctx)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.JSONEq(t, `{"database": {"status":"unhealthy", "err": "error"}}`, resp.Body.String())
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		assert.JSONEq(t, `{
+			"status": "unhealthy",
+			"components": {
+				"database": {"status":"healthy"},
+				"queue": {"status":"unhealthy", "err":"error"}
+			}
+		}`, resp.Body.String())
 	})
 }
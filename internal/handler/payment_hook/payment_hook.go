@@ -1,107 +1,64 @@
 package payment_hook
 
 import (
-	"log/slog"
+	"bytes"
+	"errors"
+	"io"
 	"net/http"
 
-	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
-	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
-	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_id"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/update"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
 	"github.com/labstack/echo/v4"
 )
 
 type Handler struct {
-	getPaymentByIdService service.GetPaymentByIDService[get_by_id.GetByIdDTO]
-	updatePaymentService  service.UpdatePaymentService[update.UpdatePaymentDTO]
-	orderProductionTopic  cloud.TopicService
-	updateOrderTopic      cloud.TopicService
+	updatePaymentService service.UpdatePaymentService[update.UpdatePaymentDTO]
 }
 
 func NewHandler(
-	getPaymentByIdService service.GetPaymentByIDService[get_by_id.GetByIdDTO],
 	updatePaymentService service.UpdatePaymentService[update.UpdatePaymentDTO],
-	orderProductionTopic cloud.TopicService,
-	updateOrderTopic cloud.TopicService,
 ) *Handler {
 	return &Handler{
-		getPaymentByIdService: getPaymentByIdService,
-		updatePaymentService:  updatePaymentService,
-		orderProductionTopic:  orderProductionTopic,
-		updateOrderTopic:      updateOrderTopic,
+		updatePaymentService: updatePaymentService,
 	}
 }
 
 func (h *Handler) Handle(ctx echo.Context) error {
 	var request update.UpdatePaymentDTO
 
+	// the raw notification is kept in the state history, so it is read before binding
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
+	}
+
+	ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+
 	if err := ctx.Bind(&request); err != nil {
 		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
 	}
 
+	request.Payload = string(body)
+
 	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &request); err != nil {
 		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
 	}
 
 	context := ctx.Request().Context()
 
-	var payment *payment_entity.Payment
-	var err error
-
-	if request.Resend {
-		slog.InfoContext(context, "payment resend requested, getting payment by id", "payment_id", request.PaymentId)
-
-		exists, err := h.getPaymentByIdService.Handle(context, get_by_id.GetByIdDTO{
-			PaymentId: request.PaymentId,
-		})
-		if err != nil {
-			if custom_error.IsBusinessErr(err) {
-				return custom_error.NewHttpAppErrorFromBusinessError(err)
-			}
-
-			return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
-		}
-
-		payment = &exists
-	} else {
-		payment, err = h.updatePaymentService.Handle(context, request)
-		if err != nil {
-			if custom_error.IsBusinessErr(err) {
-				return custom_error.NewHttpAppErrorFromBusinessError(err)
-			}
-
-			return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
+	payment, err := h.updatePaymentService.Handle(context, request)
+	if err != nil {
+		// a redelivered event is answered with the payment returned the first time
+		if errors.Is(err, custom_error.ErrEventAlreadyProcessed) && payment != nil {
+			return ctx.JSON(http.StatusOK, payment)
 		}
 
-		if payment.State == payment_entity.Approved {
-			slog.InfoContext(context, "payment approved, sending to production topic", "payment_id", payment.PaymentId)
-
-			req := cloud.NewOrderProductionContractFromPayment(payment)
-
-			messageId, err := h.orderProductionTopic.PublishMessage(context, req)
-			if err != nil {
-				slog.ErrorContext(context, "error publishing message to production topic", "error", err)
-			}
-
-			if messageId != nil {
-				slog.InfoContext(context, "message published to production topic", "message_id", *messageId)
-			}
+		if custom_error.IsBusinessErr(err) {
+			return custom_error.NewHttpAppErrorFromBusinessError(err)
 		}
-	}
-
-	slog.InfoContext(context, "sending to update order topic", "payment_id", payment.PaymentId)
-
-	req := cloud.NewUpdateOrderContractFromPayment(payment)
-
-	messageId, err := h.updateOrderTopic.PublishMessage(context, req)
-	if err != nil {
-		slog.ErrorContext(context, "error publishing message to update order topic", "error", err)
-	}
 
-	if messageId != nil {
-		slog.InfoContext(context, "message published to update order topic", "message_id", *messageId)
+		return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
 	}
 
 	return ctx.JSON(http.StatusCreated, payment)
@@ -8,11 +8,8 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
-	topic_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud/mocks"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
-	service_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
-	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_id"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/update"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
 	"github.com/labstack/echo/v4"
@@ -21,31 +18,58 @@ import (
 )
 
 func TestHandle(t *testing.T) {
-	t.Run("Should create a payment gateway when the payment is approved", func(t *testing.T) {
+	t.Run("Should update the payment when the payment is approved", func(t *testing.T) {
 		// Arrange
-		getPaymentByIdService := service_mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
-		updatePaymentService := service_mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
-		orderProductionTopicService := topic_mocks.NewMockTopicService(t)
-		updateOrderTopicService := topic_mocks.NewMockTopicService(t)
+		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
 
-		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
+		paymentId := uuid.NewString()
+
+		updatePaymentService.On("Handle", mock.Anything, update.UpdatePaymentDTO{
+			PaymentId: paymentId,
+			Approved:  true,
+			Payload:   `{"approved": true}`,
+		}).
 			Return(&payment_entity.Payment{
-				State: payment_entity.Approved,
+				PaymentId:  paymentId,
+				State:      payment_entity.Approved,
+				StateTitle: "Approved",
 			}, nil).
 			Once()
 
-		msgId := uuid.NewString()
+		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer([]byte(`{"approved": true}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
 
-		orderProductionTopicService.On("PublishMessage", mock.Anything, mock.Anything).
-			Return(&msgId, nil).
-			Once()
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(paymentId)
+
+		handler := NewHandler(updatePaymentService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"state_title":"Approved"`)
+		updatePaymentService.AssertExpectations(t)
+	})
 
-		updateOrderTopicService.On("PublishMessage", mock.Anything, mock.Anything).
-			Return(&msgId, nil).
+	t.Run("Should update the payment when the payment is rejected", func(t *testing.T) {
+		// Arrange
+		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
+
+		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
+			Return(&payment_entity.Payment{
+				State: payment_entity.Rejected,
+			}, nil).
 			Once()
 
 		reqBody := update.UpdatePaymentDTO{
-			Approved: true,
+			Approved: false,
 		}
 
 		body, err := json.Marshal(reqBody)
@@ -61,7 +85,7 @@ func TestHandle(t *testing.T) {
 		ctx.SetParamNames("payment_id")
 		ctx.SetParamValues(uuid.NewString())
 
-		handler := NewHandler(getPaymentByIdService, updatePaymentService, orderProductionTopicService, updateOrderTopicService)
+		handler := NewHandler(updatePaymentService)
 
 		// Act
 		err = handler.Handle(ctx)
@@ -69,37 +93,22 @@ func TestHandle(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusCreated, resp.Code)
-		getPaymentByIdService.AssertExpectations(t)
 		updatePaymentService.AssertExpectations(t)
-		orderProductionTopicService.AssertExpectations(t)
-		updateOrderTopicService.AssertExpectations(t)
 	})
 
-	t.Run("Should create a payment gateway when the payment is rejected", func(t *testing.T) {
+	t.Run("Should bind the resend query param", func(t *testing.T) {
 		// Arrange
-		getPaymentByIdService := service_mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
-		updatePaymentService := service_mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
-		orderProductionTopicService := topic_mocks.NewMockTopicService(t)
-		updateOrderTopicService := topic_mocks.NewMockTopicService(t)
+		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
 
-		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
+		updatePaymentService.On("Handle", mock.Anything, mock.MatchedBy(func(req update.UpdatePaymentDTO) bool {
+			return req.Resend
+		})).
 			Return(&payment_entity.Payment{
-				State: payment_entity.Rejected,
+				State: payment_entity.Approved,
 			}, nil).
 			Once()
 
-		updateOrderTopicService.On("PublishMessage", mock.Anything, mock.Anything).
-			Return(nil, nil).
-			Once()
-
-		reqBody := update.UpdatePaymentDTO{
-			Approved: true,
-		}
-
-		body, err := json.Marshal(reqBody)
-		assert.NoError(t, err)
-
-		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer(body))
+		req := httptest.NewRequest(echo.PATCH, "/?resend=true", bytes.NewBuffer([]byte(`{}`)))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		resp := httptest.NewRecorder()
@@ -109,39 +118,67 @@ func TestHandle(t *testing.T) {
 		ctx.SetParamNames("payment_id")
 		ctx.SetParamValues(uuid.NewString())
 
-		handler := NewHandler(getPaymentByIdService, updatePaymentService, orderProductionTopicService, updateOrderTopicService)
+		handler := NewHandler(updatePaymentService)
 
 		// Act
-		err = handler.Handle(ctx)
+		err := handler.Handle(ctx)
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusCreated, resp.Code)
-		getPaymentByIdService.AssertExpectations(t)
 		updatePaymentService.AssertExpectations(t)
-		orderProductionTopicService.AssertExpectations(t)
-		updateOrderTopicService.AssertExpectations(t)
 	})
 
-	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
+	t.Run("Should return the original payment when the event was already processed", func(t *testing.T) {
 		// Arrange
-		getPaymentByIdService := service_mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
 		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
-		orderProductionTopicService := topic_mocks.NewMockTopicService(t)
-		updateOrderTopicService := topic_mocks.NewMockTopicService(t)
 
-		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
-			Return(nil, custom_error.ErrRequestNotValid).
+		paymentId := uuid.NewString()
+
+		updatePaymentService.On("Handle", mock.Anything, update.UpdatePaymentDTO{
+			PaymentId: paymentId,
+			EventId:   "event-1",
+			Approved:  true,
+			Payload:   `{"event_id": "event-1", "approved": true}`,
+		}).
+			Return(&payment_entity.Payment{
+				PaymentId:  paymentId,
+				State:      payment_entity.Approved,
+				StateTitle: "Approved",
+			}, custom_error.ErrEventAlreadyProcessed).
 			Once()
 
-		reqBody := update.UpdatePaymentDTO{
-			Approved: true,
-		}
+		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer([]byte(`{"event_id": "event-1", "approved": true}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		body, err := json.Marshal(reqBody)
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(paymentId)
+
+		handler := NewHandler(updatePaymentService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
 		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"state_title":"Approved"`)
+		updatePaymentService.AssertExpectations(t)
+	})
 
-		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer(body))
+	t.Run("Should return conflict when the event was processed for another payment", func(t *testing.T) {
+		// Arrange
+		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
+
+		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, custom_error.ErrEventAlreadyProcessed).
+			Once()
+
+		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer([]byte(`{"event_id": "event-1", "approved": true}`)))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		resp := httptest.NewRecorder()
@@ -149,12 +186,12 @@ func TestHandle(t *testing.T) {
 		e := echo.New()
 		ctx := e.NewContext(req, resp)
 		ctx.SetParamNames("payment_id")
-		ctx.SetParamValues("invalid-payment-id")
+		ctx.SetParamValues(uuid.NewString())
 
-		handler := NewHandler(getPaymentByIdService, updatePaymentService, orderProductionTopicService, updateOrderTopicService)
+		handler := NewHandler(updatePaymentService)
 
 		// Act
-		err = handler.Handle(ctx)
+		err := handler.Handle(ctx)
 
 		// Assert
 		assert.Error(t, err)
@@ -162,38 +199,19 @@ func TestHandle(t *testing.T) {
 		he, ok := err.(*echo.HTTPError)
 		assert.True(t, ok)
 
-		assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
-		assert.Equal(t, custom_error.AppError{
-			Code:    http.StatusUnprocessableEntity,
-			Message: "validation error",
-			Details: "request not valid, please check the fields",
-		}, he.Message)
-
-		getPaymentByIdService.AssertExpectations(t)
+		assert.Equal(t, http.StatusConflict, he.Code)
 		updatePaymentService.AssertExpectations(t)
-		orderProductionTopicService.AssertExpectations(t)
-		updateOrderTopicService.AssertExpectations(t)
 	})
 
-	t.Run("Should return internal server error when an unexpected error occurs", func(t *testing.T) {
+	t.Run("Should return conflict when the payment was changed concurrently", func(t *testing.T) {
 		// Arrange
-		getPaymentByIdService := service_mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
 		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
-		orderProductionTopicService := topic_mocks.NewMockTopicService(t)
-		updateOrderTopicService := topic_mocks.NewMockTopicService(t)
 
 		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
-			Return(nil, assert.AnError).
+			Return(nil, custom_error.ErrPaymentConcurrentUpdate).
 			Once()
 
-		reqBody := update.UpdatePaymentDTO{
-			Approved: true,
-		}
-
-		body, err := json.Marshal(reqBody)
-		assert.NoError(t, err)
-
-		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer(body))
+		req := httptest.NewRequest(echo.PATCH, "/", bytes.NewBuffer([]byte(`{"approved": true}`)))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		resp := httptest.NewRecorder()
@@ -203,10 +221,10 @@ func TestHandle(t *testing.T) {
 		ctx.SetParamNames("payment_id")
 		ctx.SetParamValues(uuid.NewString())
 
-		handler := NewHandler(getPaymentByIdService, updatePaymentService, orderProductionTopicService, updateOrderTopicService)
+		handler := NewHandler(updatePaymentService)
 
 		// Act
-		err = handler.Handle(ctx)
+		err := handler.Handle(ctx)
 
 		// Assert
 		assert.Error(t, err)
@@ -214,38 +232,22 @@ func TestHandle(t *testing.T) {
 		he, ok := err.(*echo.HTTPError)
 		assert.True(t, ok)
 
-		assert.Equal(t, http.StatusInternalServerError, he.Code)
+		assert.Equal(t, http.StatusConflict, he.Code)
 		assert.Equal(t, custom_error.AppError{
-			Code:    http.StatusInternalServerError,
-			Message: "internal server error",
-			Details: "assert.AnError general error for testing",
+			Code:    http.StatusConflict,
+			Message: "unable to update the payment",
+			Details: "payment was changed by another request, please retry",
 		}, he.Message)
 
-		getPaymentByIdService.AssertExpectations(t)
 		updatePaymentService.AssertExpectations(t)
-		orderProductionTopicService.AssertExpectations(t)
-		updateOrderTopicService.AssertExpectations(t)
 	})
 
-	t.Run("Should log error when an unexpected error occurs while publishing to order production topic", func(t *testing.T) {
+	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
 		// Arrange
-		getPaymentByIdService := service_mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
-		updatePaymentService := service_mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
-		orderProductionTopicService := topic_mocks.NewMockTopicService(t)
-		updateOrderTopicService := topic_mocks.NewMockTopicService(t)
+		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
 
 		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
-			Return(&payment_entity.Payment{
-				State: payment_entity.Approved,
-			}, nil).
-			Once()
-
-		orderProductionTopicService.On("PublishMessage", mock.Anything, mock.Anything).
-			Return(nil, assert.AnError).
-			Once()
-
-		updateOrderTopicService.On("PublishMessage", mock.Anything, mock.Anything).
-			Return(nil, nil).
+			Return(nil, custom_error.ErrRequestNotValid).
 			Once()
 
 		reqBody := update.UpdatePaymentDTO{
@@ -263,42 +265,34 @@ func TestHandle(t *testing.T) {
 		e := echo.New()
 		ctx := e.NewContext(req, resp)
 		ctx.SetParamNames("payment_id")
-		ctx.SetParamValues(uuid.NewString())
+		ctx.SetParamValues("invalid-payment-id")
 
-		handler := NewHandler(getPaymentByIdService, updatePaymentService, orderProductionTopicService, updateOrderTopicService)
+		handler := NewHandler(updatePaymentService)
 
 		// Act
 		err = handler.Handle(ctx)
 
 		// Assert
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusCreated, resp.Code)
-		getPaymentByIdService.AssertExpectations(t)
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Details: "request not valid, please check the fields",
+		}, he.Message)
+
 		updatePaymentService.AssertExpectations(t)
-		orderProductionTopicService.AssertExpectations(t)
-		updateOrderTopicService.AssertExpectations(t)
 	})
 
-	t.Run("Should log error when an unexpected error occurs while publishing to update order topic", func(t *testing.T) {
+	t.Run("Should return internal server error when an unexpected error occurs", func(t *testing.T) {
 		// Arrange
-		getPaymentByIdService := service_mocks.NewMockGetPaymentByIDService[get_by_id.GetByIdDTO](t)
-		updatePaymentService := service_mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
-		orderProductionTopicService := topic_mocks.NewMockTopicService(t)
-		updateOrderTopicService := topic_mocks.NewMockTopicService(t)
+		updatePaymentService := mocks.NewMockUpdatePaymentService[update.UpdatePaymentDTO](t)
 
 		updatePaymentService.On("Handle", mock.Anything, mock.Anything).
-			Return(&payment_entity.Payment{
-				State: payment_entity.Approved,
-			}, nil).
-			Once()
-
-		msgId := uuid.NewString()
-
-		orderProductionTopicService.On("PublishMessage", mock.Anything, mock.Anything).
-			Return(&msgId, nil).
-			Once()
-
-		updateOrderTopicService.On("PublishMessage", mock.Anything, mock.Anything).
 			Return(nil, assert.AnError).
 			Once()
 
@@ -319,18 +313,24 @@ func TestHandle(t *testing.T) {
 		ctx.SetParamNames("payment_id")
 		ctx.SetParamValues(uuid.NewString())
 
-		handler := NewHandler(getPaymentByIdService, updatePaymentService, orderProductionTopicService, updateOrderTopicService)
+		handler := NewHandler(updatePaymentService)
 
 		// Act
 		err = handler.Handle(ctx)
 
 		// Assert
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusCreated, resp.Code)
-		getPaymentByIdService.AssertExpectations(t)
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "internal server error",
+			Details: "assert.AnError general error for testing",
+		}, he.Message)
+
 		updatePaymentService.AssertExpectations(t)
-		orderProductionTopicService.AssertExpectations(t)
-		updateOrderTopicService.AssertExpectations(t)
 	})
-
 }
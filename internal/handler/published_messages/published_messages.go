@@ -0,0 +1,41 @@
+package published_messages
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	topics []*cloud.MemoryTopicService
+}
+
+func NewHandler(topics ...*cloud.MemoryTopicService) *Handler {
+	return &Handler{
+		topics: topics,
+	}
+}
+
+// Handle lists the messages published to the in-memory topics, oldest first,
+// the topic query parameter narrows them to a single topic
+func (h *Handler) Handle(ctx echo.Context) error {
+	topicName := ctx.QueryParam("topic")
+
+	messages := make([]cloud.PublishedMessage, 0)
+
+	for _, topic := range h.topics {
+		if topicName != "" && topic.GetTopicName() != topicName {
+			continue
+		}
+
+		messages = append(messages, topic.Messages()...)
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].PublishedAt.Before(messages[j].PublishedAt)
+	})
+
+	return ctx.JSON(http.StatusOK, messages)
+}
@@ -0,0 +1,100 @@
+package published_messages
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandle(t *testing.T) {
+	t.Run("Should return the messages of every topic", func(t *testing.T) {
+		// Arrange
+		updateOrder := cloud.NewMemoryTopicService("update-order-topic")
+		orderProduction := cloud.NewMemoryTopicService("order-production-topic")
+
+		_, err := updateOrder.PublishMessage(context.Background(), "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		assert.NoError(t, err)
+
+		_, err = orderProduction.PublishMessage(context.Background(), "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(echo.GET, "/admin/topics/messages", nil)
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(updateOrder, orderProduction)
+
+		// Act
+		err = handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		var messages []cloud.PublishedMessage
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &messages))
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "update-order-topic", messages[0].TopicName)
+		assert.Equal(t, "order-production-topic", messages[1].TopicName)
+		assert.JSONEq(t, `{"order_id":"order_id"}`, string(messages[0].Message))
+	})
+
+	t.Run("Should return only the messages of the topic requested", func(t *testing.T) {
+		// Arrange
+		updateOrder := cloud.NewMemoryTopicService("update-order-topic")
+		orderProduction := cloud.NewMemoryTopicService("order-production-topic")
+
+		_, err := updateOrder.PublishMessage(context.Background(), "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		assert.NoError(t, err)
+
+		_, err = orderProduction.PublishMessage(context.Background(), "outbox_id", json.RawMessage(`{"order_id":"order_id"}`))
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(echo.GET, "/admin/topics/messages?topic=order-production-topic", nil)
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(updateOrder, orderProduction)
+
+		// Act
+		err = handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		var messages []cloud.PublishedMessage
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &messages))
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "order-production-topic", messages[0].TopicName)
+	})
+
+	t.Run("Should return an empty list when nothing was published", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(echo.GET, "/admin/topics/messages", nil)
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(cloud.NewMemoryTopicService("update-order-topic"))
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `[]`, resp.Body.String())
+	})
+}
@@ -0,0 +1,45 @@
+package refund
+
+import (
+	"net/http"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/refund"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	refundPaymentService service.RefundPaymentService[refund.RefundPaymentDTO]
+}
+
+func NewHandler(
+	refundPaymentService service.RefundPaymentService[refund.RefundPaymentDTO],
+) *Handler {
+	return &Handler{
+		refundPaymentService: refundPaymentService,
+	}
+}
+
+func (h *Handler) Handle(ctx echo.Context) error {
+	var request refund.RefundPaymentDTO
+
+	if err := ctx.Bind(&request); err != nil {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
+	}
+
+	request.ActorId, _ = ctx.Get("userId").(string)
+
+	context := ctx.Request().Context()
+
+	paymentRefund, err := h.refundPaymentService.Handle(context, request)
+	if err != nil {
+		if custom_error.IsBusinessErr(err) {
+			return custom_error.NewHttpAppErrorFromBusinessError(err)
+		}
+
+		return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
+	}
+
+	return ctx.JSON(http.StatusCreated, paymentRefund)
+}
@@ -0,0 +1,163 @@
+package refund
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/refund"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandle(t *testing.T) {
+	t.Run("Should refund the payment", func(t *testing.T) {
+		// Arrange
+		refundPaymentService := mocks.NewMockRefundPaymentService[refund.RefundPaymentDTO](t)
+
+		paymentId := uuid.NewString()
+
+		refundPaymentService.On("Handle", mock.Anything, refund.RefundPaymentDTO{
+			PaymentId: paymentId,
+			Amount:    money.New(1050, money.BRL),
+			Reason:    "order cancelled",
+			ActorId:   "user_id",
+		}).
+			Return(&refund_entity.Refund{
+				Id:        "refund_id",
+				PaymentId: paymentId,
+				Amount:    money.New(1050, money.BRL),
+			}, nil).
+			Once()
+
+		req := httptest.NewRequest(echo.POST, "/", bytes.NewBuffer([]byte(`{"amount": 10.5, "reason": "order cancelled"}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(paymentId)
+		ctx.Set("userId", "user_id")
+
+		handler := NewHandler(refundPaymentService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"id":"refund_id"`)
+		refundPaymentService.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the body is invalid", func(t *testing.T) {
+		// Arrange
+		refundPaymentService := mocks.NewMockRefundPaymentService[refund.RefundPaymentDTO](t)
+
+		req := httptest.NewRequest(echo.POST, "/", bytes.NewBuffer([]byte(`{"amount": "abc"}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(refundPaymentService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		refundPaymentService.AssertNotCalled(t, "Handle")
+	})
+
+	t.Run("Should return an error if the amount exceeds the remaining amount", func(t *testing.T) {
+		// Arrange
+		refundPaymentService := mocks.NewMockRefundPaymentService[refund.RefundPaymentDTO](t)
+
+		refundPaymentService.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, custom_error.ErrRefundAmountExceeded).
+			Once()
+
+		req := httptest.NewRequest(echo.POST, "/", bytes.NewBuffer([]byte(`{"amount": 1000}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(refundPaymentService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "unable to refund the payment",
+			Details: "refund amount exceeds the remaining amount of the payment",
+		}, he.Message)
+
+		refundPaymentService.AssertExpectations(t)
+	})
+
+	t.Run("Should return internal server error when an unexpected error occurs", func(t *testing.T) {
+		// Arrange
+		refundPaymentService := mocks.NewMockRefundPaymentService[refund.RefundPaymentDTO](t)
+
+		refundPaymentService.On("Handle", mock.Anything, mock.Anything).
+			Return(nil, assert.AnError).
+			Once()
+
+		req := httptest.NewRequest(echo.POST, "/", bytes.NewBuffer([]byte(`{"amount": 1}`)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+		ctx.SetParamNames("payment_id")
+		ctx.SetParamValues(uuid.NewString())
+
+		handler := NewHandler(refundPaymentService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+		refundPaymentService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,41 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/search"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	searchPayments service.SearchPaymentsService[search.SearchPaymentsDTO]
+}
+
+func NewHandler(searchPayments service.SearchPaymentsService[search.SearchPaymentsDTO]) *Handler {
+	return &Handler{
+		searchPayments: searchPayments,
+	}
+}
+
+func (h *Handler) Handle(ctx echo.Context) error {
+	var request search.SearchPaymentsDTO
+
+	if err := ctx.Bind(&request); err != nil {
+		return custom_error.NewHttpAppError(http.StatusBadRequest, "invalid request", err)
+	}
+
+	context := ctx.Request().Context()
+
+	page, err := h.searchPayments.Handle(context, request)
+	if err != nil {
+		if custom_error.IsBusinessErr(err) {
+			return custom_error.NewHttpAppErrorFromBusinessError(err)
+		}
+
+		return custom_error.NewHttpAppError(http.StatusInternalServerError, "internal server error", err)
+	}
+
+	return ctx.JSON(http.StatusOK, page)
+}
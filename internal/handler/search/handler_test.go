@@ -0,0 +1,154 @@
+package search
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/search"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandle(t *testing.T) {
+	t.Run("Should return the page of payments", func(t *testing.T) {
+		// Arrange
+		searchService := mocks.NewMockSearchPaymentsService[search.SearchPaymentsDTO](t)
+
+		searchService.On("Handle", mock.Anything, search.SearchPaymentsDTO{
+			States:      []string{"WaitingForApproval", "Approved"},
+			CreatedFrom: "2024-05-18T00:00:00Z",
+			MinAmount:   "10",
+			Sort:        "-amount",
+			Limit:       10,
+			Cursor:      "abc",
+		}).
+			Return(payment_entity.SearchPage{
+				Payments: []payment_entity.Payment{
+					{
+						PaymentId:  uuid.NewString(),
+						State:      payment_entity.WaitingForApproval,
+						StateTitle: "WaitingForApproval",
+					},
+				},
+				NextCursor: "next",
+			}, nil).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/?state=WaitingForApproval&state=Approved&created_from=2024-05-18T00:00:00Z&min_amount=10&sort=-amount&limit=10&cursor=abc", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(searchService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"state_title":"WaitingForApproval"`)
+		assert.Contains(t, resp.Body.String(), `"next_cursor":"next"`)
+		searchService.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the cursor is invalid", func(t *testing.T) {
+		// Arrange
+		searchService := mocks.NewMockSearchPaymentsService[search.SearchPaymentsDTO](t)
+
+		searchService.On("Handle", mock.Anything, mock.Anything).
+			Return(payment_entity.SearchPage{}, custom_error.ErrCursorNotValid).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/?cursor=abc", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(searchService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+		assert.Equal(t, custom_error.AppError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Details: "cursor not valid, please restart the search",
+		}, he.Message)
+
+		searchService.AssertExpectations(t)
+	})
+
+	t.Run("Should return bad request if the query cannot be bound", func(t *testing.T) {
+		// Arrange
+		searchService := mocks.NewMockSearchPaymentsService[search.SearchPaymentsDTO](t)
+
+		req := httptest.NewRequest(echo.GET, "/?limit=abc", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(searchService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		searchService.AssertExpectations(t)
+	})
+
+	t.Run("Should return internal server error when an unexpected error occurs", func(t *testing.T) {
+		// Arrange
+		searchService := mocks.NewMockSearchPaymentsService[search.SearchPaymentsDTO](t)
+
+		searchService.On("Handle", mock.Anything, mock.Anything).
+			Return(payment_entity.SearchPage{}, assert.AnError).
+			Once()
+
+		req := httptest.NewRequest(echo.GET, "/", nil)
+
+		resp := httptest.NewRecorder()
+
+		e := echo.New()
+		ctx := e.NewContext(req, resp)
+
+		handler := NewHandler(searchService)
+
+		// Act
+		err := handler.Handle(ctx)
+
+		// Assert
+		assert.Error(t, err)
+
+		he, ok := err.(*echo.HTTPError)
+		assert.True(t, ok)
+
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+		searchService.AssertExpectations(t)
+	})
+}
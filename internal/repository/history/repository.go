@@ -0,0 +1,105 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+)
+
+type HistoryRepository struct {
+	conn *sql.DB
+}
+
+func NewHistoryRepository(conn *sql.DB) *HistoryRepository {
+	return &HistoryRepository{
+		conn: conn,
+	}
+}
+
+// Insert writes the state changes using the given transaction, so the history
+// is only stored when the payment changes it describes are
+func Insert(ctx context.Context, tx *sql.Tx, changes ...payment_entity.StateChange) error {
+	query := `
+		INSERT INTO payment_state_history (
+			id,
+			payment_id,
+			from_state,
+			to_state,
+			source,
+			actor_id,
+			payload,
+			created_at
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+	`
+
+	for _, change := range changes {
+		_, err := tx.ExecContext(ctx,
+			query,
+			change.Id,
+			change.PaymentId,
+			change.From,
+			change.To,
+			change.Source,
+			change.ActorId,
+			change.Payload,
+			change.CreatedAt)
+		if err != nil {
+			slog.ErrorContext(ctx, "error creating state change", "payment_id", change.PaymentId, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *HistoryRepository) GetByPaymentID(ctx context.Context, paymentId string) ([]payment_entity.StateChange, error) {
+	query, params, err := goqu.
+		From("payment_state_history").
+		Select("id", "payment_id", "from_state", "to_state", "source", "actor_id", "payload", "created_at").
+		Where(goqu.C("payment_id").Eq(paymentId)).
+		Order(goqu.C("created_at").Asc(), goqu.C("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := r.conn.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer statement.Close()
+
+	changes := make([]payment_entity.StateChange, 0)
+
+	for statement.Next() {
+		var change payment_entity.StateChange
+
+		err = statement.Scan(
+			&change.Id,
+			&change.PaymentId,
+			&change.From,
+			&change.To,
+			&change.Source,
+			&change.ActorId,
+			&change.Payload,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		change.RefreshStateTitles()
+
+		changes = append(changes, change)
+	}
+
+	if err := statement.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
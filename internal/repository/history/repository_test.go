@@ -0,0 +1,155 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsert(t *testing.T) {
+	t.Run("Should insert the state changes in the transaction", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		change := payment_entity.NewStateChange("payment_id", payment_entity.WaitingForApproval, payment_entity.Approved, payment_entity.ChangeOrigin{
+			Source:  payment_entity.SourceWebhook,
+			Payload: `{"approved":true}`,
+		}, now)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO payment_state_history(.+)").
+			WithArgs(change.Id, "payment_id", payment_entity.WaitingForApproval, payment_entity.Approved, payment_entity.SourceWebhook, "", `{"approved":true}`, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+
+		// Act
+		err = Insert(ctx, tx, change)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		change := payment_entity.NewStateChange("payment_id", payment_entity.Approved, payment_entity.Refunded, payment_entity.ChangeOrigin{
+			Source: payment_entity.SourceAdmin,
+		}, time.Now())
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO payment_state_history(.+)").
+			WillReturnError(assert.AnError)
+
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+
+		// Act
+		err = Insert(ctx, tx, change)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetByPaymentID(t *testing.T) {
+	columns := []string{"id", "payment_id", "from_state", "to_state", "source", "actor_id", "payload", "created_at"}
+
+	t.Run("Should return the state changes in order", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payment_state_history\" WHERE (.+) ORDER BY \"created_at\" ASC, \"id\" ASC").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("change_1", "payment_id", payment_entity.WaitingForApproval, payment_entity.Approved, "webhook", "", `{"approved":true}`, now).
+				AddRow("change_2", "payment_id", payment_entity.Approved, payment_entity.Refunded, "admin", "user_id", "", now.Add(time.Minute)))
+
+		repo := NewHistoryRepository(db)
+
+		// Act
+		changes, err := repo.GetByPaymentID(ctx, "payment_id")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, changes, 2)
+		assert.Equal(t, "change_1", changes[0].Id)
+		assert.Equal(t, "WaitingForApproval", changes[0].FromTitle)
+		assert.Equal(t, "Approved", changes[0].ToTitle)
+		assert.Equal(t, payment_entity.SourceWebhook, changes[0].Source)
+		assert.Equal(t, `{"approved":true}`, changes[0].Payload)
+		assert.Equal(t, payment_entity.SourceAdmin, changes[1].Source)
+		assert.Equal(t, "user_id", changes[1].ActorId)
+		assert.Equal(t, "Refunded", changes[1].ToTitle)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return an empty history", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payment_state_history\" WHERE (.+)").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		repo := NewHistoryRepository(db)
+
+		// Act
+		changes, err := repo.GetByPaymentID(ctx, "payment_id")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+		assert.NotNil(t, changes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payment_state_history\" WHERE (.+)").
+			WillReturnError(assert.AnError)
+
+		repo := NewHistoryRepository(db)
+
+		// Act
+		_, err = repo.GetByPaymentID(ctx, "payment_id")
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
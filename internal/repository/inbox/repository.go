@@ -0,0 +1,109 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/outbox"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+type InboxRepository struct {
+	conn *sql.DB
+}
+
+func NewInboxRepository(conn *sql.DB) *InboxRepository {
+	return &InboxRepository{
+		conn: conn,
+	}
+}
+
+// Insert records the event using the given transaction, so it is only stored when the
+// changes it caused are, a concurrent delivery of the same event fails on the primary key
+func Insert(ctx context.Context, tx *sql.Tx, event *inbox_entity.Event) error {
+	query := `
+		INSERT INTO inbox (
+			id,
+			payment_id,
+			response,
+			processed_at
+		)
+		VALUES ($1,$2,$3,$4);
+	`
+
+	_, err := tx.ExecContext(ctx,
+		query,
+		event.Id,
+		event.PaymentId,
+		event.Response,
+		event.ProcessedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return custom_error.ErrEventAlreadyProcessed
+		}
+
+		slog.ErrorContext(ctx, "error creating inbox event", "event_id", event.Id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *InboxRepository) GetByEventID(ctx context.Context, eventId string) (inbox_entity.Event, error) {
+	query, params, err := goqu.
+		From("inbox").
+		Select("id", "payment_id", "response", "processed_at").
+		Where(goqu.C("id").Eq(eventId)).
+		ToSQL()
+	if err != nil {
+		return inbox_entity.Event{}, err
+	}
+
+	var event inbox_entity.Event
+
+	err = r.conn.QueryRowContext(ctx, query, params...).Scan(
+		&event.Id,
+		&event.PaymentId,
+		&event.Response,
+		&event.ProcessedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return inbox_entity.Event{}, custom_error.ErrEventNotFound
+	}
+	if err != nil {
+		return inbox_entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+func (r *InboxRepository) Create(ctx context.Context, event *inbox_entity.Event, messages ...outbox_entity.Message) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = Insert(ctx, tx, event)
+	if err == nil {
+		err = outbox.Insert(ctx, tx, messages...)
+	}
+	if err != nil {
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
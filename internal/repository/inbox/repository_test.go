@@ -0,0 +1,190 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetByEventID(t *testing.T) {
+	t.Run("Should get the event by id", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"inbox\" WHERE (.+)").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "payment_id", "response", "processed_at"}).
+				AddRow("event_id", "payment_id", `{"payment_id":"payment_id"}`, now))
+
+		repo := NewInboxRepository(db)
+
+		// Act
+		event, err := repo.GetByEventID(ctx, "event_id")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "event_id", event.Id)
+		assert.Equal(t, "payment_id", event.PaymentId)
+		assert.Equal(t, `{"payment_id":"payment_id"}`, event.Response)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if the event is not found", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"inbox\" WHERE (.+)").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := NewInboxRepository(db)
+
+		// Act
+		_, err = repo.GetByEventID(ctx, "event_id")
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrEventNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"inbox\" WHERE (.+)").
+			WillReturnError(assert.AnError)
+
+		repo := NewInboxRepository(db)
+
+		// Act
+		_, err = repo.GetByEventID(ctx, "event_id")
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("Should record the event and the outbox messages in the same transaction", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		event, err := inbox_entity.NewEvent("event_id", "payment_id", "response", now)
+		assert.NoError(t, err)
+
+		message, err := outbox_entity.NewMessage("topic", "payload", now)
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?inbox(.+)?").
+			WithArgs(event.Id, event.PaymentId, event.Response, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewInboxRepository(db)
+
+		// Act
+		err = repo.Create(ctx, &event, message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if the event was already processed", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		event, err := inbox_entity.NewEvent("event_id", "payment_id", "response", time.Now())
+		assert.NoError(t, err)
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?inbox(.+)?").
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		mock.ExpectRollback()
+
+		repo := NewInboxRepository(db)
+
+		// Act
+		err = repo.Create(ctx, &event, message)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrEventAlreadyProcessed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if an error occurs when writing the outbox messages", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		event, err := inbox_entity.NewEvent("event_id", "payment_id", "response", time.Now())
+		assert.NoError(t, err)
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?inbox(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewInboxRepository(db)
+
+		// Act
+		err = repo.Create(ctx, &event, message)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
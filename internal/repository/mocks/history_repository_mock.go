@@ -0,0 +1,59 @@
+// Code generated by mockery v2.42.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	payment_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockHistoryRepository is an autogenerated mock type for the HistoryRepository type
+type MockHistoryRepository struct {
+	mock.Mock
+}
+
+// GetByPaymentID provides a mock function with given fields: ctx, paymentId
+func (_m *MockHistoryRepository) GetByPaymentID(ctx context.Context, paymentId string) ([]payment_entity.StateChange, error) {
+	ret := _m.Called(ctx, paymentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByPaymentID")
+	}
+
+	var r0 []payment_entity.StateChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]payment_entity.StateChange, error)); ok {
+		return rf(ctx, paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []payment_entity.StateChange); ok {
+		r0 = rf(ctx, paymentId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payment_entity.StateChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockHistoryRepository creates a new instance of MockHistoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockHistoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockHistoryRepository {
+	mock := &MockHistoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.42.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	inbox_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	mock "github.com/stretchr/testify/mock"
+
+	outbox_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+)
+
+// MockInboxRepository is an autogenerated mock type for the InboxRepository type
+type MockInboxRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, event, messages
+func (_m *MockInboxRepository) Create(ctx context.Context, event *inbox_entity.Event, messages ...outbox_entity.Message) error {
+	_va := make([]interface{}, len(messages))
+	for _i := range messages {
+		_va[_i] = messages[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, event)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *inbox_entity.Event, ...outbox_entity.Message) error); ok {
+		r0 = rf(ctx, event, messages...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByEventID provides a mock function with given fields: ctx, eventId
+func (_m *MockInboxRepository) GetByEventID(ctx context.Context, eventId string) (inbox_entity.Event, error) {
+	ret := _m.Called(ctx, eventId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByEventID")
+	}
+
+	var r0 inbox_entity.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (inbox_entity.Event, error)); ok {
+		return rf(ctx, eventId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) inbox_entity.Event); ok {
+		r0 = rf(ctx, eventId)
+	} else {
+		r0 = ret.Get(0).(inbox_entity.Event)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockInboxRepository creates a new instance of MockInboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInboxRepository {
+	mock := &MockInboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
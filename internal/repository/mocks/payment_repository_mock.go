@@ -5,8 +5,16 @@ package mocks
 import (
 	context "context"
 
-	payment_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	inbox_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
 	mock "github.com/stretchr/testify/mock"
+
+	outbox_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+
+	payment_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+
+	refund_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+
+	time "time"
 )
 
 // MockPaymentRepository is an autogenerated mock type for the PaymentRepository type
@@ -14,6 +22,31 @@ type MockPaymentRepository struct {
 	mock.Mock
 }
 
+// CompleteRefund provides a mock function with given fields: ctx, payment, refund, messages
+func (_m *MockPaymentRepository) CompleteRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund, messages ...outbox_entity.Message) error {
+	_va := make([]interface{}, len(messages))
+	for _i := range messages {
+		_va[_i] = messages[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, payment, refund)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteRefund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payment_entity.Payment, *refund_entity.Refund, ...outbox_entity.Message) error); ok {
+		r0 = rf(ctx, payment, refund, messages...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, payment
 func (_m *MockPaymentRepository) Create(ctx context.Context, payment *payment_entity.Payment) error {
 	ret := _m.Called(ctx, payment)
@@ -32,6 +65,42 @@ func (_m *MockPaymentRepository) Create(ctx context.Context, payment *payment_en
 	return r0
 }
 
+// CreatePendingRefund provides a mock function with given fields: ctx, payment, refund
+func (_m *MockPaymentRepository) CreatePendingRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund) error {
+	ret := _m.Called(ctx, payment, refund)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePendingRefund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payment_entity.Payment, *refund_entity.Refund) error); ok {
+		r0 = rf(ctx, payment, refund)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailRefund provides a mock function with given fields: ctx, refund
+func (_m *MockPaymentRepository) FailRefund(ctx context.Context, refund *refund_entity.Refund) error {
+	ret := _m.Called(ctx, refund)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailRefund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *refund_entity.Refund) error); ok {
+		r0 = rf(ctx, refund)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetByID provides a mock function with given fields: ctx, paymentId
 func (_m *MockPaymentRepository) GetByID(ctx context.Context, paymentId string) (payment_entity.Payment, error) {
 	ret := _m.Called(ctx, paymentId)
@@ -90,17 +159,137 @@ func (_m *MockPaymentRepository) GetByOrderID(ctx context.Context, orderId strin
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, payment
-func (_m *MockPaymentRepository) Update(ctx context.Context, payment *payment_entity.Payment) error {
-	ret := _m.Called(ctx, payment)
+// GetOverdue provides a mock function with given fields: ctx, createdBefore, limit
+func (_m *MockPaymentRepository) GetOverdue(ctx context.Context, createdBefore time.Time, limit int) ([]payment_entity.Payment, error) {
+	ret := _m.Called(ctx, createdBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverdue")
+	}
+
+	var r0 []payment_entity.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]payment_entity.Payment, error)); ok {
+		return rf(ctx, createdBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []payment_entity.Payment); ok {
+		r0 = rf(ctx, createdBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payment_entity.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, createdBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingRefund provides a mock function with given fields: ctx, paymentId
+func (_m *MockPaymentRepository) GetPendingRefund(ctx context.Context, paymentId string) (refund_entity.Refund, error) {
+	ret := _m.Called(ctx, paymentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingRefund")
+	}
+
+	var r0 refund_entity.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (refund_entity.Refund, error)); ok {
+		return rf(ctx, paymentId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) refund_entity.Refund); ok {
+		r0 = rf(ctx, paymentId)
+	} else {
+		r0 = ret.Get(0).(refund_entity.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, paymentId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, filter
+func (_m *MockPaymentRepository) Search(ctx context.Context, filter payment_entity.SearchFilter) ([]payment_entity.Payment, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []payment_entity.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payment_entity.SearchFilter) ([]payment_entity.Payment, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payment_entity.SearchFilter) []payment_entity.Payment); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payment_entity.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payment_entity.SearchFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, payment, messages
+func (_m *MockPaymentRepository) Update(ctx context.Context, payment *payment_entity.Payment, messages ...outbox_entity.Message) error {
+	_va := make([]interface{}, len(messages))
+	for _i := range messages {
+		_va[_i] = messages[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, payment)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *payment_entity.Payment) error); ok {
-		r0 = rf(ctx, payment)
+	if rf, ok := ret.Get(0).(func(context.Context, *payment_entity.Payment, ...outbox_entity.Message) error); ok {
+		r0 = rf(ctx, payment, messages...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFromEvent provides a mock function with given fields: ctx, payment, event, messages
+func (_m *MockPaymentRepository) UpdateFromEvent(ctx context.Context, payment *payment_entity.Payment, event *inbox_entity.Event, messages ...outbox_entity.Message) error {
+	_va := make([]interface{}, len(messages))
+	for _i := range messages {
+		_va[_i] = messages[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, payment, event)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFromEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *payment_entity.Payment, *inbox_entity.Event, ...outbox_entity.Message) error); ok {
+		r0 = rf(ctx, payment, event, messages...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,160 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/tracing"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+)
+
+type OutboxRepository struct {
+	conn *sql.DB
+}
+
+func NewOutboxRepository(conn *sql.DB) *OutboxRepository {
+	return &OutboxRepository{
+		conn: conn,
+	}
+}
+
+// Insert writes the messages using the given transaction, so they can be
+// persisted atomically with the changes that originated them, along with
+// the trace of the context so the relay can continue it
+func Insert(ctx context.Context, tx *sql.Tx, messages ...outbox_entity.Message) error {
+	query := `
+		INSERT INTO outbox (
+			id,
+			topic,
+			payload,
+			attempts,
+			last_error,
+			next_attempt_at,
+			created_at,
+			trace_context
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+	`
+
+	traceContext := tracing.Inject(ctx)
+
+	for _, message := range messages {
+		_, err := tx.ExecContext(ctx,
+			query,
+			message.Id,
+			message.Topic,
+			message.Payload,
+			message.Attempts,
+			message.LastError,
+			message.NextAttemptAt,
+			message.CreatedAt,
+			traceContext)
+		if err != nil {
+			slog.ErrorContext(ctx, "error creating outbox message", "message_id", message.Id, "topic", message.Topic, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) Create(ctx context.Context, messages ...outbox_entity.Message) error {
+	tx, err := r.conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := Insert(ctx, tx, messages...); err != nil {
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, now time.Time, lockUntil time.Time, limit int) ([]outbox_entity.Message, error) {
+	query := `
+		UPDATE outbox
+		SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE sent_at IS NULL AND next_attempt_at <= $2
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, topic, payload, attempts, last_error, next_attempt_at, created_at, trace_context;
+	`
+
+	statement, err := r.conn.QueryContext(ctx, query, lockUntil, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer statement.Close()
+
+	messages := make([]outbox_entity.Message, 0)
+
+	for statement.Next() {
+		var message outbox_entity.Message
+
+		err = statement.Scan(
+			&message.Id,
+			&message.Topic,
+			&message.Payload,
+			&message.Attempts,
+			&message.LastError,
+			&message.NextAttemptAt,
+			&message.CreatedAt,
+			&message.TraceContext,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	if err := statement.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+
+	return messages, nil
+}
+
+// MarkAsSent and MarkAsFailed bind the times as parameters, like ClaimPending compares them,
+// so the times written and the ones compared are read in the same zone
+func (r *OutboxRepository) MarkAsSent(ctx context.Context, message *outbox_entity.Message) error {
+	query := `
+		UPDATE outbox
+		SET attempts = $1, last_error = $2, sent_at = $3
+		WHERE id = $4;
+	`
+
+	_, err := r.conn.ExecContext(ctx, query, message.Attempts, message.LastError, message.SentAt, message.Id)
+
+	return err
+}
+
+func (r *OutboxRepository) MarkAsFailed(ctx context.Context, message *outbox_entity.Message) error {
+	query := `
+		UPDATE outbox
+		SET attempts = $1, last_error = $2, next_attempt_at = $3
+		WHERE id = $4;
+	`
+
+	_, err := r.conn.ExecContext(ctx, query, message.Attempts, message.LastError, message.NextAttemptAt, message.Id)
+
+	return err
+}
@@ -0,0 +1,273 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/tracing"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("Should create the outbox messages", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		err = repo.Create(ctx, message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		err = repo.Create(ctx, message)
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInsert(t *testing.T) {
+	t.Run("Should store the trace of the context", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		provider := tracing.NewSyncTracerProvider(tracetest.NewInMemoryExporter())
+		t.Cleanup(func() {
+			_ = provider.Shutdown(context.Background())
+			otel.SetTracerProvider(noop.NewTracerProvider())
+			otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		})
+
+		ctx, span := tracing.Tracer().Start(context.Background(), "webhook")
+		defer span.End()
+
+		now := time.Now()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", now)
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WithArgs(message.Id, message.Topic, message.Payload, 0, "", now, now, tracing.Inject(ctx)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+
+		// Act
+		err = Insert(ctx, tx, message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Contains(t, tracing.Inject(ctx), span.SpanContext().TraceID().String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestClaimPending(t *testing.T) {
+	t.Run("Should claim the pending messages ordered by creation", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+		lockUntil := now.Add(time.Minute)
+
+		mock.ExpectQuery("UPDATE outbox(.+)FOR UPDATE SKIP LOCKED(.+)RETURNING(.+)").
+			WithArgs(lockUntil, now, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "payload", "attempts", "last_error", "next_attempt_at", "created_at", "trace_context"}).
+				AddRow("2", "topic", "{}", 0, "", lockUntil, now, "").
+				AddRow("1", "topic", "{}", 1, "error", lockUntil, now.Add(-time.Second), `{"traceparent":"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}`))
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		messages, err := repo.ClaimPending(ctx, now, lockUntil, 10)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "1", messages[0].Id)
+		assert.Equal(t, "error", messages[0].LastError)
+		assert.Contains(t, messages[0].TraceContext, "traceparent")
+		assert.Equal(t, "2", messages[1].Id)
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("UPDATE outbox(.+)").
+			WillReturnError(assert.AnError)
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		messages, err := repo.ClaimPending(ctx, now, now, 10)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("Should return error if scan fails", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("UPDATE outbox(.+)").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "payload", "attempts", "last_error", "next_attempt_at", "created_at", "trace_context"}).
+				AddRow("1", "topic", "{}", "abc", "", now, now, ""))
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		messages, err := repo.ClaimPending(ctx, now, now, 10)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Empty(t, messages)
+	})
+}
+
+func TestMarkAsSent(t *testing.T) {
+	t.Run("Should mark the message as sent", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		message.MarkAsSent(time.Now())
+
+		mock.ExpectExec("UPDATE outbox(.+)sent_at = (.+)").
+			WithArgs(message.Attempts, message.LastError, message.SentAt, message.Id).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		err = repo.MarkAsSent(ctx, &message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMarkAsFailed(t *testing.T) {
+	t.Run("Should mark the message as failed", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		message.MarkAsFailed(assert.AnError, time.Now().Add(time.Minute))
+
+		mock.ExpectExec("UPDATE outbox(.+)next_attempt_at = (.+)").
+			WithArgs(message.Attempts, message.LastError, message.NextAttemptAt, message.Id).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		err = repo.MarkAsFailed(ctx, &message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectExec("UPDATE (.+)?outbox(.+)?").
+			WillReturnError(assert.AnError)
+
+		repo := NewOutboxRepository(db)
+
+		// Act
+		err = repo.MarkAsFailed(ctx, &outbox_entity.Message{})
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
@@ -4,10 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/inbox"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/outbox"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 )
 
 type PaymentRepository struct {
@@ -28,10 +37,13 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *payment_entity.
 			total_items,
 			amount,
 			state,
+			charge_id,
+			qr_code,
+			refunded_amount,
 			created_at,
 			updated_at
 		)
-		VALUES ($1,$2,$3,$4,$5,$6,$7);
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10);
 	`
 	queryInsertPaymentItems := `
 		INSERT INTO payment_items (
@@ -39,9 +51,12 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *payment_entity.
 			order_id,
 			payment_id,
 			name,
-			quantity
+			quantity,
+			unit_price,
+			discount,
+			total
 		)
-		VALUES ($1,$2,$3,$4,$5);
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
 	`
 
 	tx, err := r.conn.BeginTx(ctx, &sql.TxOptions{})
@@ -56,6 +71,9 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *payment_entity.
 		payment.TotalItems,
 		payment.Amount,
 		payment.State,
+		payment.ChargeId,
+		payment.QrCode,
+		payment.RefundedAmount,
 		payment.CreatedAt,
 		payment.UpdatedAt)
 	if err != nil {
@@ -75,7 +93,10 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *payment_entity.
 			payment.OrderId,
 			payment.PaymentId,
 			item.Name,
-			item.Quantity)
+			item.Quantity,
+			item.UnitPrice,
+			item.Discount,
+			item.Total)
 		if err != nil {
 			slog.ErrorContext(ctx, "error creating payment item", "item_id", item.Id, "error", err)
 			errTx := tx.Rollback()
@@ -93,7 +114,7 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *payment_entity.
 func (r *PaymentRepository) GetByID(ctx context.Context, paymentId string) (payment_entity.Payment, error) {
 	sql, params, err := goqu.
 		From("payments").
-		Select("order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at").
+		Select("order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version").
 		Where(goqu.C("payment_id").Eq(paymentId)).
 		ToSQL()
 	if err != nil {
@@ -115,8 +136,12 @@ func (r *PaymentRepository) GetByID(ctx context.Context, paymentId string) (paym
 			&payment.TotalItems,
 			&payment.Amount,
 			&payment.State,
+			&payment.ChargeId,
+			&payment.QrCode,
+			&payment.RefundedAmount,
 			&payment.CreatedAt,
 			&payment.UpdatedAt,
+			&payment.Version,
 		)
 		if err != nil {
 			return payment_entity.Payment{}, err
@@ -127,9 +152,11 @@ func (r *PaymentRepository) GetByID(ctx context.Context, paymentId string) (paym
 		return payment_entity.Payment{}, custom_error.ErrPaymentNotFound
 	}
 
+	payment.RefreshStateTitle()
+
 	sql, params, err = goqu.
 		From("payment_items").
-		Select("id", "name", "quantity").
+		Select("id", "name", "quantity", "unit_price", "discount", "total").
 		Where(goqu.C("payment_id").Eq(paymentId)).
 		ToSQL()
 	if err != nil {
@@ -151,6 +178,9 @@ func (r *PaymentRepository) GetByID(ctx context.Context, paymentId string) (paym
 			&item.Id,
 			&item.Name,
 			&item.Quantity,
+			&item.UnitPrice,
+			&item.Discount,
+			&item.Total,
 		)
 		if err != nil {
 			return payment_entity.Payment{}, err
@@ -162,96 +192,529 @@ func (r *PaymentRepository) GetByID(ctx context.Context, paymentId string) (paym
 	return payment, nil
 }
 
+// GetByOrderID loads the payments of the order and their items with a single query,
+// joining the items so the number of round trips does not grow with the payments
 func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderId string) ([]payment_entity.Payment, error) {
 	var payments []payment_entity.Payment
 
-	sql, params, err := goqu.
-		From("payments").
-		Where(goqu.C("order_id").Eq(orderId)).
+	query, params, err := goqu.
+		From(goqu.T("payments").As("p")).
+		LeftJoin(
+			goqu.T("payment_items").As("i"),
+			goqu.On(
+				goqu.I("i.payment_id").Eq(goqu.I("p.payment_id")),
+				goqu.I("i.order_id").Eq(goqu.I("p.order_id")),
+			),
+		).
+		Select(
+			"p.order_id", "p.payment_id", "p.total_items", "p.amount", "p.state", "p.charge_id", "p.qr_code", "p.refunded_amount", "p.created_at", "p.updated_at", "p.version",
+			"i.id", "i.name", "i.quantity", "i.unit_price", "i.discount", "i.total",
+		).
+		Where(goqu.I("p.order_id").Eq(orderId)).
+		Order(goqu.I("p.created_at").Asc(), goqu.I("p.payment_id").Asc(), goqu.I("i.id").Asc()).
 		ToSQL()
 	if err != nil {
 		return payments, err
 	}
 
-	paymentStatement, err := r.conn.QueryContext(ctx, sql, params...)
+	statement, err := r.conn.QueryContext(ctx, query, params...)
 	if err != nil {
 		return payments, err
 	}
-	defer paymentStatement.Close()
+	defer statement.Close()
 
-	for paymentStatement.Next() {
+	// rows of the same payment are adjacent, as the result is ordered by payment
+	for statement.Next() {
 		var payment payment_entity.Payment
+		var itemId, itemName sql.NullString
+		var itemQuantity sql.NullInt64
+		// the prices scan a missing item as zero, only the id tells whether there is one
+		var itemUnitPrice, itemDiscount, itemTotal money.Money
 
-		err = paymentStatement.Scan(
+		err = statement.Scan(
 			&payment.OrderId,
 			&payment.PaymentId,
 			&payment.TotalItems,
 			&payment.Amount,
 			&payment.State,
+			&payment.ChargeId,
+			&payment.QrCode,
+			&payment.RefundedAmount,
 			&payment.CreatedAt,
 			&payment.UpdatedAt,
+			&payment.Version,
+			&itemId,
+			&itemName,
+			&itemQuantity,
+			&itemUnitPrice,
+			&itemDiscount,
+			&itemTotal,
 		)
 		if err != nil {
 			return payments, err
 		}
 
-		sql, params, err = goqu.
-			From("payment_items").
-			Select("id", "name", "quantity").
-			Where(goqu.And(
-				goqu.Ex{"payment_id": payment.PaymentId},
-				goqu.Ex{"order_id": payment.OrderId},
-			)).
-			ToSQL()
+		if len(payments) == 0 || payments[len(payments)-1].PaymentId != payment.PaymentId {
+			payment.Items = make([]payment_entity.PaymentItem, 0)
+			payment.RefreshStateTitle()
+			payments = append(payments, payment)
+		}
+
+		if itemId.Valid {
+			last := &payments[len(payments)-1]
+			last.Items = append(last.Items, payment_entity.PaymentItem{
+				Id:       itemId.String,
+				Name:     itemName.String,
+				Quantity: int(itemQuantity.Int64),
+
+				UnitPrice: itemUnitPrice,
+				Discount:  itemDiscount,
+				Total:     itemTotal,
+			})
+		}
+	}
+
+	if err := statement.Err(); err != nil {
+		return payments, err
+	}
+
+	return payments, nil
+}
+
+// GetOverdue returns the payments still waiting for approval that were created
+// before the given time, without their items
+func (r *PaymentRepository) GetOverdue(ctx context.Context, createdBefore time.Time, limit int) ([]payment_entity.Payment, error) {
+	payments := make([]payment_entity.Payment, 0)
+
+	sql, params, err := goqu.
+		From("payments").
+		Select("order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version").
+		Where(
+			goqu.C("state").Eq(payment_entity.WaitingForApproval),
+			goqu.C("created_at").Lte(createdBefore),
+		).
+		Order(goqu.C("created_at").Asc()).
+		Limit(uint(limit)).
+		ToSQL()
+	if err != nil {
+		return payments, err
+	}
+
+	statement, err := r.conn.QueryContext(ctx, sql, params...)
+	if err != nil {
+		return payments, err
+	}
+	defer statement.Close()
+
+	for statement.Next() {
+		var payment payment_entity.Payment
+
+		err = statement.Scan(
+			&payment.OrderId,
+			&payment.PaymentId,
+			&payment.TotalItems,
+			&payment.Amount,
+			&payment.State,
+			&payment.ChargeId,
+			&payment.QrCode,
+			&payment.RefundedAmount,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+			&payment.Version,
+		)
 		if err != nil {
 			return payments, err
 		}
 
-		itemsStatement, err := r.conn.QueryContext(ctx, sql, params...)
+		payment.RefreshStateTitle()
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// Search returns the payments matching the filter, without their items
+func (r *PaymentRepository) Search(ctx context.Context, filter payment_entity.SearchFilter) ([]payment_entity.Payment, error) {
+	payments := make([]payment_entity.Payment, 0)
+
+	query, params, err := searchQuery(filter).ToSQL()
+	if err != nil {
+		return payments, err
+	}
+
+	statement, err := r.conn.QueryContext(ctx, query, params...)
+	if err != nil {
+		return payments, err
+	}
+	defer statement.Close()
+
+	for statement.Next() {
+		var payment payment_entity.Payment
+
+		err = statement.Scan(
+			&payment.OrderId,
+			&payment.PaymentId,
+			&payment.TotalItems,
+			&payment.Amount,
+			&payment.State,
+			&payment.ChargeId,
+			&payment.QrCode,
+			&payment.RefundedAmount,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+			&payment.Version,
+		)
 		if err != nil {
 			return payments, err
 		}
-		defer itemsStatement.Close()
-
-		payment.Items = make([]payment_entity.PaymentItem, 0)
-
-		for itemsStatement.Next() {
-			item := payment_entity.PaymentItem{}
-			err := itemsStatement.Scan(
-				&item.Id,
-				&item.Name,
-				&item.Quantity,
-			)
-			if err != nil {
-				return payments, err
-			}
-			payment.Items = append(payment.Items, item)
-		}
 
 		payment.RefreshStateTitle()
 		payments = append(payments, payment)
 	}
 
+	if err := statement.Err(); err != nil {
+		return payments, err
+	}
+
 	return payments, nil
 }
 
-func (r *PaymentRepository) Update(ctx context.Context, payment *payment_entity.Payment) error {
+// searchQuery orders the payments by the sort field and then by the payment id, so the
+// position of the last payment of a page (the keyset) is enough to query the next one
+func searchQuery(filter payment_entity.SearchFilter) *goqu.SelectDataset {
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = payment_entity.SortByCreatedAt
+	}
+
+	sortColumn := goqu.C(string(sortBy))
+	idColumn := goqu.C("payment_id")
+
+	conditions := make([]exp.Expression, 0)
+
+	if len(filter.States) > 0 {
+		conditions = append(conditions, goqu.C("state").In(filter.States))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, goqu.C("created_at").Gte(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, goqu.C("created_at").Lte(*filter.CreatedTo))
+	}
+	if filter.UpdatedFrom != nil {
+		conditions = append(conditions, goqu.C("updated_at").Gte(*filter.UpdatedFrom))
+	}
+	if filter.UpdatedTo != nil {
+		conditions = append(conditions, goqu.C("updated_at").Lte(*filter.UpdatedTo))
+	}
+	if filter.MinAmount != nil {
+		conditions = append(conditions, goqu.C("amount").Gte(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		conditions = append(conditions, goqu.C("amount").Lte(*filter.MaxAmount))
+	}
+
+	if filter.After != nil {
+		var value interface{}
+
+		switch sortBy {
+		case payment_entity.SortByUpdatedAt:
+			value = filter.After.UpdatedAt
+		case payment_entity.SortByAmount:
+			value = filter.After.Amount
+		default:
+			value = filter.After.CreatedAt
+		}
+
+		if filter.Descending {
+			conditions = append(conditions, goqu.Or(
+				sortColumn.Lt(value),
+				goqu.And(sortColumn.Eq(value), idColumn.Lt(filter.After.PaymentId)),
+			))
+		} else {
+			conditions = append(conditions, goqu.Or(
+				sortColumn.Gt(value),
+				goqu.And(sortColumn.Eq(value), idColumn.Gt(filter.After.PaymentId)),
+			))
+		}
+	}
+
+	order := []exp.OrderedExpression{sortColumn.Asc(), idColumn.Asc()}
+	if filter.Descending {
+		order = []exp.OrderedExpression{sortColumn.Desc(), idColumn.Desc()}
+	}
+
+	dataset := goqu.
+		From("payments").
+		Select("order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version").
+		Where(conditions...).
+		Order(order...)
+
+	if filter.Limit > 0 {
+		dataset = dataset.Limit(uint(filter.Limit))
+	}
+
+	return dataset
+}
+
+func (r *PaymentRepository) Update(ctx context.Context, payment *payment_entity.Payment, messages ...outbox_entity.Message) error {
+	return r.UpdateFromEvent(ctx, payment, nil, messages...)
+}
+
+// UpdateFromEvent updates the payment recording the event that caused the change, when given
+func (r *PaymentRepository) UpdateFromEvent(ctx context.Context, payment *payment_entity.Payment, event *inbox_entity.Event, messages ...outbox_entity.Message) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if event != nil {
+		err = inbox.Insert(ctx, tx, event)
+	}
+	if err == nil {
+		err = update(ctx, tx, payment)
+	}
+	if err == nil {
+		err = outbox.Insert(ctx, tx, messages...)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error updating payment", "payment_id", payment.PaymentId, "error", err)
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return commit(tx, payment)
+}
+
+// CreatePendingRefund records the refund before the gateway is called, the version of the payment
+// is checked and moved forward, so a concurrent change or refund fails with ErrPaymentConcurrentUpdate
+func (r *PaymentRepository) CreatePendingRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund) error {
+	queryInsertRefund := `
+		INSERT INTO refunds (
+			id,
+			order_id,
+			payment_id,
+			amount,
+			reason,
+			state,
+			gateway_refund_id,
+			created_at
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+	`
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		queryInsertRefund,
+		refund.Id,
+		refund.OrderId,
+		refund.PaymentId,
+		refund.Amount,
+		refund.Reason,
+		refund.State,
+		refund.GatewayRefundId,
+		refund.CreatedAt)
+	if err == nil {
+		err = update(ctx, tx, payment)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error creating refund", "payment_id", payment.PaymentId, "refund_id", refund.Id, "error", err)
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return commit(tx, payment)
+}
+
+// GetPendingRefund returns the refund of the payment that was sent to the gateway without being settled
+func (r *PaymentRepository) GetPendingRefund(ctx context.Context, paymentId string) (refund_entity.Refund, error) {
 	sql, params, err := goqu.
+		From("refunds").
+		Select("id", "order_id", "payment_id", "amount", "reason", "state", "gateway_refund_id", "created_at").
+		Where(
+			goqu.C("payment_id").Eq(paymentId),
+			goqu.C("state").Eq(refund_entity.RefundPending),
+		).
+		ToSQL()
+	if err != nil {
+		return refund_entity.Refund{}, err
+	}
+
+	statement, err := r.conn.QueryContext(ctx, sql, params...)
+	if err != nil {
+		return refund_entity.Refund{}, err
+	}
+	defer statement.Close()
+
+	var refund refund_entity.Refund
+
+	for statement.Next() {
+		err = statement.Scan(
+			&refund.Id,
+			&refund.OrderId,
+			&refund.PaymentId,
+			&refund.Amount,
+			&refund.Reason,
+			&refund.State,
+			&refund.GatewayRefundId,
+			&refund.CreatedAt,
+		)
+		if err != nil {
+			return refund_entity.Refund{}, err
+		}
+	}
+
+	if refund.Id == "" {
+		return refund_entity.Refund{}, custom_error.ErrRefundNotFound
+	}
+
+	return refund, nil
+}
+
+// CompleteRefund settles the pending refund and updates the payment in the same transaction,
+// ErrRefundNotPending is returned when the refund was settled meanwhile by another request
+func (r *PaymentRepository) CompleteRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund, messages ...outbox_entity.Message) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = settleRefund(ctx, tx, refund)
+	if err == nil {
+		err = update(ctx, tx, payment)
+	}
+	if err == nil {
+		err = outbox.Insert(ctx, tx, messages...)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error completing refund", "payment_id", payment.PaymentId, "refund_id", refund.Id, "error", err)
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return commit(tx, payment)
+}
+
+// FailRefund settles the pending refund the gateway rejected, the payment is left as it is
+func (r *PaymentRepository) FailRefund(ctx context.Context, refund *refund_entity.Refund) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := settleRefund(ctx, tx, refund); err != nil {
+		slog.ErrorContext(ctx, "error failing refund", "payment_id", refund.PaymentId, "refund_id", refund.Id, "error", err)
+		errTx := tx.Rollback()
+		if errTx != nil {
+			slog.ErrorContext(ctx, "error rolling back transaction", "error", errTx)
+			return errTx
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// settleRefund moves the refund out of the pending state, only when it is still pending
+func settleRefund(ctx context.Context, tx *sql.Tx, refund *refund_entity.Refund) error {
+	query, params, err := goqu.
+		Update("refunds").
+		Set(goqu.Record{
+			"state":             refund.State,
+			"gateway_refund_id": refund.GatewayRefundId,
+		}).
+		Where(
+			goqu.C("id").Eq(refund.Id),
+			goqu.C("state").Eq(refund_entity.RefundPending),
+		).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, query, params...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		slog.WarnContext(ctx, "refund already settled", "payment_id", refund.PaymentId, "refund_id", refund.Id)
+		return custom_error.ErrRefundNotPending
+	}
+
+	return nil
+}
+
+// update only touches the payment when it still has the version it was loaded with,
+// so a concurrent change made in between fails with ErrPaymentConcurrentUpdate instead of being overwritten
+func update(ctx context.Context, tx *sql.Tx, payment *payment_entity.Payment) error {
+	query, params, err := goqu.
 		Update("payments").
 		Set(goqu.Record{
-			"state":      payment.State,
-			"updated_at": payment.UpdatedAt,
+			"state":           payment.State,
+			"charge_id":       payment.ChargeId,
+			"qr_code":         payment.QrCode,
+			"refunded_amount": payment.RefundedAmount,
+			"updated_at":      payment.UpdatedAt,
+			"version":         goqu.L("? + 1", goqu.C("version")),
 		}).
-		Where(goqu.C("payment_id").Eq(payment.PaymentId)).
+		Where(
+			goqu.C("payment_id").Eq(payment.PaymentId),
+			goqu.C("version").Eq(payment.Version),
+		).
 		ToSQL()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.conn.ExecContext(ctx, sql, params...)
+	result, err := tx.ExecContext(ctx, query, params...)
 	if err != nil {
 		return err
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		slog.WarnContext(ctx, "payment changed concurrently", "payment_id", payment.PaymentId, "version", payment.Version)
+		return custom_error.ErrPaymentConcurrentUpdate
+	}
+
+	return history.Insert(ctx, tx, payment.StateChanges...)
+}
+
+// commit moves the payment to the stored version and clears the state changes,
+// so they are not recorded again by a later update of the same payment
+func commit(tx *sql.Tx, payment *payment_entity.Payment) error {
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	payment.Version++
+	payment.ClearStateChanges()
+
 	return nil
 }
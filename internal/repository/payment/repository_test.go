@@ -2,12 +2,19 @@ package payment
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,12 +27,15 @@ func TestCreate(t *testing.T) {
 
 		ctx := context.Background()
 
+		item := payment_entity.NewPaymentItem(uuid.NewString(), "item", 2, money.New(1000, money.BRL), money.New(150, money.BRL))
+
 		mock.ExpectBegin()
 
 		mock.ExpectExec("INSERT INTO (.+)?payments(.+)?").
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectExec("INSERT INTO (.+)?payment_items(.+)?").
+			WithArgs(item.Id, "order_id", "payment_id", "item", 2, "10.00", "1.50", "18.50").
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectCommit()
@@ -34,13 +44,14 @@ func TestCreate(t *testing.T) {
 
 		// Act
 		err = repo.Create(ctx, &payment_entity.Payment{
-			Items: []payment_entity.PaymentItem{
-				payment_entity.NewPaymentItem(uuid.NewString(), "item", 1),
-			},
+			OrderId:   "order_id",
+			PaymentId: "payment_id",
+			Items:     []payment_entity.PaymentItem{item},
 		})
 
 		// Assert
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Should return error if an error occurs", func(t *testing.T) {
@@ -90,7 +101,7 @@ func TestCreate(t *testing.T) {
 		// Act
 		err = repo.Create(ctx, &payment_entity.Payment{
 			Items: []payment_entity.PaymentItem{
-				payment_entity.NewPaymentItem(uuid.NewString(), "item", 1),
+				payment_entity.NewPaymentItem(uuid.NewString(), "item", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 			},
 		})
 
@@ -111,7 +122,7 @@ func TestGetByID(t *testing.T) {
 		now := time.Now()
 
 		expectedPaymentItems := []payment_entity.PaymentItem{
-			payment_entity.NewPaymentItem(uuid.NewString(), "item", 1),
+			payment_entity.NewPaymentItem(uuid.NewString(), "item", 1, money.New(123, money.BRL), money.New(0, money.BRL)),
 		}
 
 		expectedPayment := payment_entity.Payment{
@@ -119,19 +130,23 @@ func TestGetByID(t *testing.T) {
 			PaymentId:  "payment_id",
 			Items:      expectedPaymentItems,
 			TotalItems: 1,
-			Amount:     1.0,
+			Amount:     money.New(100, money.BRL),
 			State:      payment_entity.WaitingForApproval,
+			StateTitle: "WaitingForApproval",
+			ChargeId:   "charge_id",
+			QrCode:     "qr_code",
 			CreatedAt:  now,
 			UpdatedAt:  now,
+			Version:    3,
 		}
 
 		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at"}).
-				AddRow(expectedPayment.OrderId, expectedPayment.PaymentId, expectedPayment.TotalItems, expectedPayment.Amount, expectedPayment.State, expectedPayment.CreatedAt, expectedPayment.UpdatedAt))
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}).
+				AddRow(expectedPayment.OrderId, expectedPayment.PaymentId, expectedPayment.TotalItems, expectedPayment.Amount, expectedPayment.State, expectedPayment.ChargeId, expectedPayment.QrCode, expectedPayment.RefundedAmount, expectedPayment.CreatedAt, expectedPayment.UpdatedAt, expectedPayment.Version))
 
 		mock.ExpectQuery("SELECT (.+)?payment_items(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "quantity"}).
-				AddRow(expectedPaymentItems[0].Id, expectedPaymentItems[0].Name, expectedPaymentItems[0].Quantity))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "quantity", "unit_price", "discount", "total"}).
+				AddRow(expectedPaymentItems[0].Id, expectedPaymentItems[0].Name, expectedPaymentItems[0].Quantity, "1.23", "0.00", "1.23"))
 
 		repo := NewPaymentRepository(db)
 
@@ -173,7 +188,7 @@ func TestGetByID(t *testing.T) {
 		ctx := context.Background()
 
 		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at"}))
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}))
 
 		repo := NewPaymentRepository(db)
 
@@ -194,8 +209,8 @@ func TestGetByID(t *testing.T) {
 		ctx := context.Background()
 
 		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at"}).
-				AddRow("order_id", "payment_id", 1, "abc", payment_entity.WaitingForApproval, time.Now(), time.Now()))
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}).
+				AddRow("order_id", "payment_id", 1, "abc", payment_entity.WaitingForApproval, "", "", 0, time.Now(), time.Now(), 0))
 
 		repo := NewPaymentRepository(db)
 
@@ -208,6 +223,11 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+var orderColumns = []string{
+	"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version",
+	"id", "name", "quantity", "unit_price", "discount", "total",
+}
+
 func TestGetByOrderID(t *testing.T) {
 	t.Run("Should get payments by order id", func(t *testing.T) {
 		// Arrange
@@ -224,14 +244,17 @@ func TestGetByOrderID(t *testing.T) {
 				OrderId:    "order_id",
 				PaymentId:  "payment_id",
 				TotalItems: 1,
-				Amount:     1.0,
+				Amount:     money.New(100, money.BRL),
 				State:      payment_entity.WaitingForApproval,
 				StateTitle: "WaitingForApproval",
 				Items: []payment_entity.PaymentItem{
 					{
-						Id:       "item_id",
-						Name:     "item",
-						Quantity: 1,
+						Id:        "item_id",
+						Name:      "item",
+						Quantity:  1,
+						UnitPrice: money.New(150, money.BRL),
+						Discount:  money.New(50, money.BRL),
+						Total:     money.New(100, money.BRL),
 					},
 				},
 				CreatedAt: now,
@@ -239,13 +262,10 @@ func TestGetByOrderID(t *testing.T) {
 			},
 		}
 
-		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at"}).
-				AddRow(expectedPayments[0].OrderId, expectedPayments[0].PaymentId, expectedPayments[0].TotalItems, expectedPayments[0].Amount, expectedPayments[0].State, expectedPayments[0].CreatedAt, expectedPayments[0].UpdatedAt))
-
-		mock.ExpectQuery("SELECT (.+)?payment_items(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "quantity"}).
-				AddRow(expectedPayments[0].Items[0].Id, expectedPayments[0].Items[0].Name, expectedPayments[0].Items[0].Quantity))
+		mock.ExpectQuery(`SELECT (.+) FROM "payments" AS "p" LEFT JOIN "payment_items" AS "i" (.+) WHERE \("p"."order_id" = 'order_id'\) ORDER BY (.+)`).
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow(expectedPayments[0].OrderId, expectedPayments[0].PaymentId, expectedPayments[0].TotalItems, expectedPayments[0].Amount, expectedPayments[0].State, expectedPayments[0].ChargeId, expectedPayments[0].QrCode, expectedPayments[0].RefundedAmount, expectedPayments[0].CreatedAt, expectedPayments[0].UpdatedAt, expectedPayments[0].Version,
+					expectedPayments[0].Items[0].Id, expectedPayments[0].Items[0].Name, expectedPayments[0].Items[0].Quantity, "1.50", "0.50", "1.00"))
 
 		repo := NewPaymentRepository(db)
 
@@ -255,6 +275,51 @@ func TestGetByOrderID(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedPayments, payments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should group the items of each payment", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payments\" AS \"p\" LEFT JOIN (.+)").
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow("order_id", "payment_1", 2, 10.0, payment_entity.Rejected, "", "", 0, now, now, 0, "item_1", "item 1", 1, "5.00", "0.00", "5.00").
+				AddRow("order_id", "payment_1", 2, 10.0, payment_entity.Rejected, "", "", 0, now, now, 0, "item_2", "item 2", 1, "5.00", "0.00", "5.00").
+				AddRow("order_id", "payment_2", 0, 10.0, payment_entity.Approved, "", "", 0, now, now, 0, nil, nil, nil, nil, nil, nil).
+				AddRow("order_id", "payment_3", 1, 10.0, payment_entity.WaitingForApproval, "", "", 0, now, now, 0, "item_3", "item 3", 3, "4.00", "2.00", "10.00"))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.GetByOrderID(ctx, "order_id")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, payments, 3)
+
+		assert.Equal(t, "payment_1", payments[0].PaymentId)
+		assert.Equal(t, "Rejected", payments[0].StateTitle)
+		assert.Equal(t, []payment_entity.PaymentItem{
+			{Id: "item_1", Name: "item 1", Quantity: 1, UnitPrice: money.New(500, money.BRL), Total: money.New(500, money.BRL)},
+			{Id: "item_2", Name: "item 2", Quantity: 1, UnitPrice: money.New(500, money.BRL), Total: money.New(500, money.BRL)},
+		}, payments[0].Items)
+
+		assert.Equal(t, "payment_2", payments[1].PaymentId)
+		assert.NotNil(t, payments[1].Items)
+		assert.Empty(t, payments[1].Items)
+
+		assert.Equal(t, "payment_3", payments[2].PaymentId)
+		assert.Equal(t, []payment_entity.PaymentItem{
+			{Id: "item_3", Name: "item 3", Quantity: 3, UnitPrice: money.New(400, money.BRL), Discount: money.New(200, money.BRL), Total: money.New(1000, money.BRL)},
+		}, payments[2].Items)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Should return error if an error occurs", func(t *testing.T) {
@@ -287,7 +352,7 @@ func TestGetByOrderID(t *testing.T) {
 		ctx := context.Background()
 
 		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at"}))
+			WillReturnRows(sqlmock.NewRows(orderColumns))
 
 		repo := NewPaymentRepository(db)
 
@@ -308,8 +373,8 @@ func TestGetByOrderID(t *testing.T) {
 		ctx := context.Background()
 
 		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
-			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "created_at", "updated_at"}).
-				AddRow("order_id", "payment_id", 1, "abc", payment_entity.WaitingForApproval, time.Now(), time.Now()))
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow("order_id", "payment_id", 1, "abc", payment_entity.WaitingForApproval, "", "", 0, time.Now(), time.Now(), 0, nil, nil, nil, nil, nil, nil))
 
 		repo := NewPaymentRepository(db)
 
@@ -320,6 +385,269 @@ func TestGetByOrderID(t *testing.T) {
 		assert.Error(t, err)
 		assert.Empty(t, payments)
 	})
+
+	t.Run("Should return error if reading the rows fails", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow("order_id", "payment_id", 1, 1.0, payment_entity.WaitingForApproval, "", "", 0, time.Now(), time.Now(), 0, nil, nil, nil, nil, nil, nil).
+				RowError(0, assert.AnError))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		_, err = repo.GetByOrderID(ctx, "order_id")
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+// BenchmarkGetByOrderID reports the queries issued per call, which must
+// stay at one however many payments the order has
+func BenchmarkGetByOrderID(b *testing.B) {
+	for _, count := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("payments=%d", count), func(b *testing.B) {
+			queries := 0
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+				queries++
+				return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+			})))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer db.Close()
+
+			ctx := context.Background()
+			now := time.Now()
+
+			repo := NewPaymentRepository(db)
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				rows := sqlmock.NewRows(orderColumns)
+				for p := 0; p < count; p++ {
+					paymentId := fmt.Sprintf("payment_%03d", p)
+					for item := 0; item < 3; item++ {
+						rows.AddRow("order_id", paymentId, 3, 10.0, payment_entity.Approved, "", "", 0, now, now, 0, fmt.Sprintf("item_%d", item), "item", 1, "3.00", "0.00", "3.00")
+					}
+				}
+				mock.ExpectQuery("SELECT (.+)?payments(.+)?").WillReturnRows(rows)
+				b.StartTimer()
+
+				payments, err := repo.GetByOrderID(ctx, "order_id")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(payments) != count {
+					b.Fatalf("expected %d payments, got %d", count, len(payments))
+				}
+			}
+
+			b.StopTimer()
+			if err := mock.ExpectationsWereMet(); err != nil {
+				b.Fatal(err)
+			}
+			if queries != b.N {
+				b.Fatalf("expected %d queries, got %d", b.N, queries)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("Should search the payments with every filter", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+		from := time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)
+		to := from.Add(24 * time.Hour)
+		minAmount := money.New(1050, money.BRL)
+		maxAmount := money.New(10000, money.BRL)
+
+		filter := payment_entity.SearchFilter{
+			States:      []payment_entity.PaymentState{payment_entity.WaitingForApproval, payment_entity.Approved},
+			CreatedFrom: &from,
+			CreatedTo:   &to,
+			UpdatedFrom: &from,
+			UpdatedTo:   &to,
+			MinAmount:   &minAmount,
+			MaxAmount:   &maxAmount,
+			SortBy:      payment_entity.SortByCreatedAt,
+			Limit:       20,
+		}
+
+		mock.ExpectQuery(`SELECT "order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version" FROM "payments" ` +
+			`WHERE (("state" IN (1, 2)) AND ("created_at" >= '2024-05-19T00:00:00Z') AND ("created_at" <= '2024-05-20T00:00:00Z') ` +
+			`AND ("updated_at" >= '2024-05-19T00:00:00Z') AND ("updated_at" <= '2024-05-20T00:00:00Z') AND ("amount" >= '10.50') AND ("amount" <= '100.00')) ` +
+			`ORDER BY "created_at" ASC, "payment_id" ASC LIMIT 20`).
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}).
+				AddRow("order_id", "payment_id", 1, 50.0, payment_entity.WaitingForApproval, "", "", 0, now, now, 0))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.Search(ctx, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, payments, 1)
+		assert.Equal(t, "WaitingForApproval", payments[0].StateTitle)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should compare the bounds given in another zone as the same instant", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		location := time.FixedZone("America/Sao_Paulo", -3*60*60)
+		from := time.Date(2024, 5, 19, 0, 0, 0, 0, location)
+		to := from.Add(time.Hour)
+
+		filter := payment_entity.SearchFilter{
+			CreatedFrom: &from,
+			CreatedTo:   &to,
+		}
+
+		mock.ExpectQuery(`SELECT "order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version" FROM "payments" ` +
+			`WHERE (("created_at" >= '2024-05-19T03:00:00Z') AND ("created_at" <= '2024-05-19T04:00:00Z')) ` +
+			`ORDER BY "created_at" ASC, "payment_id" ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.Search(ctx, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, payments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should continue after the keyset in descending order", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		filter := payment_entity.SearchFilter{
+			SortBy:     payment_entity.SortByAmount,
+			Descending: true,
+			After: &payment_entity.SearchKey{
+				PaymentId: "payment_id",
+				Amount:    money.New(2000, money.BRL),
+			},
+		}
+
+		mock.ExpectQuery(`SELECT "order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version" FROM "payments" ` +
+			`WHERE (("amount" < '20.00') OR (("amount" = '20.00') AND ("payment_id" < 'payment_id'))) ` +
+			`ORDER BY "amount" DESC, "payment_id" DESC`).
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.Search(ctx, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, payments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should continue after the keyset of the update date", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		filter := payment_entity.SearchFilter{
+			SortBy: payment_entity.SortByUpdatedAt,
+			After: &payment_entity.SearchKey{
+				PaymentId: "payment_id",
+				UpdatedAt: time.Date(2024, 5, 19, 2, 1, 36, 0, time.UTC),
+			},
+		}
+
+		mock.ExpectQuery(`SELECT "order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version" FROM "payments" ` +
+			`WHERE (("updated_at" > '2024-05-19T02:01:36Z') OR (("updated_at" = '2024-05-19T02:01:36Z') AND ("payment_id" > 'payment_id'))) ` +
+			`ORDER BY "updated_at" ASC, "payment_id" ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		_, err = repo.Search(ctx, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
+			WillReturnError(assert.AnError)
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.Search(ctx, payment_entity.SearchFilter{})
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, payments)
+	})
+
+	t.Run("Should return error if scan fails", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+)?payments(.+)?").
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}).
+				AddRow("order_id", "payment_id", 1, "abc", payment_entity.WaitingForApproval, "", "", 0, time.Now(), time.Now(), 0))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.Search(ctx, payment_entity.SearchFilter{})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Empty(t, payments)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -337,15 +665,20 @@ func TestUpdate(t *testing.T) {
 			OrderId:    "order_id",
 			PaymentId:  "payment_id",
 			TotalItems: 1,
-			Amount:     1.0,
+			Amount:     money.New(100, money.BRL),
 			State:      payment_entity.WaitingForApproval,
 			CreatedAt:  now,
 			UpdatedAt:  now,
+			Version:    2,
 		}
 
-		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+		mock.ExpectBegin()
+
+		mock.ExpectExec(`UPDATE "payments" SET (.+)"version"="version" \+ 1 WHERE \(\("payment_id" = 'payment_id'\) AND \("version" = 2\)\)`).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mock.ExpectCommit()
+
 		repo := NewPaymentRepository(db)
 
 		// Act
@@ -353,9 +686,11 @@ func TestUpdate(t *testing.T) {
 
 		// Assert
 		assert.NoError(t, err)
+		assert.Equal(t, 3, expectedPayment.Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Should return error if an error occurs", func(t *testing.T) {
+	t.Run("Should rollback and return conflict if the payment was changed concurrently", func(t *testing.T) {
 		// Arrange
 		db, mock, err := sqlmock.New()
 		assert.NoError(t, err)
@@ -363,15 +698,687 @@ func TestUpdate(t *testing.T) {
 
 		ctx := context.Background()
 
+		now := time.Now()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), now)
+		payment.Version = 2
+
+		err = payment.TransitionTo(payment_entity.Approved, now, payment_entity.ChangeOrigin{Source: payment_entity.SourceWebhook})
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
 		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
-			WillReturnError(assert.AnError)
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		mock.ExpectRollback()
 
 		repo := NewPaymentRepository(db)
 
 		// Act
-		err = repo.Update(ctx, &payment_entity.Payment{})
+		err = repo.Update(ctx, &payment)
 
 		// Assert
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, custom_error.ErrPaymentConcurrentUpdate)
+		assert.Equal(t, 2, payment.Version)
+		assert.Len(t, payment.StateChanges, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should update payment and write the outbox messages in the same transaction", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		message, err := outbox_entity.NewMessage("topic", map[string]string{"order_id": "order_id"}, now)
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WithArgs(message.Id, message.Topic, message.Payload, 0, "", now, now, "").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.Update(ctx, &payment_entity.Payment{PaymentId: "payment_id"}, message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should record the event in the same transaction as the update", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		event, err := inbox_entity.NewEvent("event_id", "payment_id", map[string]string{"payment_id": "payment_id"}, now)
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?inbox(.+)?").
+			WithArgs(event.Id, event.PaymentId, event.Response, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.UpdateFromEvent(ctx, &payment_entity.Payment{PaymentId: "payment_id"}, &event)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should record the state changes in the same transaction as the update", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), now)
+		err = payment.TransitionTo(payment_entity.Approved, now, payment_entity.ChangeOrigin{
+			Source:  payment_entity.SourceWebhook,
+			Payload: `{"approved":true}`,
+		})
+		assert.NoError(t, err)
+
+		change := payment.StateChanges[0]
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO payment_state_history(.+)").
+			WithArgs(change.Id, "payment_id", payment_entity.WaitingForApproval, payment_entity.Approved, payment_entity.SourceWebhook, "", `{"approved":true}`, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.Update(ctx, &payment)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, payment.StateChanges)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback and keep the state changes if the history cannot be written", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), time.Now())
+		err = payment.TransitionTo(payment_entity.Expired, time.Now(), payment_entity.ChangeOrigin{
+			Source: payment_entity.SourceExpiration,
+		})
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO payment_state_history(.+)").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.Update(ctx, &payment)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Len(t, payment.StateChanges, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback without updating when the event was already processed", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		event, err := inbox_entity.NewEvent("event_id", "payment_id", nil, time.Now())
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?inbox(.+)?").
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.UpdateFromEvent(ctx, &payment_entity.Payment{PaymentId: "payment_id"}, &event)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrEventAlreadyProcessed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if an error occurs when writing the outbox messages", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", time.Now())
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.Update(ctx, &payment_entity.Payment{PaymentId: "payment_id"}, message)
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.Update(ctx, &payment_entity.Payment{})
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return error if the transaction cannot be started", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectBegin().
+			WillReturnError(assert.AnError)
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.Update(ctx, &payment_entity.Payment{})
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestCreatePendingRefund(t *testing.T) {
+	t.Run("Should create the pending refund and move the payment version in the same transaction", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), now)
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "reason", now)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?refunds(.+)?").
+			WithArgs(refund.Id, "order_id", "payment_id", "4.00", "reason", refund_entity.RefundPending, "", now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.CreatePendingRefund(ctx, &payment, &refund)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, payment.Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if an error occurs when inserting the refund", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "", time.Now())
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?refunds(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.CreatePendingRefund(ctx, &payment_entity.Payment{}, &refund)
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if the payment changed concurrently", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "", time.Now())
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("INSERT INTO (.+)?refunds(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.CreatePendingRefund(ctx, &payment_entity.Payment{}, &refund)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentConcurrentUpdate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPendingRefund(t *testing.T) {
+	t.Run("Should get the pending refund of the payment", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"refunds\" WHERE (.+)\"payment_id\" = 'payment_id'(.+)\"state\" = 'Pending'(.+)").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_id", "amount", "reason", "state", "gateway_refund_id", "created_at"}).
+				AddRow("refund_id", "order_id", "payment_id", "4.00", "reason", "Pending", "", now))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		refund, err := repo.GetPendingRefund(ctx, "payment_id")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "refund_id", refund.Id)
+		assert.Equal(t, money.New(400, money.BRL), refund.Amount)
+		assert.True(t, refund.IsPending())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if there is no pending refund", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"refunds\"(.+)").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_id", "amount", "reason", "state", "gateway_refund_id", "created_at"}))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		_, err = repo.GetPendingRefund(ctx, "payment_id")
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrRefundNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if the query fails", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"refunds\"(.+)").
+			WillReturnError(assert.AnError)
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		_, err = repo.GetPendingRefund(ctx, "payment_id")
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestCompleteRefund(t *testing.T) {
+	t.Run("Should complete the refund and update the payment in the same transaction", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		payment := payment_entity.NewPayment("order_id", "payment_id", nil, 1, money.New(1000, money.BRL), now)
+		payment.SetCharge("charge_id", "qr_code", now)
+		assert.NoError(t, payment.TransitionTo(payment_entity.Approved, now, payment_entity.ChangeOrigin{Source: payment_entity.SourceWebhook}))
+		payment.ClearStateChanges()
+
+		err = payment.ApplyRefund(money.New(400, money.BRL), now, payment_entity.ChangeOrigin{
+			Source:  payment_entity.SourceAdmin,
+			ActorId: "user_id",
+		})
+		assert.NoError(t, err)
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "reason", now)
+		refund.Complete("gateway_refund_id")
+
+		message, err := outbox_entity.NewMessage("topic", "payload", now)
+		assert.NoError(t, err)
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE \"refunds\" SET \"gateway_refund_id\"='gateway_refund_id',\"state\"='Completed' WHERE (.+)\"state\" = 'Pending'(.+)").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO payment_state_history(.+)").
+			WithArgs(sqlmock.AnyArg(), "payment_id", payment_entity.Approved, payment_entity.PartiallyRefunded, payment_entity.SourceAdmin, "user_id", "", now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("INSERT INTO (.+)?outbox(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.CompleteRefund(ctx, &payment, &refund, message)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if the refund was already settled", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "", time.Now())
+		refund.Complete("gateway_refund_id")
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?refunds(.+)?").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.CompleteRefund(ctx, &payment_entity.Payment{}, &refund)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrRefundNotPending)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if an error occurs when updating the payment", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "", time.Now())
+		refund.Complete("gateway_refund_id")
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?refunds(.+)?").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectExec("UPDATE (.+)?payments(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.CompleteRefund(ctx, &payment_entity.Payment{}, &refund)
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFailRefund(t *testing.T) {
+	t.Run("Should fail the pending refund", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "", time.Now())
+		refund.Fail()
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE \"refunds\" SET (.+)\"state\"='Failed' WHERE (.+)").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.FailRefund(ctx, &refund)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should rollback if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		refund := refund_entity.NewRefund("order_id", "payment_id", money.New(400, money.BRL), "", time.Now())
+		refund.Fail()
+
+		mock.ExpectBegin()
+
+		mock.ExpectExec("UPDATE (.+)?refunds(.+)?").
+			WillReturnError(assert.AnError)
+
+		mock.ExpectRollback()
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		err = repo.FailRefund(ctx, &refund)
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetOverdue(t *testing.T) {
+	t.Run("Should get the overdue payments", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payments\" WHERE (.+)\"state\" = 1(.+)\"created_at\" <= (.+) ORDER BY \"created_at\" ASC LIMIT 10").
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}).
+				AddRow("order_id", "payment_id", 1, 10.0, payment_entity.WaitingForApproval, "", "", 0, now, now, 0))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.GetOverdue(ctx, now, 10)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, payments, 1)
+		assert.Equal(t, "payment_id", payments[0].PaymentId)
+		assert.Equal(t, "WaitingForApproval", payments[0].StateTitle)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should return error if an error occurs", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payments\"(.+)").
+			WillReturnError(assert.AnError)
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.GetOverdue(ctx, time.Now(), 10)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Empty(t, payments)
+	})
+
+	t.Run("Should return error if scan fails", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		ctx := context.Background()
+
+		mock.ExpectQuery("SELECT (.+) FROM \"payments\"(.+)").
+			WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_id", "total_items", "amount", "state", "charge_id", "qr_code", "refunded_amount", "created_at", "updated_at", "version"}).
+				AddRow("order_id", "payment_id", 1, "abc", payment_entity.WaitingForApproval, "", "", 0, time.Now(), time.Now(), 0))
+
+		repo := NewPaymentRepository(db)
+
+		// Act
+		payments, err := repo.GetOverdue(ctx, time.Now(), 10)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Empty(t, payments)
 	})
 }
@@ -2,13 +2,40 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
 )
 
 type PaymentRepository interface {
 	Create(ctx context.Context, payment *payment_entity.Payment) error
 	GetByID(ctx context.Context, paymentId string) (payment_entity.Payment, error)
 	GetByOrderID(ctx context.Context, orderId string) ([]payment_entity.Payment, error)
-	Update(ctx context.Context, payment *payment_entity.Payment) error
+	GetOverdue(ctx context.Context, createdBefore time.Time, limit int) ([]payment_entity.Payment, error)
+	Search(ctx context.Context, filter payment_entity.SearchFilter) ([]payment_entity.Payment, error)
+	Update(ctx context.Context, payment *payment_entity.Payment, messages ...outbox_entity.Message) error
+	UpdateFromEvent(ctx context.Context, payment *payment_entity.Payment, event *inbox_entity.Event, messages ...outbox_entity.Message) error
+	CreatePendingRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund) error
+	GetPendingRefund(ctx context.Context, paymentId string) (refund_entity.Refund, error)
+	CompleteRefund(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund, messages ...outbox_entity.Message) error
+	FailRefund(ctx context.Context, refund *refund_entity.Refund) error
+}
+
+type HistoryRepository interface {
+	GetByPaymentID(ctx context.Context, paymentId string) ([]payment_entity.StateChange, error)
+}
+
+type InboxRepository interface {
+	GetByEventID(ctx context.Context, eventId string) (inbox_entity.Event, error)
+	Create(ctx context.Context, event *inbox_entity.Event, messages ...outbox_entity.Message) error
+}
+
+type OutboxRepository interface {
+	Create(ctx context.Context, messages ...outbox_entity.Message) error
+	ClaimPending(ctx context.Context, now time.Time, lockUntil time.Time, limit int) ([]outbox_entity.Message, error)
+	MarkAsSent(ctx context.Context, message *outbox_entity.Message) error
+	MarkAsFailed(ctx context.Context, message *outbox_entity.Message) error
 }
@@ -8,6 +8,9 @@ import (
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/create"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_id"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_order_id"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/refund"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/search"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/update"
 )
 
@@ -15,13 +18,19 @@ type Dependency struct {
 	TimeProvider *time_provider.TimeProvider
 
 	PaymentRepository repository.PaymentRepository
+	OutboxRepository  repository.OutboxRepository
+	InboxRepository   repository.InboxRepository
+	HistoryRepository repository.HistoryRepository
 
 	CreatePaymentService service.CreatePaymentService[create.CreatePaymentDTO]
 	UpdatePaymentService service.UpdatePaymentService[update.UpdatePaymentDTO]
+	RefundPaymentService service.RefundPaymentService[refund.RefundPaymentDTO]
 
 	UpdateOrderTopicService     cloud.TopicService
 	OrderProductionTopicService cloud.TopicService
 
 	GetPaymentByOrderIdService service.GetPaymentsByOrderIDService[get_by_order_id.GetByOrderIdDTO]
 	GetPaymentByIDService      service.GetPaymentByIDService[get_by_id.GetByIdDTO]
+	GetPaymentHistoryService   service.GetPaymentHistoryService[get_history.GetHistoryDTO]
+	SearchPaymentsService      service.SearchPaymentsService[search.SearchPaymentsDTO]
 }
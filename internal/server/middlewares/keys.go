@@ -0,0 +1,314 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
+)
+
+var (
+	ErrKeyNotFound       = errors.New("signing key not found")
+	ErrKeysNotConfigured = errors.New("either the jwks url or the public keys must be configured")
+)
+
+// KeyProvider returns the public keys able to verify a token signed with the given key id,
+// an empty key id returns every known key
+type KeyProvider interface {
+	GetKeys(ctx context.Context, kid string) ([]crypto.PublicKey, error)
+}
+
+func NewKeyProvider(config *environment.AuthConfig, timeProvider provider.TimeProvider) (KeyProvider, error) {
+	if config.JwksUrl != "" {
+		return NewJwksKeyProvider(config, timeProvider), nil
+	}
+
+	if config.PublicKeys != "" {
+		return NewStaticKeyProvider(config.PublicKeys)
+	}
+
+	return nil, ErrKeysNotConfigured
+}
+
+type StaticKeyProvider struct {
+	keys []crypto.PublicKey
+}
+
+// NewStaticKeyProvider parses one or more PEM encoded public keys
+func NewStaticKeyProvider(publicKeys string) (*StaticKeyProvider, error) {
+	var keys []crypto.PublicKey
+
+	rest := []byte(publicKeys)
+
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing public key: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no public key found in the pem data")
+	}
+
+	return &StaticKeyProvider{
+		keys: keys,
+	}, nil
+}
+
+func (p *StaticKeyProvider) GetKeys(ctx context.Context, kid string) ([]crypto.PublicKey, error) {
+	return p.keys, nil
+}
+
+type JwksKeyProvider struct {
+	url                string
+	cacheTtl           time.Duration
+	minRefreshInterval time.Duration
+	client             *http.Client
+	timeProvider       provider.TimeProvider
+
+	mutex       sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+// NewJwksKeyProvider fetches the keys lazily, caching them for the configured ttl,
+// a token signed with an unknown key id refreshes the keys to pick up rotated ones
+func NewJwksKeyProvider(config *environment.AuthConfig, timeProvider provider.TimeProvider) *JwksKeyProvider {
+	return &JwksKeyProvider{
+		url:                config.JwksUrl,
+		cacheTtl:           config.JwksCacheTtl,
+		minRefreshInterval: config.JwksMinRefreshInterval,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		timeProvider: timeProvider,
+	}
+}
+
+func (p *JwksKeyProvider) GetKeys(ctx context.Context, kid string) ([]crypto.PublicKey, error) {
+	keys, refreshedAt := p.cached()
+
+	now := p.timeProvider.GetTime()
+
+	expired := now.Sub(refreshedAt) >= p.cacheTtl
+	missing := !hasKey(keys, kid) && now.Sub(refreshedAt) >= p.minRefreshInterval
+
+	if expired || missing {
+		refreshed, err := p.refresh(ctx, refreshedAt)
+		if err != nil {
+			// keep serving the cached keys while the jwks endpoint is unavailable
+			slog.ErrorContext(ctx, "error refreshing jwks", "url", p.url, "error", err)
+		} else {
+			keys = refreshed
+		}
+	}
+
+	return selectKeys(keys, kid)
+}
+
+func (p *JwksKeyProvider) cached() (map[string]crypto.PublicKey, time.Time) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.keys, p.refreshedAt
+}
+
+func (p *JwksKeyProvider) refresh(ctx context.Context, seenRefreshedAt time.Time) (map[string]crypto.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// another request refreshed the keys while this one was waiting for the lock
+	if !p.refreshedAt.Equal(seenRefreshedAt) {
+		return p.keys, nil
+	}
+
+	keys, err := p.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = keys
+	p.refreshedAt = p.timeProvider.GetTime()
+
+	return keys, nil
+}
+
+func (p *JwksKeyProvider) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks status code %d", res.StatusCode)
+	}
+
+	var jwks jsonWebKeySet
+
+	if err := json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("error decoding jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			slog.WarnContext(ctx, "ignoring invalid jwk", "kid", jwk.Kid, "error", err)
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func hasKey(keys map[string]crypto.PublicKey, kid string) bool {
+	if kid == "" {
+		return len(keys) > 0
+	}
+
+	_, ok := keys[kid]
+	return ok
+}
+
+func selectKeys(keys map[string]crypto.PublicKey, kid string) ([]crypto.PublicKey, error) {
+	if kid != "" {
+		key, ok := keys[kid]
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+
+		return []crypto.PublicKey{key}, nil
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	selected := make([]crypto.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		selected = append(selected, key)
+	}
+
+	return selected, nil
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+
+	// rsa
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// ecdsa
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: n,
+			E: int(e.Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     x,
+			Y:     y,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,289 @@
+package token_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider/time_provider"
+	token "github.com/jfelipearaujo-org/ms-payment-management/internal/server/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+type jwksServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	keys     []map[string]string
+	requests int
+}
+
+func newJwksServer(t *testing.T, keys ...map[string]string) *jwksServer {
+	s := &jwksServer{
+		keys: keys,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.requests++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": s.keys,
+		})
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *jwksServer) SetKeys(keys ...map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys = keys
+}
+
+func (s *jwksServer) Requests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests
+}
+
+func jwk(t *testing.T, kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func encodePublicKey(t *testing.T, key interface{}) string {
+	data, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: data,
+	}))
+}
+
+type clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestNewKeyProvider(t *testing.T) {
+	t.Run("Should return error when no key source is configured", func(t *testing.T) {
+		// Arrange
+		config := &environment.AuthConfig{}
+
+		// Act
+		keys, err := token.NewKeyProvider(config, time_provider.NewTimeProvider(time.Now))
+
+		// Assert
+		assert.ErrorIs(t, err, token.ErrKeysNotConfigured)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("Should return error when the public keys are not valid", func(t *testing.T) {
+		// Arrange
+		config := &environment.AuthConfig{
+			PublicKeys: "not a pem",
+		}
+
+		// Act
+		keys, err := token.NewKeyProvider(config, time_provider.NewTimeProvider(time.Now))
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, keys)
+	})
+}
+
+func TestStaticKeyProvider(t *testing.T) {
+	t.Run("Should return every configured key", func(t *testing.T) {
+		// Arrange
+		rsaKey := generateKey(t)
+
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+
+		provider, err := token.NewStaticKeyProvider(encodePublicKey(t, &rsaKey.PublicKey) + encodePublicKey(t, &ecKey.PublicKey))
+		assert.NoError(t, err)
+
+		// Act
+		keys, err := provider.GetKeys(context.Background(), "any")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, keys, 2)
+		assert.True(t, rsaKey.PublicKey.Equal(keys[0]))
+		assert.True(t, ecKey.PublicKey.Equal(keys[1]))
+	})
+}
+
+func TestJwksKeyProvider(t *testing.T) {
+	t.Run("Should cache the keys until the ttl expires", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		now := &clock{now: time.Now()}
+
+		key := generateKey(t)
+		server := newJwksServer(t, jwk(t, "key-1", &key.PublicKey))
+
+		provider := token.NewJwksKeyProvider(newAuthConfig(server.URL), time_provider.NewTimeProvider(now.Now))
+
+		// Act
+		_, err := provider.GetKeys(ctx, "key-1")
+		assert.NoError(t, err)
+
+		now.Advance(5 * time.Minute)
+
+		keys, err := provider.GetKeys(ctx, "key-1")
+		assert.NoError(t, err)
+
+		requestsBeforeTtl := server.Requests()
+
+		now.Advance(5 * time.Minute)
+
+		_, err = provider.GetKeys(ctx, "key-1")
+		assert.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 1, requestsBeforeTtl)
+		assert.Equal(t, 2, server.Requests())
+		assert.Len(t, keys, 1)
+		assert.True(t, key.PublicKey.Equal(keys[0]))
+	})
+
+	t.Run("Should refresh the keys when an unknown key id is rotated in", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		now := &clock{now: time.Now()}
+
+		oldKey := generateKey(t)
+		newKey := generateKey(t)
+		server := newJwksServer(t, jwk(t, "key-1", &oldKey.PublicKey))
+
+		provider := token.NewJwksKeyProvider(newAuthConfig(server.URL), time_provider.NewTimeProvider(now.Now))
+
+		_, err := provider.GetKeys(ctx, "key-1")
+		assert.NoError(t, err)
+
+		server.SetKeys(jwk(t, "key-1", &oldKey.PublicKey), jwk(t, "key-2", &newKey.PublicKey))
+		now.Advance(time.Minute)
+
+		// Act
+		keys, err := provider.GetKeys(ctx, "key-2")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.True(t, newKey.PublicKey.Equal(keys[0]))
+		assert.Equal(t, 2, server.Requests())
+	})
+
+	t.Run("Should not refresh the keys for unknown key ids more than once per interval", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		now := &clock{now: time.Now()}
+
+		key := generateKey(t)
+		server := newJwksServer(t, jwk(t, "key-1", &key.PublicKey))
+
+		provider := token.NewJwksKeyProvider(newAuthConfig(server.URL), time_provider.NewTimeProvider(now.Now))
+
+		_, err := provider.GetKeys(ctx, "key-1")
+		assert.NoError(t, err)
+
+		// Act
+		for i := 0; i < 5; i++ {
+			_, err = provider.GetKeys(ctx, "unknown")
+		}
+
+		// Assert
+		assert.ErrorIs(t, err, token.ErrKeyNotFound)
+		assert.Equal(t, 1, server.Requests())
+	})
+
+	t.Run("Should keep the cached keys when the refresh fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		now := &clock{now: time.Now()}
+
+		key := generateKey(t)
+		server := newJwksServer(t, jwk(t, "key-1", &key.PublicKey))
+
+		provider := token.NewJwksKeyProvider(newAuthConfig(server.URL), time_provider.NewTimeProvider(now.Now))
+
+		_, err := provider.GetKeys(ctx, "key-1")
+		assert.NoError(t, err)
+
+		server.Close()
+		now.Advance(time.Hour)
+
+		// Act
+		keys, err := provider.GetKeys(ctx, "key-1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+	})
+
+	t.Run("Should ignore keys that are not valid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		key := generateKey(t)
+		server := newJwksServer(t,
+			jwk(t, "key-1", &key.PublicKey),
+			map[string]string{"kid": "key-2", "kty": "oct", "k": "c2VjcmV0"},
+			map[string]string{"kid": "key-3", "kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		)
+
+		provider := token.NewJwksKeyProvider(newAuthConfig(server.URL), time_provider.NewTimeProvider(time.Now))
+
+		// Act
+		keys, err := provider.GetKeys(ctx, "")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.True(t, key.PublicKey.Equal(keys[0]))
+	})
+}
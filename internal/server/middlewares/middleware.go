@@ -1,40 +1,78 @@
 package token
 
 import (
+	"crypto"
+	"log/slog"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/labstack/echo/v4"
 )
 
-func Middleware() echo.MiddlewareFunc {
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(),
+	jwt.SigningMethodPS384.Alg(),
+	jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+func Middleware(keys KeyProvider, config *environment.AuthConfig) echo.MiddlewareFunc {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(config.Leeway),
+	}
+
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	parser := jwt.NewParser(options...)
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			tokenHeader := c.Request().Header.Get("Authorization")
 			if tokenHeader == "" {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
 				return echo.NewHTTPError(http.StatusUnauthorized, "Token is required")
 			}
 
-			tokenValue := tokenHeader[7:]
-			token, _, err := new(jwt.Parser).ParseUnverified(tokenValue, jwt.MapClaims{})
-			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
+			scheme, tokenValue, found := strings.Cut(tokenHeader, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || tokenValue == "" {
+				return unauthorized(c, "Invalid token")
 			}
 
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
-			}
+			ctx := c.Request().Context()
+
+			token, err := parser.ParseWithClaims(tokenValue, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+				kid, _ := token.Header["kid"].(string)
 
-			expiration := claims["exp"].(float64)
-			if expiration < float64(time.Now().Unix()) {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
+				publicKeys, err := keys.GetKeys(ctx, kid)
+				if err != nil {
+					return nil, err
+				}
+
+				return verificationKeys(publicKeys), nil
+			})
+			if err != nil {
+				slog.WarnContext(ctx, "invalid token", "error", err)
+				return unauthorized(c, "Invalid token")
 			}
 
-			userId, ok := claims["sub"].(string)
-			if !ok {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
+			userId, err := token.Claims.GetSubject()
+			if err != nil || userId == "" {
+				return unauthorized(c, "Invalid token")
 			}
 
 			c.Set("userId", userId)
@@ -43,3 +81,24 @@ func Middleware() echo.MiddlewareFunc {
 		}
 	}
 }
+
+func verificationKeys(publicKeys []crypto.PublicKey) interface{} {
+	if len(publicKeys) == 1 {
+		return publicKeys[0]
+	}
+
+	keySet := jwt.VerificationKeySet{
+		Keys: make([]jwt.VerificationKey, 0, len(publicKeys)),
+	}
+
+	for _, key := range publicKeys {
+		keySet.Keys = append(keySet.Keys, key)
+	}
+
+	return keySet
+}
+
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	return echo.NewHTTPError(http.StatusUnauthorized, message)
+}
@@ -1,6 +1,8 @@
 package token_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,49 +11,89 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider/time_provider"
 	token "github.com/jfelipearaujo-org/ms-payment-management/internal/server/middlewares"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
-func generateToken(t *testing.T, userId string, expire time.Duration) string {
-	claims := jwt.MapClaims{
-		"sub": userId,
-		"exp": time.Now().Add(expire).Unix(),
+func newAuthConfig(jwksUrl string) *environment.AuthConfig {
+	return &environment.AuthConfig{
+		JwksUrl:                jwksUrl,
+		JwksCacheTtl:           10 * time.Minute,
+		JwksMinRefreshInterval: time.Minute,
+		Issuer:                 "https://auth.example.com",
+		Audience:               "ms-payment-management",
 	}
+}
 
-	if userId == "no-claims" {
-		claims = jwt.MapClaims{
-			"exp": time.Now().Add(expire).Unix(),
-		}
+func newClaims(userId string, expire time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": userId,
+		"iss": "https://auth.example.com",
+		"aud": "ms-payment-management",
+		"exp": time.Now().Add(expire).Unix(),
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+func generateToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
 
-	tokenString, err := token.SignedString([]byte("my-secret"))
+	tokenString, err := token.SignedString(key)
 	assert.NoError(t, err)
 
 	return fmt.Sprintf("Bearer %s", tokenString)
 }
 
+func serve(t *testing.T, config *environment.AuthConfig, authorization string) *httptest.ResponseRecorder {
+	keys, err := token.NewKeyProvider(config, time_provider.NewTimeProvider(time.Now))
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(echo.GET, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	res := httptest.NewRecorder()
+
+	e := echo.New()
+	e.Use(token.Middleware(keys, config))
+	e.GET("/", func(c echo.Context) error {
+		userId := c.Get("userId").(string)
+		return c.String(http.StatusOK, userId)
+	})
+
+	e.ServeHTTP(res, req)
+
+	return res
+}
+
 func TestMiddleware(t *testing.T) {
+	key := generateKey(t)
+	jwks := newJwksServer(t, jwk(t, "key-1", &key.PublicKey))
+
 	t.Run("Should authorize when token is valid", func(t *testing.T) {
 		// Arrange
 		userId := uuid.NewString()
 
-		req := httptest.NewRequest(echo.GET, "/", nil)
-		req.Header.Set("Authorization", generateToken(t, userId, time.Minute*1))
-		res := httptest.NewRecorder()
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-1", newClaims(userId, time.Minute)))
 
-		e := echo.New()
-		e.Use(token.Middleware())
-		e.GET("/", func(c echo.Context) error {
-			userId := c.Get("userId").(string)
-			return c.String(http.StatusOK, userId)
-		})
+		// Assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, userId, res.Body.String())
+	})
+
+	t.Run("Should authorize when token is verified with a static public key", func(t *testing.T) {
+		// Arrange
+		userId := uuid.NewString()
+
+		config := newAuthConfig("")
+		config.PublicKeys = encodePublicKey(t, &key.PublicKey)
 
 		// Act
-		e.ServeHTTP(res, req)
+		res := serve(t, config, generateToken(t, key, "", newClaims(userId, time.Minute)))
 
 		// Assert
 		assert.Equal(t, http.StatusOK, res.Code)
@@ -59,83 +101,136 @@ func TestMiddleware(t *testing.T) {
 	})
 
 	t.Run("Should not authorize when token is invalid", func(t *testing.T) {
-		// Arrange
-		req := httptest.NewRequest(echo.GET, "/", nil)
-		req.Header.Set("Authorization", "invalid-token")
-		res := httptest.NewRecorder()
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), "invalid-token")
 
-		e := echo.New()
-		e.Use(token.Middleware())
-		e.GET("/", func(c echo.Context) error {
-			userId := c.Get("userId").(string)
-			return c.String(http.StatusOK, userId)
-		})
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, `Bearer error="invalid_token"`, res.Header().Get(echo.HeaderWWWAuthenticate))
+	})
 
+	t.Run("Should not authorize when the header is too short", func(t *testing.T) {
 		// Act
-		e.ServeHTTP(res, req)
+		res := serve(t, newAuthConfig(jwks.URL), "abc")
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, res.Code)
 	})
 
 	t.Run("Should not authorize when token is missing", func(t *testing.T) {
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), "")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, "Bearer", res.Header().Get(echo.HeaderWWWAuthenticate))
+	})
+
+	t.Run("Should not authorize when token is expired", func(t *testing.T) {
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-1", newClaims(uuid.NewString(), -time.Minute)))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize when token does not have expiration", func(t *testing.T) {
 		// Arrange
-		req := httptest.NewRequest(echo.GET, "/", nil)
-		res := httptest.NewRecorder()
+		claims := newClaims(uuid.NewString(), time.Minute)
+		delete(claims, "exp")
 
-		e := echo.New()
-		e.Use(token.Middleware())
-		e.GET("/", func(c echo.Context) error {
-			userId := c.Get("userId").(string)
-			return c.String(http.StatusOK, userId)
-		})
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-1", claims))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize when token doest not have user id", func(t *testing.T) {
+		// Arrange
+		claims := newClaims(uuid.NewString(), time.Minute)
+		delete(claims, "sub")
 
 		// Act
-		e.ServeHTTP(res, req)
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-1", claims))
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, res.Code)
 	})
 
-	t.Run("Should not authorize when token is expired", func(t *testing.T) {
+	t.Run("Should not authorize when token is signed by an unknown key", func(t *testing.T) {
 		// Arrange
-		userId := uuid.NewString()
+		otherKey := generateKey(t)
 
-		req := httptest.NewRequest(echo.GET, "/", nil)
-		req.Header.Set("Authorization", generateToken(t, userId, time.Second*-1))
-		res := httptest.NewRecorder()
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, otherKey, "key-1", newClaims(uuid.NewString(), time.Minute)))
 
-		e := echo.New()
-		e.Use(token.Middleware())
-		e.GET("/", func(c echo.Context) error {
-			userId := c.Get("userId").(string)
-			return c.String(http.StatusOK, userId)
-		})
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
 
+	t.Run("Should not authorize when token has an unknown key id", func(t *testing.T) {
 		// Act
-		e.ServeHTTP(res, req)
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-2", newClaims(uuid.NewString(), time.Minute)))
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, res.Code)
 	})
 
-	t.Run("Should not authorize when token doest not have user id", func(t *testing.T) {
+	t.Run("Should not authorize when token is signed with a symmetric algorithm", func(t *testing.T) {
 		// Arrange
-		req := httptest.NewRequest(echo.GET, "/", nil)
-		req.Header.Set("Authorization", generateToken(t, "no-claims", time.Minute*1))
-		res := httptest.NewRecorder()
+		hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(uuid.NewString(), time.Minute))
 
-		e := echo.New()
-		e.Use(token.Middleware())
-		e.GET("/", func(c echo.Context) error {
-			userId := c.Get("userId").(string)
-			return c.String(http.StatusOK, userId)
-		})
+		tokenString, err := hmacToken.SignedString([]byte("my-secret"))
+		assert.NoError(t, err)
 
 		// Act
-		e.ServeHTTP(res, req)
+		res := serve(t, newAuthConfig(jwks.URL), fmt.Sprintf("Bearer %s", tokenString))
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, res.Code)
 	})
+
+	t.Run("Should not authorize when the issuer does not match", func(t *testing.T) {
+		// Arrange
+		claims := newClaims(uuid.NewString(), time.Minute)
+		claims["iss"] = "https://other.example.com"
+
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-1", claims))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize when the audience does not match", func(t *testing.T) {
+		// Arrange
+		claims := newClaims(uuid.NewString(), time.Minute)
+		claims["aud"] = "other-service"
+
+		// Act
+		res := serve(t, newAuthConfig(jwks.URL), generateToken(t, key, "key-1", claims))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize when the jwks is unavailable", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		// Act
+		res := serve(t, newAuthConfig(server.URL), generateToken(t, key, "key-1", newClaims(uuid.NewString(), time.Minute)))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return key
 }
@@ -0,0 +1,64 @@
+package signature
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
+	"github.com/labstack/echo/v4"
+)
+
+const maxBodySize = 1 << 20
+
+// Middleware authenticates the notifications sent by the payment gateway, which are signed
+// with the shared webhook secret instead of a user token, along with the path and query
+// they were sent to, so a notification cannot be replayed against another payment
+func Middleware(config *environment.GatewayConfig, timeProvider provider.TimeProvider) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			header := c.Request().Header.Get(payment_gateway.WebhookSignatureHeader)
+			if header == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Signature is required")
+			}
+
+			body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxBodySize+1))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid body")
+			}
+
+			if len(body) > maxBodySize {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Body too large")
+			}
+
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			request := payment_gateway.WebhookRequest{
+				Method: c.Request().Method,
+				Target: publicTarget(config.WebhookBasePath, c.Request().URL),
+				Body:   body,
+			}
+
+			err = payment_gateway.VerifyWebhook(config.WebhookSecret, header, request, timeProvider.GetTime(), config.WebhookTolerance)
+			if err != nil {
+				slog.WarnContext(ctx, "invalid webhook signature", "path", c.Request().URL.Path, "error", err)
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid signature")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// publicTarget rebuilds the path and query the gateway sent the webhook to,
+// before the ingress stripped the base path from it
+func publicTarget(basePath string, url *url.URL) string {
+	return strings.TrimSuffix(basePath, "/") + url.RequestURI()
+}
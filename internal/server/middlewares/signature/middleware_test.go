@@ -0,0 +1,175 @@
+package signature_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider/time_provider"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/server/middlewares/signature"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T, now time.Time, target string, body []byte, header string) *httptest.ResponseRecorder {
+	return serveBehindIngress(t, "", now, target, body, header)
+}
+
+// serveBehindIngress serves the target as it reaches the service once the ingress stripped the base path
+func serveBehindIngress(t *testing.T, basePath string, now time.Time, target string, body []byte, header string) *httptest.ResponseRecorder {
+	config := &environment.GatewayConfig{
+		WebhookSecret:    "webhook-secret",
+		WebhookTolerance: 5 * time.Minute,
+		WebhookBasePath:  basePath,
+	}
+
+	timeProvider := time_provider.NewTimeProvider(func() time.Time {
+		return now
+	})
+
+	req := httptest.NewRequest(echo.PATCH, target, bytes.NewReader(body))
+	if header != "" {
+		req.Header.Set(payment_gateway.WebhookSignatureHeader, header)
+	}
+	res := httptest.NewRecorder()
+
+	e := echo.New()
+	e.Use(signature.Middleware(config, timeProvider))
+	e.PATCH("/payments/webhook/:payment_id", func(c echo.Context) error {
+		received, err := io.ReadAll(c.Request().Body)
+		assert.NoError(t, err)
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, received)
+	})
+
+	e.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Now()
+	target := "/payments/webhook/payment-id"
+	body := []byte(`{"approved":true}`)
+
+	sign := func(secret string, target string, body []byte) string {
+		return payment_gateway.SignWebhook(secret, now, payment_gateway.WebhookRequest{
+			Method: echo.PATCH,
+			Target: target,
+			Body:   body,
+		})
+	}
+
+	t.Run("Should authorize when the signature is valid", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", target, body)
+
+		// Act
+		res := serve(t, now, target, body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, string(body), res.Body.String())
+	})
+
+	t.Run("Should not authorize when the signature is missing", func(t *testing.T) {
+		// Act
+		res := serve(t, now, target, body, "")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize when the signature was made with another secret", func(t *testing.T) {
+		// Arrange
+		header := sign("other-secret", target, body)
+
+		// Act
+		res := serve(t, now, target, body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize a signature replayed on another payment", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", target, body)
+
+		// Act
+		res := serve(t, now, "/payments/webhook/other-payment-id", body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize a signature replayed with another query", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", target, body)
+
+		// Act
+		res := serve(t, now, target+"?resend=true", body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should authorize a signature made for the query sent", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", target+"?resend=true", body)
+
+		// Act
+		res := serve(t, now, target+"?resend=true", body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("Should authorize a signature made for the public path the ingress rewrote", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", "/payments-mgmt"+target+"?resend=true", body)
+
+		// Act
+		res := serveBehindIngress(t, "/payments-mgmt/", now, target+"?resend=true", body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("Should not authorize a signature made for the path without the base path", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", target, body)
+
+		// Act
+		res := serveBehindIngress(t, "/payments-mgmt", now, target, body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should not authorize when the notification is replayed after the tolerance", func(t *testing.T) {
+		// Arrange
+		header := sign("webhook-secret", target, body)
+
+		// Act
+		res := serve(t, now.Add(10*time.Minute), target, body, header)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Should reject a body that is too large", func(t *testing.T) {
+		// Arrange
+		large := bytes.Repeat([]byte("a"), 2<<20)
+		header := sign("webhook-secret", target, large)
+
+		// Act
+		res := serve(t, now, target, large, header)
+
+		// Assert
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	})
+}
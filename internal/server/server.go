@@ -10,32 +10,71 @@ import (
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/database"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/metrics"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/tracing"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	enqueue_message_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/enqueue_message"
+	get_by_id_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/get_by_id"
 	get_by_order_id_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/get_by_order_id"
+	get_history_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/get_history"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/handler/health"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/handler/payment_hook"
+	published_messages_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/published_messages"
+	refund_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/refund"
+	search_handler "github.com/jfelipearaujo-org/ms-payment-management/internal/handler/search"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider/time_provider"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/inbox"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/outbox"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/payment"
 	token "github.com/jfelipearaujo-org/ms-payment-management/internal/server/middlewares"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/server/middlewares/signature"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/create"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/gateway"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_id"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_by_order_id"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/get_history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/refund"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/search"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/service/payment/update"
+	shared_health "github.com/jfelipearaujo-org/ms-payment-management/internal/shared/health"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/logger"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/worker"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/worker/outbox_relay"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/worker/payment_expiration"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
+// MemoryCloud holds the in-memory queue and topics, so they can be inspected in development
+type MemoryCloud struct {
+	Queue                *cloud.MemoryQueueService
+	UpdateOrderTopic     *cloud.MemoryTopicService
+	OrderProductionTopic *cloud.MemoryTopicService
+}
+
 type Server struct {
 	Config          *environment.Config
 	DatabaseService database.DatabaseService
 	QueueService    cloud.QueueService
+	KeyProvider     token.KeyProvider
+	Metrics         *metrics.Metrics
+	TracerProvider  *sdktrace.TracerProvider
+	HealthChecker   *shared_health.Checker
+
+	// MemoryCloud is only set when the in-memory cloud driver is selected
+	MemoryCloud *MemoryCloud
 
 	UpdateOrderTopicService     cloud.TopicService
 	OrderProductionTopicService cloud.TopicService
 
+	OutboxRelay       worker.Worker
+	PaymentExpiration worker.Worker
+
 	Dependency Dependency
 }
 
@@ -51,42 +90,147 @@ func NewServer(config *environment.Config) *Server {
 		cloudConfig.BaseEndpoint = aws.String(config.CloudConfig.BaseEndpoint)
 	}
 
+	// the provider is set as the global one before the instrumented clients are built
+	tracerProvider, err := tracing.NewTracerProvider(ctx, config.TracingConfig)
+	if err != nil {
+		panic(err)
+	}
+
 	databaseService := database.NewDatabase(config)
 
 	timeProvider := time_provider.NewTimeProvider(time.Now)
-	paymentRepository := payment.NewPaymentRepository(databaseService.GetInstance())
+
+	keyProvider, err := token.NewKeyProvider(config.AuthConfig, timeProvider)
+	if err != nil {
+		panic(err)
+	}
+
+	metricsService := metrics.NewMetrics()
+	metricsService.RegisterDatabase(databaseService.GetInstance(), "payment_db")
+
+	paymentRepository := metrics.NewPaymentRepository(payment.NewPaymentRepository(databaseService.GetInstance()), metricsService)
+	outboxRepository := outbox.NewOutboxRepository(databaseService.GetInstance())
+	inboxRepository := inbox.NewInboxRepository(databaseService.GetInstance())
+	historyRepository := history.NewHistoryRepository(databaseService.GetInstance())
 	createPaymentService := create.NewService(paymentRepository, timeProvider)
-	createPaymentGatewayService := gateway.NewService()
+	paymentGatewayService := payment_gateway.NewHttpGatewayService(config.GatewayConfig)
+	createPaymentGatewayService := gateway.NewService(paymentRepository, paymentGatewayService, timeProvider)
 
-	updateOrderTopicService := cloud.NewUpdateOrderTopicService(config.CloudConfig.UpdateOrderTopic, cloudConfig)
-	orderProductionTopicService := cloud.NewOrderProductionTopicService(config.CloudConfig.OrderProductionTopic, cloudConfig)
+	var queue cloud.QueueService
+	var updateOrderTopic, orderProductionTopic cloud.TopicService
+	var memoryCloud *MemoryCloud
 
-	return &Server{
-		Config:          config,
-		DatabaseService: databaseService,
-		QueueService: cloud.NewQueueService(
+	healthComponents := []shared_health.Component{
+		{Name: "database", Check: databaseService},
+	}
+
+	switch config.CloudConfig.Driver {
+	case environment.CloudDriverAws:
+		queue = cloud.NewQueueService(
 			config.CloudConfig.OrderPaymentQueue,
+			config.CloudConfig.OrderPaymentDlq,
+			config.QueueConfig,
 			cloudConfig,
 			createPaymentService,
 			createPaymentGatewayService,
-		),
+		)
+		updateOrderTopic = cloud.NewTopicPublisher[cloud.UpdateOrderTopicContract](config.CloudConfig.UpdateOrderTopic, cloudConfig)
+		orderProductionTopic = cloud.NewTopicPublisher[cloud.OrderProductionTopicContract](config.CloudConfig.OrderProductionTopic, cloudConfig)
+
+		healthComponents = append(healthComponents, shared_health.Component{
+			Name:  "secrets",
+			Check: cloud.NewSecretHealthCheck(config.DbConfig.UrlSecretName, cloudConfig),
+		})
+	case environment.CloudDriverMemory:
+		memoryCloud = &MemoryCloud{
+			Queue: cloud.NewMemoryQueueService(
+				config.CloudConfig.OrderPaymentQueue,
+				config.QueueConfig,
+				createPaymentService,
+				createPaymentGatewayService,
+			),
+			UpdateOrderTopic:     cloud.NewMemoryTopicService(config.CloudConfig.UpdateOrderTopic),
+			OrderProductionTopic: cloud.NewMemoryTopicService(config.CloudConfig.OrderProductionTopic),
+		}
+
+		queue = memoryCloud.Queue
+		updateOrderTopic = memoryCloud.UpdateOrderTopic
+		orderProductionTopic = memoryCloud.OrderProductionTopic
+	default:
+		panic(fmt.Errorf("%w: %s", cloud.ErrUnknownDriver, config.CloudConfig.Driver))
+	}
+
+	queueService := metrics.NewQueueService(queue, metricsService)
+	updateOrderTopicService := metrics.NewTopicService(updateOrderTopic, metricsService)
+	orderProductionTopicService := metrics.NewTopicService(orderProductionTopic, metricsService)
+
+	healthComponents = append(healthComponents,
+		shared_health.Component{Name: "queue", Check: queueService},
+		shared_health.Component{Name: "consumer", Check: queueService.Heartbeat()},
+		shared_health.Component{Name: "update_order_topic", Check: updateOrderTopicService},
+		shared_health.Component{Name: "order_production_topic", Check: orderProductionTopicService},
+	)
+
+	healthChecker := shared_health.NewChecker(config.HealthConfig.Timeout, config.HealthConfig.CacheTtl, healthComponents...)
+
+	return &Server{
+		Config:          config,
+		DatabaseService: databaseService,
+		KeyProvider:     keyProvider,
+		Metrics:         metricsService,
+		TracerProvider:  tracerProvider,
+		HealthChecker:   healthChecker,
+		QueueService:    queueService,
+		MemoryCloud:     memoryCloud,
 
 		UpdateOrderTopicService:     updateOrderTopicService,
 		OrderProductionTopicService: orderProductionTopicService,
 
+		OutboxRelay: outbox_relay.NewRelay(
+			outboxRepository,
+			timeProvider,
+			config.OutboxConfig,
+			updateOrderTopicService,
+			orderProductionTopicService,
+		),
+		PaymentExpiration: payment_expiration.NewExpiration(
+			paymentRepository,
+			timeProvider,
+			config.ExpirationConfig,
+			config.CloudConfig.UpdateOrderTopic,
+		),
+
 		Dependency: Dependency{
 			TimeProvider: timeProvider,
 
 			PaymentRepository: paymentRepository,
+			OutboxRepository:  outboxRepository,
+			InboxRepository:   inboxRepository,
+			HistoryRepository: historyRepository,
 
 			CreatePaymentService: createPaymentService,
-			UpdatePaymentService: update.NewService(paymentRepository, timeProvider),
+			UpdatePaymentService: update.NewService(
+				paymentRepository,
+				outboxRepository,
+				inboxRepository,
+				timeProvider,
+				config.CloudConfig.OrderProductionTopic,
+				config.CloudConfig.UpdateOrderTopic,
+			),
+			RefundPaymentService: refund.NewService(
+				paymentRepository,
+				paymentGatewayService,
+				timeProvider,
+				config.CloudConfig.UpdateOrderTopic,
+			),
 
 			UpdateOrderTopicService:     updateOrderTopicService,
 			OrderProductionTopicService: orderProductionTopicService,
 
 			GetPaymentByOrderIdService: get_by_order_id.NewService(paymentRepository),
 			GetPaymentByIDService:      get_by_id.NewService(paymentRepository),
+			GetPaymentHistoryService:   get_history.NewService(paymentRepository, historyRepository),
+			SearchPaymentsService:      search.NewService(paymentRepository),
 		},
 	}
 }
@@ -103,10 +247,16 @@ func (s *Server) GetHttpServer() *http.Server {
 
 func (s *Server) RegisterRoutes() http.Handler {
 	e := echo.New()
+	e.Use(otelecho.Middleware(s.Config.TracingConfig.ServiceName))
 	e.Use(logger.Middleware())
 	e.Use(middleware.Recover())
 
 	s.registerHealthCheck(e)
+	s.registerMetrics(e)
+
+	if s.MemoryCloud != nil && s.Config.ApiConfig.IsDevelopment() {
+		s.registerAdminHandlers(e)
+	}
 
 	group := e.Group(fmt.Sprintf("/api/%s", s.Config.ApiConfig.ApiVersion))
 
@@ -116,22 +266,49 @@ func (s *Server) RegisterRoutes() http.Handler {
 }
 
 func (server *Server) registerHealthCheck(e *echo.Echo) {
-	healthHandler := health.NewHandler(server.DatabaseService)
+	healthHandler := health.NewHandler(server.HealthChecker)
 
-	e.GET("/health", healthHandler.Handle)
+	e.GET("/health/live", healthHandler.Live)
+	e.GET("/health/ready", healthHandler.Ready)
 }
 
-func (s *Server) registerPaymentHandlers(e *echo.Group) {
-	updatePaymentHandler := payment_hook.NewHandler(
-		s.Dependency.GetPaymentByIDService,
-		s.Dependency.UpdatePaymentService,
-		s.Dependency.OrderProductionTopicService,
-		s.Dependency.UpdateOrderTopicService,
+func (s *Server) registerMetrics(e *echo.Echo) {
+	e.GET("/metrics", echo.WrapHandler(s.Metrics.Handler()))
+}
+
+func (s *Server) registerAdminHandlers(e *echo.Echo) {
+	publishedMessagesHandler := published_messages_handler.NewHandler(
+		s.MemoryCloud.UpdateOrderTopic,
+		s.MemoryCloud.OrderProductionTopic,
 	)
 
+	enqueueMessageHandler := enqueue_message_handler.NewHandler(s.MemoryCloud.Queue)
+
+	e.GET("/admin/topics/messages", publishedMessagesHandler.Handle)
+	e.POST("/admin/queue/messages", enqueueMessageHandler.Handle)
+}
+
+func (s *Server) registerPaymentHandlers(e *echo.Group) {
+	updatePaymentHandler := payment_hook.NewHandler(s.Dependency.UpdatePaymentService)
+
+	refundPaymentHandler := refund_handler.NewHandler(s.Dependency.RefundPaymentService)
+
 	getPaymentByOrderIdHandler := get_by_order_id_handler.NewHandler(s.Dependency.GetPaymentByOrderIdService)
 
-	e.Use(token.Middleware())
-	e.PATCH("/payments/webhook/:payment_id", updatePaymentHandler.Handle)
-	e.GET("/payments/order/:order_id", getPaymentByOrderIdHandler.Handle)
+	getPaymentByIdHandler := get_by_id_handler.NewHandler(s.Dependency.GetPaymentByIDService)
+
+	getPaymentHistoryHandler := get_history_handler.NewHandler(s.Dependency.GetPaymentHistoryService)
+
+	searchPaymentsHandler := search_handler.NewHandler(s.Dependency.SearchPaymentsService)
+
+	// the gateway signs the webhook with a shared secret, the other routes require a user token
+	webhookMiddleware := signature.Middleware(s.Config.GatewayConfig, s.Dependency.TimeProvider)
+	tokenMiddleware := token.Middleware(s.KeyProvider, s.Config.AuthConfig)
+
+	e.PATCH("/payments/webhook/:payment_id", updatePaymentHandler.Handle, s.Metrics.WebhookMiddleware(), webhookMiddleware)
+	e.POST("/payments/:payment_id/refunds", refundPaymentHandler.Handle, tokenMiddleware)
+	e.GET("/payments/order/:order_id", getPaymentByOrderIdHandler.Handle, tokenMiddleware)
+	e.GET("/payments/:payment_id", getPaymentByIdHandler.Handle, tokenMiddleware)
+	e.GET("/payments/:payment_id/history", getPaymentHistoryHandler.Handle, tokenMiddleware)
+	e.GET("/payments", searchPaymentsHandler.Handle, tokenMiddleware)
 }
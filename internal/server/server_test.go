@@ -1,29 +1,76 @@
 package server
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/metrics"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
 	"github.com/stretchr/testify/assert"
 )
 
+func newConfig() *environment.Config {
+	return &environment.Config{
+		ApiConfig: &environment.ApiConfig{
+			Port: 8080,
+		},
+		DbConfig: &environment.DatabaseConfig{
+			Url: "postgres://host:1234",
+		},
+		CloudConfig: &environment.CloudConfig{
+			OrderProductionTopic: "order-production-topic",
+			UpdateOrderTopic:     "update-order-topic",
+			OrderPaymentQueue:    "order-payment-queue",
+			OrderPaymentDlq:      "order-payment-dlq",
+			CloudEndpointConfig: environment.CloudEndpointConfig{
+				BaseEndpoint: "http://localhost:8080",
+				Driver:       environment.CloudDriverAws,
+			},
+		},
+		QueueConfig: &environment.QueueConfig{
+			Concurrency:      10,
+			MaxReceiveCount:  5,
+			BaseBackoff:      time.Second,
+			MaxBackoff:       time.Minute,
+			HeartbeatTimeout: 2 * time.Minute,
+		},
+		GatewayConfig: &environment.GatewayConfig{
+			BaseUrl:          "http://localhost:8081",
+			WebhookSecret:    "webhook-secret",
+			WebhookTolerance: 5 * time.Minute,
+		},
+		AuthConfig: &environment.AuthConfig{
+			JwksUrl:                "http://localhost:8082/.well-known/jwks.json",
+			JwksCacheTtl:           time.Minute,
+			JwksMinRefreshInterval: time.Second,
+		},
+		OutboxConfig: &environment.OutboxConfig{
+			PollInterval: time.Second,
+			BatchSize:    10,
+		},
+		ExpirationConfig: &environment.ExpirationConfig{
+			PaymentTtl:   time.Minute,
+			PollInterval: time.Second,
+			BatchSize:    10,
+		},
+		TracingConfig: &environment.TracingConfig{
+			Exporter:    "none",
+			ServiceName: "ms-payment-management",
+			SampleRatio: 1,
+		},
+		HealthConfig: &environment.HealthConfig{
+			Timeout:  2 * time.Second,
+			CacheTtl: 5 * time.Second,
+		},
+	}
+}
+
 func TestNewServer(t *testing.T) {
 	t.Run("Should return a new server", func(t *testing.T) {
 		// Arrange
-		config := &environment.Config{
-			ApiConfig: &environment.ApiConfig{
-				Port: 8080,
-			},
-			DbConfig: &environment.DatabaseConfig{
-				Url: "postgres://host:1234",
-			},
-			CloudConfig: &environment.CloudConfig{
-				OrderProductionTopic: "order-production-topic",
-				UpdateOrderTopic:     "update-order-topic",
-				OrderPaymentQueue:    "order-payment-queue",
-				BaseEndpoint:         "http://localhost:8080",
-			},
-		}
+		config := newConfig()
 
 		// Act
 		server := NewServer(config)
@@ -34,20 +81,7 @@ func TestNewServer(t *testing.T) {
 
 	t.Run("Should return a new server with base endpoint", func(t *testing.T) {
 		// Arrange
-		config := &environment.Config{
-			ApiConfig: &environment.ApiConfig{
-				Port: 8080,
-			},
-			DbConfig: &environment.DatabaseConfig{
-				Url: "postgres://host:1234",
-			},
-			CloudConfig: &environment.CloudConfig{
-				OrderProductionTopic: "order-production-topic",
-				UpdateOrderTopic:     "update-order-topic",
-				OrderPaymentQueue:    "order-payment-queue",
-				BaseEndpoint:         "http://localhost:8080",
-			},
-		}
+		config := newConfig()
 
 		// Act
 		server := NewServer(config)
@@ -58,20 +92,7 @@ func TestNewServer(t *testing.T) {
 
 	t.Run("Should create a http server", func(t *testing.T) {
 		// Arrange
-		config := &environment.Config{
-			ApiConfig: &environment.ApiConfig{
-				Port: 8080,
-			},
-			DbConfig: &environment.DatabaseConfig{
-				Url: "postgres://host:1234",
-			},
-			CloudConfig: &environment.CloudConfig{
-				OrderProductionTopic: "order-production-topic",
-				UpdateOrderTopic:     "update-order-topic",
-				OrderPaymentQueue:    "order-payment-queue",
-				BaseEndpoint:         "http://localhost:8080",
-			},
-		}
+		config := newConfig()
 
 		server := NewServer(config)
 
@@ -82,4 +103,66 @@ func TestNewServer(t *testing.T) {
 		assert.NotNil(t, httpServer)
 		assert.Equal(t, ":8080", httpServer.Addr)
 	})
+
+	t.Run("Should return a new server with the in-memory cloud", func(t *testing.T) {
+		// Arrange
+		config := newConfig()
+		config.CloudConfig.Driver = environment.CloudDriverMemory
+
+		// Act
+		server := NewServer(config)
+
+		// Assert
+		assert.NotNil(t, server.MemoryCloud)
+		assert.Same(t, server.MemoryCloud.Queue, server.QueueService.(*metrics.QueueService).QueueService)
+	})
+
+	t.Run("Should panic when the cloud driver is unknown", func(t *testing.T) {
+		// Arrange
+		config := newConfig()
+		config.CloudConfig.Driver = "unknown"
+
+		// Act & Assert
+		assert.PanicsWithError(t, "unknown cloud driver: unknown", func() {
+			NewServer(config)
+		})
+	})
+}
+
+func TestRegisterAdminHandlers(t *testing.T) {
+	t.Run("Should list the published messages in development with the in-memory cloud", func(t *testing.T) {
+		// Arrange
+		config := newConfig()
+		config.ApiConfig.EnvName = "development"
+		config.CloudConfig.Driver = environment.CloudDriverMemory
+
+		server := NewServer(config)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/topics/messages", nil)
+		resp := httptest.NewRecorder()
+
+		// Act
+		server.RegisterRoutes().ServeHTTP(resp, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `[]`, resp.Body.String())
+	})
+
+	t.Run("Should not register the admin handlers with the aws cloud", func(t *testing.T) {
+		// Arrange
+		config := newConfig()
+		config.ApiConfig.EnvName = "development"
+
+		server := NewServer(config)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/topics/messages", nil)
+		resp := httptest.NewRecorder()
+
+		// Act
+		server.RegisterRoutes().ServeHTTP(resp, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
 }
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.42.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	payment_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockGetPaymentHistoryService is an autogenerated mock type for the GetPaymentHistoryService type
+type MockGetPaymentHistoryService[T interface{}] struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, request
+func (_m *MockGetPaymentHistoryService[T]) Handle(ctx context.Context, request T) ([]payment_entity.StateChange, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 []payment_entity.StateChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, T) ([]payment_entity.StateChange, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, T) []payment_entity.StateChange); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payment_entity.StateChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, T) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockGetPaymentHistoryService creates a new instance of MockGetPaymentHistoryService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGetPaymentHistoryService[T interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGetPaymentHistoryService[T] {
+	mock := &MockGetPaymentHistoryService[T]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.42.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	refund_entity "github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRefundPaymentService is an autogenerated mock type for the RefundPaymentService type
+type MockRefundPaymentService[T interface{}] struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: ctx, request
+func (_m *MockRefundPaymentService[T]) Handle(ctx context.Context, request T) (*refund_entity.Refund, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *refund_entity.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, T) (*refund_entity.Refund, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, T) *refund_entity.Refund); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*refund_entity.Refund)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, T) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockRefundPaymentService creates a new instance of MockRefundPaymentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRefundPaymentService[T interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRefundPaymentService[T] {
+	mock := &MockRefundPaymentService[T]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
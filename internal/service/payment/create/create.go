@@ -30,6 +30,20 @@ func (s *Service) Handle(ctx context.Context, request CreatePaymentDTO) (*paymen
 		return nil, err
 	}
 
+	items := make([]payment_entity.PaymentItem, len(request.Items))
+	for i, item := range request.Items {
+		items[i] = payment_entity.NewPaymentItem(item.Id, item.Name, item.Quantity, item.UnitPrice, item.Discount)
+	}
+
+	if request.HasItemPrices() {
+		if err := payment_entity.ReconcileItems(items, request.TotalItems, request.Amount); err != nil {
+			slog.ErrorContext(ctx, "payment does not match its items", "payment_id", request.PaymentId, "error", err)
+			return nil, err
+		}
+	} else {
+		slog.WarnContext(ctx, "payment items without unit price, skipping the reconciliation", "payment_id", request.PaymentId)
+	}
+
 	slog.InfoContext(ctx, "checking if payment already exists", "payment_id", request.PaymentId)
 
 	exists, err := s.repository.GetByID(ctx, request.PaymentId)
@@ -44,11 +58,6 @@ func (s *Service) Handle(ctx context.Context, request CreatePaymentDTO) (*paymen
 
 	slog.InfoContext(ctx, "payment not found, creating new payment", "payment_id", request.PaymentId, "order_id", request.OrderId)
 
-	items := make([]payment_entity.PaymentItem, len(request.Items))
-	for i, item := range request.Items {
-		items[i] = payment_entity.NewPaymentItem(item.Id, item.Name, item.Quantity)
-	}
-
 	payment := payment_entity.NewPayment(
 		request.OrderId,
 		request.PaymentId,
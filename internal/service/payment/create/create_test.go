@@ -9,6 +9,8 @@ import (
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider/mocks"
 	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -42,13 +44,14 @@ func TestHandle(t *testing.T) {
 			PaymentId: uuid.NewString(),
 			Items: []CreatePaymentItemDTO{
 				{
-					Id:       uuid.NewString(),
-					Name:     "item",
-					Quantity: 1,
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
 				},
 			},
 			TotalItems: 1,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -61,13 +64,27 @@ func TestHandle(t *testing.T) {
 		timeProvider.AssertExpectations(t)
 	})
 
-	t.Run("Should return error if request is invalid", func(t *testing.T) {
+	t.Run("Should create a payment without reconciling items that have no unit price", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 
+		now := time.Now()
+
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := mocks.NewMockTimeProvider(t)
 
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{}, nil).
+			Once()
+
+		repository.On("Create", ctx, mock.Anything).
+			Return(nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(now).
+			Once()
+
 		service := NewService(repository, timeProvider)
 
 		req := CreatePaymentDTO{
@@ -80,8 +97,43 @@ func TestHandle(t *testing.T) {
 					Quantity: 1,
 				},
 			},
+			TotalItems: 2,
+			Amount:     money.New(10000, money.BRL),
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, payment)
+		assert.Equal(t, money.New(10000, money.BRL), payment.Amount)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
+
+	t.Run("Should return error if request is invalid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := mocks.NewMockTimeProvider(t)
+
+		service := NewService(repository, timeProvider)
+
+		req := CreatePaymentDTO{
+			OrderId:   uuid.NewString(),
+			PaymentId: uuid.NewString(),
+			Items: []CreatePaymentItemDTO{
+				{
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
+				},
+			},
 			TotalItems: -1,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -94,6 +146,75 @@ func TestHandle(t *testing.T) {
 		timeProvider.AssertExpectations(t)
 	})
 
+	t.Run("Should return error if the amount does not match the items", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := mocks.NewMockTimeProvider(t)
+
+		service := NewService(repository, timeProvider)
+
+		req := CreatePaymentDTO{
+			OrderId:   uuid.NewString(),
+			PaymentId: uuid.NewString(),
+			Items: []CreatePaymentItemDTO{
+				{
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  2,
+					UnitPrice: money.New(1000, money.BRL),
+					Discount:  money.New(150, money.BRL),
+				},
+			},
+			TotalItems: 2,
+			Amount:     money.New(2000, money.BRL),
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentAmountMismatch)
+		assert.Nil(t, payment)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
+
+	t.Run("Should return error if the total items does not match the items", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := mocks.NewMockTimeProvider(t)
+
+		service := NewService(repository, timeProvider)
+
+		req := CreatePaymentDTO{
+			OrderId:   uuid.NewString(),
+			PaymentId: uuid.NewString(),
+			Items: []CreatePaymentItemDTO{
+				{
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
+				},
+			},
+			TotalItems: 3,
+			Amount:     money.New(10000, money.BRL),
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentAmountMismatch)
+		assert.Nil(t, payment)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
+
 	t.Run("Should return error if repository returns error", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
@@ -122,13 +243,14 @@ func TestHandle(t *testing.T) {
 			PaymentId: uuid.NewString(),
 			Items: []CreatePaymentItemDTO{
 				{
-					Id:       uuid.NewString(),
-					Name:     "item",
-					Quantity: 1,
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
 				},
 			},
 			TotalItems: 1,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -162,13 +284,14 @@ func TestHandle(t *testing.T) {
 			PaymentId: uuid.NewString(),
 			Items: []CreatePaymentItemDTO{
 				{
-					Id:       uuid.NewString(),
-					Name:     "item",
-					Quantity: 1,
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
 				},
 			},
 			TotalItems: 1,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -199,13 +322,14 @@ func TestHandle(t *testing.T) {
 			PaymentId: uuid.NewString(),
 			Items: []CreatePaymentItemDTO{
 				{
-					Id:       uuid.NewString(),
-					Name:     "item",
-					Quantity: 1,
+					Id:        uuid.NewString(),
+					Name:      "item",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
 				},
 			},
 			TotalItems: 1,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
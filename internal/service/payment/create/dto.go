@@ -7,12 +7,21 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 )
 
 type CreatePaymentItemDTO struct {
 	Id       string `json:"id" validate:"required,uuid4"`
 	Name     string `json:"name" validate:"required"`
 	Quantity int    `json:"quantity" validate:"required,gte=1"`
+
+	// UnitPrice is optional while the producers do not send it yet, an item without it is left out of the reconciliation
+	UnitPrice money.Money `json:"unit_price" validate:"gte=0"`
+	Discount  money.Money `json:"discount" validate:"gte=0"`
+}
+
+func (dto *CreatePaymentItemDTO) HasUnitPrice() bool {
+	return !dto.UnitPrice.IsZero()
 }
 
 type CreatePaymentDTO struct {
@@ -21,12 +30,13 @@ type CreatePaymentDTO struct {
 
 	Items []CreatePaymentItemDTO `json:"items" validate:"required,dive"`
 
-	TotalItems int     `json:"total_items" validate:"required,gte=1"`
-	Amount     float64 `json:"amount" validate:"required,gt=0"`
+	TotalItems int         `json:"total_items" validate:"required,gte=1"`
+	Amount     money.Money `json:"amount" validate:"required,gt=0"`
 }
 
 func (dto *CreatePaymentDTO) Validate(ctx context.Context) error {
 	validator := validator.New()
+	validator.RegisterCustomTypeFunc(money.ValidatorValue, money.Money{})
 
 	if err := validator.Struct(dto); err != nil {
 		slog.ErrorContext(ctx, "error validating payment", "error", err)
@@ -35,3 +45,15 @@ func (dto *CreatePaymentDTO) Validate(ctx context.Context) error {
 
 	return nil
 }
+
+// HasItemPrices tells whether every item carries its unit price, only then the
+// totals sent along with the items can be reconciled with them
+func (dto *CreatePaymentDTO) HasItemPrices() bool {
+	for _, item := range dto.Items {
+		if !item.HasUnitPrice() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,18 +19,45 @@ func TestValidate(t *testing.T) {
 			PaymentId: uuid.NewString(),
 			Items: []CreatePaymentItemDTO{
 				{
-					Id:       uuid.NewString(),
-					Name:     "item1",
-					Quantity: 1,
+					Id:        uuid.NewString(),
+					Name:      "item1",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
+				},
+				{
+					Id:        uuid.NewString(),
+					Name:      "item2",
+					Quantity:  1,
+					UnitPrice: money.New(10000, money.BRL),
 				},
+			},
+			TotalItems: 1,
+			Amount:     money.New(10000, money.BRL),
+		}
+
+		// Act
+		err := dto.Validate(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should accept items without unit price", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		dto := CreatePaymentDTO{
+			OrderId:   uuid.NewString(),
+			PaymentId: uuid.NewString(),
+			Items: []CreatePaymentItemDTO{
 				{
 					Id:       uuid.NewString(),
-					Name:     "item2",
+					Name:     "item",
 					Quantity: 1,
 				},
 			},
 			TotalItems: 1,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -37,6 +65,7 @@ func TestValidate(t *testing.T) {
 
 		// Assert
 		assert.NoError(t, err)
+		assert.False(t, dto.HasItemPrices())
 	})
 
 	t.Run("Should return error if request is invalid", func(t *testing.T) {
@@ -47,7 +76,7 @@ func TestValidate(t *testing.T) {
 			OrderId:    uuid.NewString(),
 			PaymentId:  uuid.NewString(),
 			TotalItems: 0,
-			Amount:     100,
+			Amount:     money.New(10000, money.BRL),
 		}
 
 		// Act
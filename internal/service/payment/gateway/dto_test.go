@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,7 +13,7 @@ func TestValidate(t *testing.T) {
 		// Arrange
 		request := CreatePaymentGatewayDTO{
 			PaymentID: uuid.NewString(),
-			Amount:    100,
+			Amount:    money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -26,7 +27,7 @@ func TestValidate(t *testing.T) {
 		// Arrange
 		request := CreatePaymentGatewayDTO{
 			PaymentID: "invalid",
-			Amount:    100,
+			Amount:    money.New(10000, money.BRL),
 		}
 
 		// Act
@@ -40,7 +41,7 @@ func TestValidate(t *testing.T) {
 		// Arrange
 		request := CreatePaymentGatewayDTO{
 			PaymentID: uuid.NewString(),
-			Amount:    0,
+			Amount:    money.New(0, money.BRL),
 		}
 
 		// Act
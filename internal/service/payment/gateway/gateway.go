@@ -3,13 +3,28 @@ package gateway
 import (
 	"context"
 	"log/slog"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
 )
 
 type Service struct {
+	repository   repository.PaymentRepository
+	gateway      payment_gateway.GatewayService
+	timeProvider provider.TimeProvider
 }
 
-func NewService() *Service {
-	return &Service{}
+func NewService(
+	repository repository.PaymentRepository,
+	gateway payment_gateway.GatewayService,
+	timeProvider provider.TimeProvider,
+) *Service {
+	return &Service{
+		repository:   repository,
+		gateway:      gateway,
+		timeProvider: timeProvider,
+	}
 }
 
 func (s *Service) Handle(ctx context.Context, request CreatePaymentGatewayDTO) error {
@@ -17,8 +32,37 @@ func (s *Service) Handle(ctx context.Context, request CreatePaymentGatewayDTO) e
 		return err
 	}
 
-	// TODO: This is a mock for now and will be replaced by a real call to the gateway API in the future
-	slog.InfoContext(ctx, "payment request sent to gateway", "payment_id", request.PaymentID, "amount", request.Amount)
+	payment, err := s.repository.GetByID(ctx, request.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	if payment.HasCharge() {
+		slog.InfoContext(ctx, "payment already has a charge", "payment_id", payment.PaymentId, "charge_id", payment.ChargeId)
+		return nil
+	}
+
+	slog.InfoContext(ctx, "sending payment request to gateway", "payment_id", request.PaymentID, "amount", request.Amount)
+
+	charge, err := s.gateway.CreateCharge(ctx, payment_gateway.ChargeRequest{
+		ReferenceId: payment.PaymentId,
+		OrderId:     payment.OrderId,
+		Amount:      request.Amount,
+		Method:      payment_gateway.MethodPix,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "error creating charge", "payment_id", payment.PaymentId, "error", err)
+		return err
+	}
+
+	payment.SetCharge(charge.Id, charge.QrCode, s.timeProvider.GetTime())
+
+	if err := s.repository.Update(ctx, &payment); err != nil {
+		slog.ErrorContext(ctx, "error updating payment charge", "payment_id", payment.PaymentId, "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "charge created", "payment_id", payment.PaymentId, "charge_id", charge.Id)
 
 	return nil
 }
@@ -3,9 +3,18 @@ package gateway
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	gateway_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	provider_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/provider/mocks"
+	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandle(t *testing.T) {
@@ -13,30 +22,102 @@ func TestHandle(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 
+		now := time.Now()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		request := CreatePaymentGatewayDTO{
+			PaymentID: uuid.NewString(),
+			Amount:    money.New(10000, money.BRL),
+		}
+
+		repository.On("GetByID", ctx, request.PaymentID).
+			Return(payment_entity.Payment{
+				OrderId:   uuid.NewString(),
+				PaymentId: request.PaymentID,
+				State:     payment_entity.WaitingForApproval,
+			}, nil).
+			Once()
+
+		gateway.On("CreateCharge", ctx, mock.MatchedBy(func(req payment_gateway.ChargeRequest) bool {
+			return req.ReferenceId == request.PaymentID && req.Amount == request.Amount
+		})).
+			Return(&payment_gateway.Charge{
+				Id:     "charge_id",
+				QrCode: "qr_code",
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(now).
+			Once()
+
+		repository.On("Update", ctx, mock.MatchedBy(func(payment *payment_entity.Payment) bool {
+			return payment.ChargeId == "charge_id" && payment.QrCode == "qr_code"
+		})).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider)
+
+		// Act
+		err := service.Handle(ctx, request)
+
+		// Assert
+		assert.Nil(t, err)
+		repository.AssertExpectations(t)
+		gateway.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
+
+	t.Run("Should not send the request when the payment already has a charge", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
 		request := CreatePaymentGatewayDTO{
 			PaymentID: uuid.NewString(),
-			Amount:    100,
+			Amount:    money.New(10000, money.BRL),
 		}
 
-		service := NewService()
+		repository.On("GetByID", ctx, request.PaymentID).
+			Return(payment_entity.Payment{
+				OrderId:   uuid.NewString(),
+				PaymentId: request.PaymentID,
+				ChargeId:  "charge_id",
+			}, nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider)
 
 		// Act
 		err := service.Handle(ctx, request)
 
 		// Assert
 		assert.Nil(t, err)
+		repository.AssertExpectations(t)
+		gateway.AssertExpectations(t)
 	})
 
 	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
 		request := CreatePaymentGatewayDTO{
 			PaymentID: "invalid",
-			Amount:    100,
+			Amount:    money.New(10000, money.BRL),
 		}
 
-		service := NewService()
+		service := NewService(repository, gateway, timeProvider)
 
 		// Act
 		err := service.Handle(ctx, request)
@@ -44,4 +125,113 @@ func TestHandle(t *testing.T) {
 		// Assert
 		assert.NotNil(t, err)
 	})
+
+	t.Run("Should return an error if the payment is not found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		request := CreatePaymentGatewayDTO{
+			PaymentID: uuid.NewString(),
+			Amount:    money.New(10000, money.BRL),
+		}
+
+		repository.On("GetByID", ctx, request.PaymentID).
+			Return(payment_entity.Payment{}, custom_error.ErrPaymentNotFound).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider)
+
+		// Act
+		err := service.Handle(ctx, request)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentNotFound)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the gateway fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		request := CreatePaymentGatewayDTO{
+			PaymentID: uuid.NewString(),
+			Amount:    money.New(10000, money.BRL),
+		}
+
+		repository.On("GetByID", ctx, request.PaymentID).
+			Return(payment_entity.Payment{
+				OrderId:   uuid.NewString(),
+				PaymentId: request.PaymentID,
+			}, nil).
+			Once()
+
+		gateway.On("CreateCharge", ctx, mock.Anything).
+			Return(nil, custom_error.ErrGatewayUnavailable).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider)
+
+		// Act
+		err := service.Handle(ctx, request)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayUnavailable)
+		repository.AssertExpectations(t)
+		gateway.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the payment update fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		request := CreatePaymentGatewayDTO{
+			PaymentID: uuid.NewString(),
+			Amount:    money.New(10000, money.BRL),
+		}
+
+		repository.On("GetByID", ctx, request.PaymentID).
+			Return(payment_entity.Payment{
+				OrderId:   uuid.NewString(),
+				PaymentId: request.PaymentID,
+			}, nil).
+			Once()
+
+		gateway.On("CreateCharge", ctx, mock.Anything).
+			Return(&payment_gateway.Charge{
+				Id:     "charge_id",
+				QrCode: "qr_code",
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		repository.On("Update", ctx, mock.Anything).
+			Return(assert.AnError).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider)
+
+		// Act
+		err := service.Handle(ctx, request)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		repository.AssertExpectations(t)
+		gateway.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,20 @@
+package get_history
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+)
+
+type GetHistoryDTO struct {
+	PaymentId string `param:"payment_id" validate:"required,uuid4"`
+}
+
+func (d *GetHistoryDTO) Validate() error {
+	validate := validator.New()
+
+	if err := validate.Struct(d); err != nil {
+		return custom_error.ErrRequestNotValid
+	}
+
+	return nil
+}
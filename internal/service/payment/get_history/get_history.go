@@ -0,0 +1,38 @@
+package get_history
+
+import (
+	"context"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
+)
+
+type Service struct {
+	repository        repository.PaymentRepository
+	historyRepository repository.HistoryRepository
+}
+
+func NewService(repository repository.PaymentRepository, historyRepository repository.HistoryRepository) *Service {
+	return &Service{
+		repository:        repository,
+		historyRepository: historyRepository,
+	}
+}
+
+func (s *Service) Handle(ctx context.Context, request GetHistoryDTO) ([]payment_entity.StateChange, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	// an unknown payment is reported as not found instead of an empty history
+	if _, err := s.repository.GetByID(ctx, request.PaymentId); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.historyRepository.GetByPaymentID(ctx, request.PaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,129 @@
+package get_history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandle(t *testing.T) {
+	t.Run("Should return the history of the payment", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := mocks.NewMockPaymentRepository(t)
+		historyRepository := mocks.NewMockHistoryRepository(t)
+
+		paymentId := uuid.NewString()
+
+		repository.On("GetByID", ctx, paymentId).
+			Return(payment_entity.Payment{
+				PaymentId: paymentId,
+			}, nil).
+			Once()
+
+		historyRepository.On("GetByPaymentID", ctx, paymentId).
+			Return([]payment_entity.StateChange{
+				{PaymentId: paymentId, From: payment_entity.WaitingForApproval, To: payment_entity.Approved, Source: payment_entity.SourceWebhook},
+			}, nil).
+			Once()
+
+		service := NewService(repository, historyRepository)
+
+		req := GetHistoryDTO{
+			PaymentId: paymentId,
+		}
+
+		// Act
+		changes, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, payment_entity.Approved, changes[0].To)
+		repository.AssertExpectations(t)
+		historyRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := mocks.NewMockPaymentRepository(t)
+		historyRepository := mocks.NewMockHistoryRepository(t)
+
+		service := NewService(repository, historyRepository)
+
+		req := GetHistoryDTO{
+			PaymentId: "",
+		}
+
+		// Act
+		changes, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrRequestNotValid)
+		assert.Nil(t, changes)
+	})
+
+	t.Run("Should return an error if the payment is not found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := mocks.NewMockPaymentRepository(t)
+		historyRepository := mocks.NewMockHistoryRepository(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{}, custom_error.ErrPaymentNotFound).
+			Once()
+
+		service := NewService(repository, historyRepository)
+
+		req := GetHistoryDTO{
+			PaymentId: uuid.NewString(),
+		}
+
+		// Act
+		changes, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentNotFound)
+		assert.Nil(t, changes)
+		historyRepository.AssertNotCalled(t, "GetByPaymentID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return an error if the history cannot be loaded", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := mocks.NewMockPaymentRepository(t)
+		historyRepository := mocks.NewMockHistoryRepository(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{}, nil).
+			Once()
+
+		historyRepository.On("GetByPaymentID", ctx, mock.Anything).
+			Return(nil, assert.AnError).
+			Once()
+
+		service := NewService(repository, historyRepository)
+
+		req := GetHistoryDTO{
+			PaymentId: uuid.NewString(),
+		}
+
+		// Act
+		changes, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, changes)
+	})
+}
@@ -0,0 +1,27 @@
+package refund
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+type RefundPaymentDTO struct {
+	PaymentId string      `param:"payment_id" json:"-" validate:"required,uuid4"`
+	Amount    money.Money `json:"amount" validate:"required,gt=0"`
+	Reason    string      `json:"reason" validate:"max=255"`
+
+	// ActorId is the user that requested the refund, taken from the token
+	ActorId string `json:"-"`
+}
+
+func (dto *RefundPaymentDTO) Validate() error {
+	validator := validator.New()
+	validator.RegisterCustomTypeFunc(money.ValidatorValue, money.Money{})
+
+	if err := validator.Struct(dto); err != nil {
+		return custom_error.ErrRequestNotValid
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package refund
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	t.Run("Should return nil if the request is valid", func(t *testing.T) {
+		// Arrange
+		dto := RefundPaymentDTO{
+			PaymentId: uuid.NewString(),
+			Amount:    money.New(1000, money.BRL),
+			Reason:    "order cancelled",
+		}
+
+		// Act
+		err := dto.Validate()
+
+		// Assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("Should return error if the request is not valid", func(t *testing.T) {
+		// Arrange
+		cases := []RefundPaymentDTO{
+			{PaymentId: "", Amount: money.New(1000, money.BRL)},
+			{PaymentId: uuid.NewString(), Amount: money.New(0, money.BRL)},
+			{PaymentId: uuid.NewString(), Amount: money.New(-100, money.BRL)},
+			{PaymentId: uuid.NewString(), Amount: money.New(1000, money.BRL), Reason: strings.Repeat("a", 256)},
+		}
+
+		for _, dto := range cases {
+			// Act
+			err := dto.Validate()
+
+			// Assert
+			assert.NotNil(t, err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package refund
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+)
+
+type Service struct {
+	repository   repository.PaymentRepository
+	gateway      payment_gateway.GatewayService
+	timeProvider provider.TimeProvider
+
+	updateOrderTopic string
+}
+
+func NewService(
+	repository repository.PaymentRepository,
+	gateway payment_gateway.GatewayService,
+	timeProvider provider.TimeProvider,
+	updateOrderTopic string,
+) *Service {
+	return &Service{
+		repository:   repository,
+		gateway:      gateway,
+		timeProvider: timeProvider,
+
+		updateOrderTopic: updateOrderTopic,
+	}
+}
+
+// Handle records the refund as pending before calling the gateway, so a refund made by the gateway
+// whose outcome could not be stored is settled by the next request, under the same idempotency key
+func (s *Service) Handle(ctx context.Context, request RefundPaymentDTO) (*refund_entity.Refund, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	payment, err := s.repository.GetByID(ctx, request.PaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := payment_entity.ChangeOrigin{
+		Source:  payment_entity.SourceAdmin,
+		ActorId: request.ActorId,
+	}
+
+	pending, err := s.repository.GetPendingRefund(ctx, payment.PaymentId)
+	switch {
+	case err == nil:
+		slog.WarnContext(ctx, "settling pending refund", "payment_id", payment.PaymentId, "refund_id", pending.Id)
+
+		if err := s.settle(ctx, &payment, &pending, origin); err != nil {
+			return nil, err
+		}
+
+		// the pending refund is taken as the one requested again, so it is not refunded twice
+		if pending.IsSameRequest(request.Amount, request.Reason) {
+			return &pending, nil
+		}
+	case !errors.Is(err, custom_error.ErrRefundNotFound):
+		return nil, err
+	}
+
+	if !payment.IsRefundable() {
+		return nil, custom_error.ErrPaymentNotRefundable
+	}
+
+	if request.Amount.Cmp(payment.RemainingAmount()) > 0 {
+		return nil, custom_error.ErrRefundAmountExceeded
+	}
+
+	refund := refund_entity.NewRefund(payment.OrderId, payment.PaymentId, request.Amount, request.Reason, s.timeProvider.GetTime())
+
+	if err := s.repository.CreatePendingRefund(ctx, &payment, &refund); err != nil {
+		return nil, err
+	}
+
+	if err := s.settle(ctx, &payment, &refund, origin); err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+// settle sends the pending refund to the gateway and stores its outcome, a refund the gateway
+// could not be reached for, or whose outcome could not be stored, is left pending
+func (s *Service) settle(ctx context.Context, payment *payment_entity.Payment, refund *refund_entity.Refund, origin payment_entity.ChangeOrigin) error {
+	slog.InfoContext(ctx, "sending refund request to gateway", "payment_id", payment.PaymentId, "refund_id", refund.Id, "amount", refund.Amount)
+
+	gatewayRefund, err := s.gateway.RefundCharge(ctx, payment_gateway.RefundRequest{
+		ReferenceId: refund.Id,
+		ChargeId:    payment.ChargeId,
+		Amount:      refund.Amount,
+		Reason:      refund.Reason,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "error refunding charge", "payment_id", payment.PaymentId, "refund_id", refund.Id, "error", err)
+
+		if errors.Is(err, custom_error.ErrGatewayRequestNotValid) {
+			refund.Fail()
+
+			if errFail := s.repository.FailRefund(ctx, refund); errFail != nil {
+				slog.ErrorContext(ctx, "error failing refund", "payment_id", payment.PaymentId, "refund_id", refund.Id, "error", errFail)
+			}
+		}
+
+		return err
+	}
+
+	refund.Complete(gatewayRefund.Id)
+
+	now := s.timeProvider.GetTime()
+
+	if err := payment.ApplyRefund(refund.Amount, now, origin); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "refund completed, scheduling message to update order topic", "payment_id", payment.PaymentId, "state", payment.StateTitle)
+
+	message, err := outbox_entity.NewMessage(s.updateOrderTopic, cloud.NewUpdateOrderContractFromPayment(payment), now)
+	if err != nil {
+		return err
+	}
+
+	err = s.repository.CompleteRefund(ctx, payment, refund, message)
+	if errors.Is(err, custom_error.ErrRefundNotPending) {
+		// another request settled the same refund with the gateway meanwhile, the refund
+		// it applied is reloaded so it is not applied twice by the checks that follow
+		slog.WarnContext(ctx, "refund settled by another request, reloading payment", "payment_id", payment.PaymentId, "refund_id", refund.Id)
+
+		reloaded, err := s.repository.GetByID(ctx, payment.PaymentId)
+		if err != nil {
+			return err
+		}
+
+		*payment = reloaded
+
+		return nil
+	}
+
+	return err
+}
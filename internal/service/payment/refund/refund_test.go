@@ -0,0 +1,768 @@
+package refund
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	gateway_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
+	provider_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/provider/mocks"
+	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newApprovedPayment(amount money.Money) payment_entity.Payment {
+	payment := payment_entity.NewPayment(uuid.NewString(), uuid.NewString(), nil, 1, amount, time.Now())
+	payment.SetCharge("charge_id", "qr_code", time.Now())
+	_ = payment.TransitionTo(payment_entity.Approved, time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceWebhook})
+	payment.ClearStateChanges()
+
+	return payment
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("Should partially refund the payment", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		now := time.Now()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(now)
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.IsPending()
+		})).
+			Return(nil).
+			Once()
+
+		gateway.On("RefundCharge", ctx, mock.MatchedBy(func(request payment_gateway.RefundRequest) bool {
+			return request.ReferenceId != "" && request.ChargeId == "charge_id" && request.Amount == money.New(400, money.BRL)
+		})).
+			Return(&payment_gateway.Refund{
+				Id:     "gateway_refund_id",
+				Status: payment_gateway.RefundStatusSucceeded,
+			}, nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.State == payment_entity.PartiallyRefunded && p.RefundedAmount == money.New(400, money.BRL) &&
+					len(p.StateChanges) == 1 &&
+					p.StateChanges[0].Source == payment_entity.SourceAdmin &&
+					p.StateChanges[0].ActorId == "user_id"
+			}),
+			mock.MatchedBy(func(r *refund_entity.Refund) bool {
+				return r.GatewayRefundId == "gateway_refund_id" && r.Amount == money.New(400, money.BRL) &&
+					r.State == refund_entity.RefundCompleted
+			}),
+			mock.MatchedBy(func(message outbox_entity.Message) bool {
+				var contract cloud.UpdateOrderTopicContract
+				assert.NoError(t, json.Unmarshal([]byte(message.Payload), &contract))
+
+				return message.Topic == "UpdateOrderTopic" && contract.Payment.State == "PartiallyRefunded"
+			})).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(400, money.BRL),
+			Reason:    "order cancelled",
+			ActorId:   "user_id",
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, refund)
+		assert.Equal(t, payment.PaymentId, refund.PaymentId)
+		assert.Equal(t, "order cancelled", refund.Reason)
+		repository.AssertExpectations(t)
+		gateway.AssertExpectations(t)
+	})
+
+	t.Run("Should fully refund the payment", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+		assert.NoError(t, payment.ApplyRefund(money.New(400, money.BRL), time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceAdmin}))
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.IsPending()
+		})).
+			Return(nil).
+			Once()
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(&payment_gateway.Refund{Id: "gateway_refund_id"}, nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.State == payment_entity.Refunded && p.RemainingAmount().IsZero()
+			}),
+			mock.Anything,
+			mock.Anything).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(600, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, refund)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return error if the request is not valid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: "invalid",
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrRequestNotValid)
+		assert.Nil(t, refund)
+	})
+
+	t.Run("Should return error if the payment is not found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{}, custom_error.ErrPaymentNotFound).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: uuid.NewString(),
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentNotFound)
+		assert.Nil(t, refund)
+	})
+
+	t.Run("Should return error if the payment is not refundable", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+		payment.State = payment_entity.Rejected
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentNotRefundable)
+		assert.Nil(t, refund)
+	})
+
+	t.Run("Should return error if the amount exceeds the remaining amount", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+		assert.NoError(t, payment.ApplyRefund(money.New(400, money.BRL), time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceAdmin}))
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(601, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrRefundAmountExceeded)
+		assert.Nil(t, refund)
+	})
+
+	t.Run("Should leave the refund pending if the gateway is unavailable", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.IsPending()
+		})).
+			Return(nil).
+			Once()
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(nil, custom_error.ErrGatewayUnavailable).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayUnavailable)
+		assert.Nil(t, refund)
+		repository.AssertNotCalled(t, "CompleteRefund")
+		repository.AssertNotCalled(t, "FailRefund")
+	})
+
+	t.Run("Should fail the refund if the gateway rejects it", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.IsPending()
+		})).
+			Return(nil).
+			Once()
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(nil, custom_error.ErrGatewayRequestNotValid).
+			Once()
+
+		repository.On("FailRefund", ctx, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.State == refund_entity.RefundFailed
+		})).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrGatewayRequestNotValid)
+		assert.Nil(t, refund)
+		repository.AssertNotCalled(t, "CompleteRefund")
+	})
+
+	t.Run("Should return error if the pending refund cannot be stored", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.Anything).
+			Return(custom_error.ErrPaymentConcurrentUpdate).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentConcurrentUpdate)
+		assert.Nil(t, refund)
+		gateway.AssertNotCalled(t, "RefundCharge")
+	})
+
+	t.Run("Should settle the refund on retry without refunding twice when it was not stored", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		var pending refund_entity.Refund
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment, nil).
+			Twice()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		// the first request reaches the gateway but fails to store the outcome
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				pending = *args.Get(2).(*refund_entity.Refund)
+			}).
+			Return(nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx, mock.Anything, mock.Anything, mock.Anything).
+			Return(custom_error.ErrPaymentConcurrentUpdate).
+			Once()
+
+		// the retry finds the refund left pending
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(func(context.Context, string) refund_entity.Refund {
+				return pending
+			}, nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.RefundedAmount == money.New(400, money.BRL)
+			}),
+			mock.MatchedBy(func(r *refund_entity.Refund) bool {
+				return r.Id == pending.Id && r.State == refund_entity.RefundCompleted
+			}),
+			mock.Anything).
+			Return(nil).
+			Once()
+
+		referenceIds := make([]string, 0)
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Run(func(args mock.Arguments) {
+				referenceIds = append(referenceIds, args.Get(1).(payment_gateway.RefundRequest).ReferenceId)
+			}).
+			Return(&payment_gateway.Refund{Id: "gateway_refund_id"}, nil).
+			Twice()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(400, money.BRL),
+			Reason:    "order cancelled",
+		}
+
+		_, err := service.Handle(ctx, req)
+		assert.ErrorIs(t, err, custom_error.ErrPaymentConcurrentUpdate)
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, pending.Id, refund.Id)
+		assert.Equal(t, []string{pending.Id, pending.Id}, referenceIds)
+		repository.AssertNumberOfCalls(t, "CreatePendingRefund", 1)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should settle the pending refund before a different one", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		pending := refund_entity.NewRefund(payment.OrderId, payment.PaymentId, money.New(400, money.BRL), "", time.Now())
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(pending, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(&payment_gateway.Refund{Id: "gateway_refund_id"}, nil).
+			Twice()
+
+		repository.On("CompleteRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.Id == pending.Id
+		}), mock.Anything).
+			Return(nil).
+			Once()
+
+		repository.On("CreatePendingRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.RefundedAmount == money.New(400, money.BRL)
+			}),
+			mock.Anything).
+			Return(nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.State == payment_entity.Refunded
+			}),
+			mock.MatchedBy(func(r *refund_entity.Refund) bool {
+				return r.Id != pending.Id
+			}),
+			mock.Anything).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(600, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEqual(t, pending.Id, refund.Id)
+		assert.Equal(t, money.New(600, money.BRL), refund.Amount)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return the refund settled meanwhile by another request", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		settled := payment
+		_ = settled.ApplyRefund(money.New(100, money.BRL), time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceAdmin})
+		settled.Version++
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.IsPending()
+		})).
+			Return(nil).
+			Once()
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(&payment_gateway.Refund{Id: "gateway_refund_id"}, nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx, mock.Anything, mock.Anything, mock.Anything).
+			Return(custom_error.ErrRefundNotPending).
+			Once()
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(settled, nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, refund)
+	})
+	t.Run("Should continue a different refund from the payment reloaded after the pending one was settled meanwhile", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		pending := refund_entity.NewRefund(payment.OrderId, payment.PaymentId, money.New(400, money.BRL), "", time.Now())
+
+		settled := payment
+		_ = settled.ApplyRefund(pending.Amount, time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceAdmin})
+		settled.ClearStateChanges()
+		settled.Version++
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(pending, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(&payment_gateway.Refund{Id: "gateway_refund_id"}, nil).
+			Twice()
+
+		repository.On("CompleteRefund", ctx, mock.Anything, mock.MatchedBy(func(r *refund_entity.Refund) bool {
+			return r.Id == pending.Id
+		}), mock.Anything).
+			Return(custom_error.ErrRefundNotPending).
+			Once()
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(settled, nil).
+			Once()
+
+		repository.On("CreatePendingRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.RefundedAmount == money.New(400, money.BRL) && p.Version == settled.Version
+			}),
+			mock.Anything).
+			Return(nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx,
+			mock.MatchedBy(func(p *payment_entity.Payment) bool {
+				return p.State == payment_entity.Refunded && p.Version == settled.Version
+			}),
+			mock.MatchedBy(func(r *refund_entity.Refund) bool {
+				return r.Id != pending.Id
+			}),
+			mock.Anything).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(600, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEqual(t, pending.Id, refund.Id)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return error if the payment cannot be reloaded after the refund was settled meanwhile", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		gateway := gateway_mocks.NewMockGatewayService(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		payment := newApprovedPayment(money.New(1000, money.BRL))
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment, nil).
+			Once()
+
+		repository.On("GetPendingRefund", ctx, payment.PaymentId).
+			Return(refund_entity.Refund{}, custom_error.ErrRefundNotFound).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now())
+
+		repository.On("CreatePendingRefund", ctx, mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+
+		gateway.On("RefundCharge", ctx, mock.Anything).
+			Return(&payment_gateway.Refund{Id: "gateway_refund_id"}, nil).
+			Once()
+
+		repository.On("CompleteRefund", ctx, mock.Anything, mock.Anything, mock.Anything).
+			Return(custom_error.ErrRefundNotPending).
+			Once()
+
+		repository.On("GetByID", ctx, payment.PaymentId).
+			Return(payment_entity.Payment{}, errors.New("connection refused")).
+			Once()
+
+		service := NewService(repository, gateway, timeProvider, "UpdateOrderTopic")
+
+		req := RefundPaymentDTO{
+			PaymentId: payment.PaymentId,
+			Amount:    money.New(100, money.BRL),
+		}
+
+		// Act
+		refund, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, refund)
+	})
+}
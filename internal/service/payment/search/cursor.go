@@ -0,0 +1,41 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+)
+
+// cursor is handed to the client as an opaque string, it keeps the sort
+// of the search so it cannot be used to continue a search sorted otherwise
+type cursor struct {
+	SortBy     payment_entity.SortField `json:"sort_by"`
+	Descending bool                     `json:"descending"`
+	Key        payment_entity.SearchKey `json:"key"`
+}
+
+func encodeCursor(c cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(value string) (cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor{}, custom_error.ErrCursorNotValid
+	}
+
+	var c cursor
+
+	if err := json.Unmarshal(data, &c); err != nil || c.Key.PaymentId == "" {
+		return cursor{}, custom_error.ErrCursorNotValid
+	}
+
+	return c, nil
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+)
+
+const (
+	defaultLimit = 20
+	defaultSort  = "-created_at"
+)
+
+type SearchPaymentsDTO struct {
+	States []string `query:"state" validate:"dive,oneof=WaitingForApproval Approved Rejected PartiallyRefunded Refunded Expired"`
+
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	UpdatedFrom string `query:"updated_from"`
+	UpdatedTo   string `query:"updated_to"`
+
+	MinAmount string `query:"min_amount"`
+	MaxAmount string `query:"max_amount"`
+
+	Sort   string `query:"sort" validate:"omitempty,oneof=created_at -created_at updated_at -updated_at amount -amount"`
+	Limit  int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Cursor string `query:"cursor"`
+}
+
+func (dto *SearchPaymentsDTO) Validate() error {
+	validator := validator.New()
+
+	if err := validator.Struct(dto); err != nil {
+		return custom_error.ErrRequestNotValid
+	}
+
+	return nil
+}
+
+// ToFilter parses the request, dates are RFC 3339 and a sort starting with "-" is descending
+func (dto *SearchPaymentsDTO) ToFilter() (payment_entity.SearchFilter, error) {
+	filter := payment_entity.SearchFilter{
+		Limit: dto.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+
+	for _, state := range dto.States {
+		filter.States = append(filter.States, payment_entity.NewPaymentState(state))
+	}
+
+	var err error
+
+	if filter.CreatedFrom, err = parseTime(dto.CreatedFrom); err != nil {
+		return payment_entity.SearchFilter{}, err
+	}
+	if filter.CreatedTo, err = parseTime(dto.CreatedTo); err != nil {
+		return payment_entity.SearchFilter{}, err
+	}
+	if filter.UpdatedFrom, err = parseTime(dto.UpdatedFrom); err != nil {
+		return payment_entity.SearchFilter{}, err
+	}
+	if filter.UpdatedTo, err = parseTime(dto.UpdatedTo); err != nil {
+		return payment_entity.SearchFilter{}, err
+	}
+	if filter.MinAmount, err = parseAmount(dto.MinAmount); err != nil {
+		return payment_entity.SearchFilter{}, err
+	}
+	if filter.MaxAmount, err = parseAmount(dto.MaxAmount); err != nil {
+		return payment_entity.SearchFilter{}, err
+	}
+
+	if isAfter(filter.CreatedFrom, filter.CreatedTo) || isAfter(filter.UpdatedFrom, filter.UpdatedTo) {
+		return payment_entity.SearchFilter{}, custom_error.ErrRequestNotValid
+	}
+
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MinAmount.Cmp(*filter.MaxAmount) > 0 {
+		return payment_entity.SearchFilter{}, custom_error.ErrRequestNotValid
+	}
+
+	sort := dto.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	filter.Descending = strings.HasPrefix(sort, "-")
+	filter.SortBy = payment_entity.SortField(strings.TrimPrefix(sort, "-"))
+
+	if dto.Cursor != "" {
+		c, err := decodeCursor(dto.Cursor)
+		if err != nil {
+			return payment_entity.SearchFilter{}, err
+		}
+
+		if c.SortBy != filter.SortBy || c.Descending != filter.Descending {
+			return payment_entity.SearchFilter{}, custom_error.ErrCursorNotValid
+		}
+
+		filter.After = &c.Key
+	}
+
+	return filter, nil
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, custom_error.ErrRequestNotValid
+	}
+
+	return &parsed, nil
+}
+
+func parseAmount(value string) (*money.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := money.Parse(value, money.DefaultCurrency)
+	if err != nil || parsed.IsNegative() {
+		return nil, custom_error.ErrRequestNotValid
+	}
+
+	return &parsed, nil
+}
+
+func isAfter(from *time.Time, to *time.Time) bool {
+	return from != nil && to != nil && from.After(*to)
+}
@@ -0,0 +1,171 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Should return nil if the request is valid", func(t *testing.T) {
+		// Arrange
+		dto := SearchPaymentsDTO{
+			States: []string{"WaitingForApproval", "Approved"},
+			Sort:   "-amount",
+			Limit:  100,
+		}
+
+		// Act
+		err := dto.Validate()
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
+		// Arrange
+		cases := []SearchPaymentsDTO{
+			{States: []string{"Unknown"}},
+			{Sort: "order_id"},
+			{Limit: 101},
+			{Limit: -1},
+		}
+
+		for _, dto := range cases {
+			// Act
+			err := dto.Validate()
+
+			// Assert
+			assert.ErrorIs(t, err, custom_error.ErrRequestNotValid)
+		}
+	})
+}
+
+func TestToFilter(t *testing.T) {
+	t.Run("Should parse every filter", func(t *testing.T) {
+		// Arrange
+		dto := SearchPaymentsDTO{
+			States:      []string{"WaitingForApproval"},
+			CreatedFrom: "2024-05-18T00:00:00Z",
+			CreatedTo:   "2024-05-19T00:00:00-03:00",
+			UpdatedFrom: "2024-05-18T12:00:00Z",
+			MinAmount:   "10",
+			MaxAmount:   "99.90",
+			Sort:        "updated_at",
+			Limit:       5,
+		}
+
+		// Act
+		filter, err := dto.ToFilter()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []payment_entity.PaymentState{payment_entity.WaitingForApproval}, filter.States)
+		assert.True(t, filter.CreatedFrom.Equal(time.Date(2024, 5, 18, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, filter.CreatedTo.Equal(time.Date(2024, 5, 19, 3, 0, 0, 0, time.UTC)))
+		assert.True(t, filter.UpdatedFrom.Equal(time.Date(2024, 5, 18, 12, 0, 0, 0, time.UTC)))
+		assert.Nil(t, filter.UpdatedTo)
+		assert.Equal(t, money.New(1000, money.BRL), *filter.MinAmount)
+		assert.Equal(t, money.New(9990, money.BRL), *filter.MaxAmount)
+		assert.Equal(t, payment_entity.SortByUpdatedAt, filter.SortBy)
+		assert.False(t, filter.Descending)
+		assert.Equal(t, 5, filter.Limit)
+		assert.Nil(t, filter.After)
+	})
+
+	t.Run("Should sort by the newest payments by default", func(t *testing.T) {
+		// Arrange
+		dto := SearchPaymentsDTO{}
+
+		// Act
+		filter, err := dto.ToFilter()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, payment_entity.SortByCreatedAt, filter.SortBy)
+		assert.True(t, filter.Descending)
+		assert.Equal(t, defaultLimit, filter.Limit)
+	})
+
+	t.Run("Should continue after the cursor", func(t *testing.T) {
+		// Arrange
+		key := payment_entity.SearchKey{
+			PaymentId: "payment_id",
+			Amount:    money.New(1000, money.BRL),
+		}
+
+		value, err := encodeCursor(cursor{SortBy: payment_entity.SortByAmount, Descending: true, Key: key})
+		assert.NoError(t, err)
+
+		dto := SearchPaymentsDTO{
+			Sort:   "-amount",
+			Cursor: value,
+		}
+
+		// Act
+		filter, err := dto.ToFilter()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &key, filter.After)
+	})
+
+	t.Run("Should return an error if the cursor belongs to another sort", func(t *testing.T) {
+		// Arrange
+		value, err := encodeCursor(cursor{SortBy: payment_entity.SortByAmount, Key: payment_entity.SearchKey{PaymentId: "payment_id"}})
+		assert.NoError(t, err)
+
+		dto := SearchPaymentsDTO{
+			Sort:   "-amount",
+			Cursor: value,
+		}
+
+		// Act
+		_, err = dto.ToFilter()
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrCursorNotValid)
+	})
+
+	t.Run("Should return an error if the cursor is not valid", func(t *testing.T) {
+		// Arrange
+		cases := []string{"not base64!", "bm90IGpzb24", "e30"}
+
+		for _, value := range cases {
+			dto := SearchPaymentsDTO{
+				Cursor: value,
+			}
+
+			// Act
+			_, err := dto.ToFilter()
+
+			// Assert
+			assert.ErrorIs(t, err, custom_error.ErrCursorNotValid, value)
+		}
+	})
+
+	t.Run("Should return an error if a filter is not valid", func(t *testing.T) {
+		// Arrange
+		cases := []SearchPaymentsDTO{
+			{CreatedFrom: "yesterday"},
+			{UpdatedTo: "2024-05-19"},
+			{MinAmount: "abc"},
+			{MaxAmount: "-1"},
+			{CreatedFrom: "2024-05-19T00:00:00Z", CreatedTo: "2024-05-18T00:00:00Z"},
+			{UpdatedFrom: "2024-05-19T00:00:00Z", UpdatedTo: "2024-05-18T00:00:00Z"},
+			{MinAmount: "10", MaxAmount: "5"},
+		}
+
+		for _, dto := range cases {
+			// Act
+			_, err := dto.ToFilter()
+
+			// Assert
+			assert.ErrorIs(t, err, custom_error.ErrRequestNotValid)
+		}
+	})
+}
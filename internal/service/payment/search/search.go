@@ -0,0 +1,58 @@
+package search
+
+import (
+	"context"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
+)
+
+type Service struct {
+	repository repository.PaymentRepository
+}
+
+func NewService(repository repository.PaymentRepository) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+func (s *Service) Handle(ctx context.Context, request SearchPaymentsDTO) (payment_entity.SearchPage, error) {
+	if err := request.Validate(); err != nil {
+		return payment_entity.SearchPage{}, err
+	}
+
+	filter, err := request.ToFilter()
+	if err != nil {
+		return payment_entity.SearchPage{}, err
+	}
+
+	limit := filter.Limit
+
+	// one more payment than requested tells whether there is a next page
+	filter.Limit = limit + 1
+
+	payments, err := s.repository.Search(ctx, filter)
+	if err != nil {
+		return payment_entity.SearchPage{}, err
+	}
+
+	page := payment_entity.SearchPage{
+		Payments: payments,
+	}
+
+	if len(payments) > limit {
+		page.Payments = payments[:limit]
+
+		page.NextCursor, err = encodeCursor(cursor{
+			SortBy:     filter.SortBy,
+			Descending: filter.Descending,
+			Key:        payment_entity.NewSearchKey(&page.Payments[limit-1]),
+		})
+		if err != nil {
+			return payment_entity.SearchPage{}, err
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPayments(count int) []payment_entity.Payment {
+	now := time.Now()
+
+	payments := make([]payment_entity.Payment, 0, count)
+	for i := 0; i < count; i++ {
+		payments = append(payments, payment_entity.Payment{
+			PaymentId: string(rune('a' + i)),
+			CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+
+	return payments
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("Should return a cursor when there are more payments", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+
+		payments := newPayments(3)
+
+		repository.On("Search", ctx, mock.MatchedBy(func(filter payment_entity.SearchFilter) bool {
+			return filter.Limit == 3 && filter.After == nil
+		})).
+			Return(payments, nil).
+			Once()
+
+		service := NewService(repository)
+
+		// Act
+		page, err := service.Handle(ctx, SearchPaymentsDTO{Limit: 2})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, page.Payments, 2)
+		assert.NotEmpty(t, page.NextCursor)
+
+		c, err := decodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, payment_entity.SortByCreatedAt, c.SortBy)
+		assert.True(t, c.Descending)
+		assert.Equal(t, "b", c.Key.PaymentId)
+		assert.True(t, payments[1].CreatedAt.Equal(c.Key.CreatedAt))
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should continue the search from the cursor", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+
+		repository.On("Search", ctx, mock.Anything).
+			Return(newPayments(3), nil).
+			Once()
+
+		repository.On("Search", ctx, mock.MatchedBy(func(filter payment_entity.SearchFilter) bool {
+			return filter.After != nil && filter.After.PaymentId == "b"
+		})).
+			Return(newPayments(1), nil).
+			Once()
+
+		service := NewService(repository)
+
+		first, err := service.Handle(ctx, SearchPaymentsDTO{Limit: 2})
+		assert.NoError(t, err)
+
+		// Act
+		page, err := service.Handle(ctx, SearchPaymentsDTO{Limit: 2, Cursor: first.NextCursor})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, page.Payments, 1)
+		assert.Empty(t, page.NextCursor)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the request is invalid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+
+		service := NewService(repository)
+
+		// Act
+		_, err := service.Handle(ctx, SearchPaymentsDTO{Sort: "invalid"})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrRequestNotValid)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the cursor is invalid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+
+		service := NewService(repository)
+
+		// Act
+		_, err := service.Handle(ctx, SearchPaymentsDTO{Cursor: "invalid"})
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrCursorNotValid)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error if the search fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+
+		repository.On("Search", ctx, mock.Anything).
+			Return(nil, assert.AnError).
+			Once()
+
+		service := NewService(repository)
+
+		// Act
+		_, err := service.Handle(ctx, SearchPaymentsDTO{})
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		repository.AssertExpectations(t)
+	})
+}
@@ -7,8 +7,12 @@ import (
 
 type UpdatePaymentDTO struct {
 	PaymentId string `param:"payment_id" json:"payment_id" validate:"required,uuid4"`
+	EventId   string `json:"event_id" validate:"omitempty,max=255"`
 	Resend    bool   `query:"resend" json:"-"`
 	Approved  bool   `json:"approved"`
+
+	// Payload is the raw notification, kept in the state history
+	Payload string `json:"-"`
 }
 
 func (dto *UpdatePaymentDTO) Validate() error {
@@ -2,7 +2,13 @@ package update
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
@@ -10,17 +16,31 @@ import (
 )
 
 type Service struct {
-	repository   repository.PaymentRepository
-	timeProvider provider.TimeProvider
+	repository       repository.PaymentRepository
+	outboxRepository repository.OutboxRepository
+	inboxRepository  repository.InboxRepository
+	timeProvider     provider.TimeProvider
+
+	orderProductionTopic string
+	updateOrderTopic     string
 }
 
 func NewService(
 	repository repository.PaymentRepository,
+	outboxRepository repository.OutboxRepository,
+	inboxRepository repository.InboxRepository,
 	timeProvider provider.TimeProvider,
+	orderProductionTopic string,
+	updateOrderTopic string,
 ) *Service {
 	return &Service{
-		repository:   repository,
-		timeProvider: timeProvider,
+		repository:       repository,
+		outboxRepository: outboxRepository,
+		inboxRepository:  inboxRepository,
+		timeProvider:     timeProvider,
+
+		orderProductionTopic: orderProductionTopic,
+		updateOrderTopic:     updateOrderTopic,
 	}
 }
 
@@ -29,18 +49,20 @@ func (s *Service) Handle(ctx context.Context, request UpdatePaymentDTO) (*paymen
 		return nil, err
 	}
 
+	if request.EventId != "" {
+		payment, err := s.processedEvent(ctx, request)
+		if !errors.Is(err, custom_error.ErrEventNotFound) {
+			return payment, err
+		}
+	}
+
 	payment, err := s.repository.GetByID(ctx, request.PaymentId)
 	if err != nil {
 		return nil, err
 	}
 
-	validStates := []payment_entity.PaymentState{
-		payment_entity.Approved,
-		payment_entity.Rejected,
-	}
-
-	if payment.IsInState(validStates...) {
-		return nil, custom_error.ErrPaymentAlreadyInState
+	if request.Resend {
+		return s.resend(ctx, &payment, request)
 	}
 
 	var state payment_entity.PaymentState
@@ -51,11 +73,119 @@ func (s *Service) Handle(ctx context.Context, request UpdatePaymentDTO) (*paymen
 		state = payment_entity.Rejected
 	}
 
-	payment.UpdateState(state, s.timeProvider.GetTime())
+	now := s.timeProvider.GetTime()
+
+	err = payment.TransitionTo(state, now, payment_entity.ChangeOrigin{
+		Source:  payment_entity.SourceWebhook,
+		Payload: request.Payload,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "payment state transition not allowed", "payment_id", payment.PaymentId, "error", err)
+		return nil, err
+	}
+
+	messages := make([]outbox_entity.Message, 0, 2)
+
+	if payment.State == payment_entity.Approved {
+		slog.InfoContext(ctx, "payment approved, scheduling message to production topic", "payment_id", payment.PaymentId)
+
+		message, err := outbox_entity.NewMessage(s.orderProductionTopic, cloud.NewOrderProductionContractFromPayment(&payment), now)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	slog.InfoContext(ctx, "scheduling message to update order topic", "payment_id", payment.PaymentId)
+
+	message, err := outbox_entity.NewMessage(s.updateOrderTopic, cloud.NewUpdateOrderContractFromPayment(&payment), now)
+	if err != nil {
+		return nil, err
+	}
+
+	messages = append(messages, message)
+
+	event, err := newEvent(request, &payment, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateFromEvent(ctx, &payment, event, messages...); err != nil {
+		if errors.Is(err, custom_error.ErrEventAlreadyProcessed) {
+			return s.processedEvent(ctx, request)
+		}
 
-	if err := s.repository.Update(ctx, &payment); err != nil {
 		return nil, err
 	}
 
 	return &payment, nil
 }
+
+func (s *Service) resend(ctx context.Context, payment *payment_entity.Payment, request UpdatePaymentDTO) (*payment_entity.Payment, error) {
+	slog.InfoContext(ctx, "payment resend requested, scheduling message to update order topic", "payment_id", payment.PaymentId)
+
+	now := s.timeProvider.GetTime()
+
+	message, err := outbox_entity.NewMessage(s.updateOrderTopic, cloud.NewUpdateOrderContractFromPayment(payment), now)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := newEvent(request, payment, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if event == nil {
+		err = s.outboxRepository.Create(ctx, message)
+	} else {
+		err = s.inboxRepository.Create(ctx, event, message)
+	}
+	if err != nil {
+		if errors.Is(err, custom_error.ErrEventAlreadyProcessed) {
+			return s.processedEvent(ctx, request)
+		}
+
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// processedEvent answers an event delivered again with the payment returned when it was first
+// processed, along with ErrEventAlreadyProcessed so nothing is published a second time
+func (s *Service) processedEvent(ctx context.Context, request UpdatePaymentDTO) (*payment_entity.Payment, error) {
+	event, err := s.inboxRepository.GetByEventID(ctx, request.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.PaymentId != request.PaymentId {
+		slog.WarnContext(ctx, "event already processed for another payment", "event_id", event.Id, "payment_id", request.PaymentId, "event_payment_id", event.PaymentId)
+		return nil, custom_error.ErrEventAlreadyProcessed
+	}
+
+	var payment payment_entity.Payment
+
+	if err := event.DecodeResponse(&payment); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "event already processed, returning the original response", "event_id", event.Id, "payment_id", payment.PaymentId)
+
+	return &payment, custom_error.ErrEventAlreadyProcessed
+}
+
+func newEvent(request UpdatePaymentDTO, payment *payment_entity.Payment, now time.Time) (*inbox_entity.Event, error) {
+	if request.EventId == "" {
+		return nil, nil
+	}
+
+	event, err := inbox_entity.NewEvent(request.EventId, payment.PaymentId, payment, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/inbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
 	provider_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/provider/mocks"
 	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
@@ -23,6 +25,8 @@ func TestHandle(t *testing.T) {
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
 		repository.On("GetByID", ctx, mock.Anything).
 			Return(payment_entity.Payment{
@@ -34,15 +38,28 @@ func TestHandle(t *testing.T) {
 			Return(now).
 			Once()
 
-		repository.On("Update", ctx, mock.Anything).
+		repository.On("UpdateFromEvent", ctx,
+			mock.MatchedBy(func(payment *payment_entity.Payment) bool {
+				return len(payment.StateChanges) == 1 &&
+					payment.StateChanges[0].Source == payment_entity.SourceWebhook &&
+					payment.StateChanges[0].Payload == `{"approved":true}`
+			}),
+			(*inbox_entity.Event)(nil),
+			mock.MatchedBy(func(message outbox_entity.Message) bool {
+				return message.Topic == "OrderProductionTopic"
+			}),
+			mock.MatchedBy(func(message outbox_entity.Message) bool {
+				return message.Topic == "UpdateOrderTopic"
+			})).
 			Return(nil).
 			Once()
 
-		service := NewService(repository, timeProvider)
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: uuid.NewString(),
 			Approved:  true,
+			Payload:   `{"approved":true}`,
 		}
 
 		// Act
@@ -63,6 +80,8 @@ func TestHandle(t *testing.T) {
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
 		repository.On("GetByID", ctx, mock.Anything).
 			Return(payment_entity.Payment{
@@ -74,11 +93,14 @@ func TestHandle(t *testing.T) {
 			Return(now).
 			Once()
 
-		repository.On("Update", ctx, mock.Anything).
+		repository.On("UpdateFromEvent", ctx, mock.Anything, (*inbox_entity.Event)(nil),
+			mock.MatchedBy(func(message outbox_entity.Message) bool {
+				return message.Topic == "UpdateOrderTopic"
+			})).
 			Return(nil).
 			Once()
 
-		service := NewService(repository, timeProvider)
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: uuid.NewString(),
@@ -101,8 +123,10 @@ func TestHandle(t *testing.T) {
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
-		service := NewService(repository, timeProvider)
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: "abc",
@@ -124,12 +148,14 @@ func TestHandle(t *testing.T) {
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
 		repository.On("GetByID", ctx, mock.Anything).
 			Return(payment_entity.Payment{}, custom_error.ErrPaymentNotFound).
 			Once()
 
-		service := NewService(repository, timeProvider)
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: uuid.NewString(),
@@ -153,20 +179,24 @@ func TestHandle(t *testing.T) {
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
 		repository.On("GetByID", ctx, mock.Anything).
-			Return(payment_entity.Payment{}, nil).
+			Return(payment_entity.Payment{
+				State: payment_entity.WaitingForApproval,
+			}, nil).
 			Once()
 
 		timeProvider.On("GetTime").
 			Return(now).
 			Once()
 
-		repository.On("Update", ctx, mock.Anything).
+		repository.On("UpdateFromEvent", ctx, mock.Anything, (*inbox_entity.Event)(nil), mock.Anything, mock.Anything).
 			Return(assert.AnError).
 			Once()
 
-		service := NewService(repository, timeProvider)
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: uuid.NewString(),
@@ -183,12 +213,55 @@ func TestHandle(t *testing.T) {
 		timeProvider.AssertExpectations(t)
 	})
 
+	t.Run("Should return conflict when the payment was changed concurrently", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{
+				State:   payment_entity.WaitingForApproval,
+				Version: 1,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		repository.On("UpdateFromEvent", ctx, mock.Anything, (*inbox_entity.Event)(nil), mock.Anything, mock.Anything).
+			Return(custom_error.ErrPaymentConcurrentUpdate).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: uuid.NewString(),
+			Approved:  true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentConcurrentUpdate)
+		assert.Nil(t, payment)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
+
 	t.Run("Should not update the payment when the payment is already approved", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
 		repository.On("GetByID", ctx, mock.Anything).
 			Return(payment_entity.Payment{
@@ -196,7 +269,11 @@ func TestHandle(t *testing.T) {
 			}, nil).
 			Once()
 
-		service := NewService(repository, timeProvider)
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: uuid.NewString(),
@@ -207,7 +284,7 @@ func TestHandle(t *testing.T) {
 		payment, err := service.Handle(ctx, req)
 
 		// Assert
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, custom_error.ErrPaymentInvalidStateTransition)
 		assert.Nil(t, payment)
 		repository.AssertExpectations(t)
 		timeProvider.AssertExpectations(t)
@@ -219,6 +296,8 @@ func TestHandle(t *testing.T) {
 
 		repository := repository_mocks.NewMockPaymentRepository(t)
 		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
 
 		repository.On("GetByID", ctx, mock.Anything).
 			Return(payment_entity.Payment{
@@ -226,7 +305,11 @@ func TestHandle(t *testing.T) {
 			}, nil).
 			Once()
 
-		service := NewService(repository, timeProvider)
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
 
 		req := UpdatePaymentDTO{
 			PaymentId: uuid.NewString(),
@@ -236,10 +319,407 @@ func TestHandle(t *testing.T) {
 		// Act
 		payment, err := service.Handle(ctx, req)
 
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrPaymentInvalidStateTransition)
+		assert.Nil(t, payment)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+	})
+
+	t.Run("Should not approve a payment that was never sent to the gateway", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{
+				State: payment_entity.None,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: uuid.NewString(),
+			Approved:  true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		var transitionErr *payment_entity.InvalidTransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.Equal(t, payment_entity.None, transitionErr.From)
+		assert.Equal(t, payment_entity.Approved, transitionErr.To)
+		assert.Nil(t, payment)
+		repository.AssertNotCalled(t, "UpdateFromEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should schedule the update order message when a resend is requested", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		now := time.Now()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{
+				State: payment_entity.Approved,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(now).
+			Once()
+
+		outboxRepository.On("Create", ctx, mock.MatchedBy(func(message outbox_entity.Message) bool {
+			return message.Topic == "UpdateOrderTopic"
+		})).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: uuid.NewString(),
+			Resend:    true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, payment)
+		assert.Equal(t, "Approved", payment.StateTitle)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+		outboxRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error when the resend message cannot be scheduled", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		repository.On("GetByID", ctx, mock.Anything).
+			Return(payment_entity.Payment{
+				State: payment_entity.Approved,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		outboxRepository.On("Create", ctx, mock.Anything).
+			Return(assert.AnError).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: uuid.NewString(),
+			Resend:    true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
 		// Assert
 		assert.Error(t, err)
 		assert.Nil(t, payment)
 		repository.AssertExpectations(t)
 		timeProvider.AssertExpectations(t)
+		outboxRepository.AssertExpectations(t)
+	})
+}
+
+func TestHandleEvent(t *testing.T) {
+	t.Run("Should return the original payment when the event was already processed", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		paymentId := uuid.NewString()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		event, err := inbox_entity.NewEvent("event-1", paymentId, payment_entity.Payment{
+			PaymentId:  paymentId,
+			State:      payment_entity.Approved,
+			StateTitle: "Approved",
+		}, time.Now())
+		assert.NoError(t, err)
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(event, nil).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: paymentId,
+			EventId:   "event-1",
+			Approved:  false,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrEventAlreadyProcessed)
+		assert.NotNil(t, payment)
+		assert.Equal(t, paymentId, payment.PaymentId)
+		assert.Equal(t, payment_entity.Approved, payment.State)
+		repository.AssertExpectations(t)
+		inboxRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should record the event when the payment is updated", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		now := time.Now()
+		paymentId := uuid.NewString()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(inbox_entity.Event{}, custom_error.ErrEventNotFound).
+			Once()
+
+		repository.On("GetByID", ctx, paymentId).
+			Return(payment_entity.Payment{
+				PaymentId: paymentId,
+				State:     payment_entity.WaitingForApproval,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(now).
+			Once()
+
+		repository.On("UpdateFromEvent", ctx, mock.Anything, mock.MatchedBy(func(event *inbox_entity.Event) bool {
+			return event.Id == "event-1" && event.PaymentId == paymentId && event.ProcessedAt.Equal(now)
+		}), mock.Anything).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: paymentId,
+			EventId:   "event-1",
+			Approved:  false,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, payment)
+		assert.Equal(t, payment_entity.Rejected, payment.State)
+		repository.AssertExpectations(t)
+		timeProvider.AssertExpectations(t)
+		inboxRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should return the original payment when a concurrent delivery processed the event first", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		paymentId := uuid.NewString()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		event, err := inbox_entity.NewEvent("event-1", paymentId, payment_entity.Payment{
+			PaymentId: paymentId,
+			State:     payment_entity.Approved,
+		}, time.Now())
+		assert.NoError(t, err)
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(inbox_entity.Event{}, custom_error.ErrEventNotFound).
+			Once()
+
+		repository.On("GetByID", ctx, paymentId).
+			Return(payment_entity.Payment{
+				PaymentId: paymentId,
+				State:     payment_entity.WaitingForApproval,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		repository.On("UpdateFromEvent", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(custom_error.ErrEventAlreadyProcessed).
+			Once()
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(event, nil).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: paymentId,
+			EventId:   "event-1",
+			Approved:  true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrEventAlreadyProcessed)
+		assert.NotNil(t, payment)
+		assert.Equal(t, payment_entity.Approved, payment.State)
+		repository.AssertExpectations(t)
+		inboxRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should record the event with the message when a resend is requested", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		paymentId := uuid.NewString()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(inbox_entity.Event{}, custom_error.ErrEventNotFound).
+			Once()
+
+		repository.On("GetByID", ctx, paymentId).
+			Return(payment_entity.Payment{
+				PaymentId: paymentId,
+				State:     payment_entity.Approved,
+			}, nil).
+			Once()
+
+		timeProvider.On("GetTime").
+			Return(time.Now()).
+			Once()
+
+		inboxRepository.On("Create", ctx, mock.MatchedBy(func(event *inbox_entity.Event) bool {
+			return event.Id == "event-1"
+		}), mock.MatchedBy(func(message outbox_entity.Message) bool {
+			return message.Topic == "UpdateOrderTopic"
+		})).
+			Return(nil).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: paymentId,
+			EventId:   "event-1",
+			Resend:    true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, payment)
+		repository.AssertExpectations(t)
+		inboxRepository.AssertExpectations(t)
+		outboxRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error when the event was processed for another payment", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		event, err := inbox_entity.NewEvent("event-1", uuid.NewString(), payment_entity.Payment{}, time.Now())
+		assert.NoError(t, err)
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(event, nil).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: uuid.NewString(),
+			EventId:   "event-1",
+			Approved:  true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, custom_error.ErrEventAlreadyProcessed)
+		assert.Nil(t, payment)
+		inboxRepository.AssertExpectations(t)
+	})
+
+	t.Run("Should return an error when the inbox cannot be read", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		outboxRepository := repository_mocks.NewMockOutboxRepository(t)
+		inboxRepository := repository_mocks.NewMockInboxRepository(t)
+
+		inboxRepository.On("GetByEventID", ctx, "event-1").
+			Return(inbox_entity.Event{}, assert.AnError).
+			Once()
+
+		service := NewService(repository, outboxRepository, inboxRepository, timeProvider, "OrderProductionTopic", "UpdateOrderTopic")
+
+		req := UpdatePaymentDTO{
+			PaymentId: uuid.NewString(),
+			EventId:   "event-1",
+			Approved:  true,
+		}
+
+		// Act
+		payment, err := service.Handle(ctx, req)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, payment)
+		inboxRepository.AssertExpectations(t)
 	})
 }
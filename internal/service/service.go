@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/refund_entity"
 )
 
 type CreatePaymentService[T any] interface {
@@ -18,10 +19,22 @@ type GetPaymentsByOrderIDService[T any] interface {
 	Handle(ctx context.Context, request T) ([]payment_entity.Payment, error)
 }
 
+type SearchPaymentsService[T any] interface {
+	Handle(ctx context.Context, request T) (payment_entity.SearchPage, error)
+}
+
+type GetPaymentHistoryService[T any] interface {
+	Handle(ctx context.Context, request T) ([]payment_entity.StateChange, error)
+}
+
 type UpdatePaymentService[T any] interface {
 	Handle(ctx context.Context, request T) (*payment_entity.Payment, error)
 }
 
+type RefundPaymentService[T any] interface {
+	Handle(ctx context.Context, request T) (*refund_entity.Refund, error)
+}
+
 // ---
 
 type CreatePaymentGatewayService[T any] interface {
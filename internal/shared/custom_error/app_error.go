@@ -1,6 +1,10 @@
 package custom_error
 
-import "github.com/labstack/echo/v4"
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
 
 type AppError struct {
 	Code    int    `json:"code"`
@@ -18,7 +22,11 @@ func NewHttpAppError(code int, message string, err error) *echo.HTTPError {
 	return echo.NewHTTPError(code, appError)
 }
 
+// NewHttpAppErrorFromBusinessError maps the business error wrapped by err,
+// the details keep the message of err so the context added by the wrappers is not lost
 func NewHttpAppErrorFromBusinessError(err error) *echo.HTTPError {
-	buErr := err.(BusinessError)
+	var buErr BusinessError
+	errors.As(err, &buErr)
+
 	return NewHttpAppError(buErr.Code(), buErr.Title(), err)
 }
@@ -2,6 +2,7 @@ package custom_error
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,4 +43,20 @@ func TestNewHttpAppErrorFromBusinessError(t *testing.T) {
 			Details: "error",
 		}, err.Message)
 	})
+
+	t.Run("Should return an HTTP error from a wrapped business error", func(t *testing.T) {
+		// Arrange
+		wrapped := fmt.Errorf("context: %w", New(123, "error", "error"))
+
+		// Act
+		err := NewHttpAppErrorFromBusinessError(wrapped)
+
+		// Assert
+		assert.Equal(t, 123, err.Code)
+		assert.Equal(t, AppError{
+			Code:    123,
+			Message: "error",
+			Details: "context: error",
+		}, err.Message)
+	})
 }
@@ -1,5 +1,7 @@
 package custom_error
 
+import "errors"
+
 type BusinessError struct {
 	code    int
 	title   string
@@ -31,6 +33,6 @@ func IsBusinessErr(err error) bool {
 		return false
 	}
 
-	_, ok := err.(BusinessError)
-	return ok
+	var buErr BusinessError
+	return errors.As(err, &buErr)
 }
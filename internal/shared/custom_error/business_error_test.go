@@ -2,6 +2,7 @@ package custom_error
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,6 +20,17 @@ func TestIsBusinessErr(t *testing.T) {
 		assert.True(t, result)
 	})
 
+	t.Run("Should return true when error wraps a business error", func(t *testing.T) {
+		// Arrange
+		err := fmt.Errorf("context: %w", New(123, "error", "error"))
+
+		// Act
+		result := IsBusinessErr(err)
+
+		// Assert
+		assert.True(t, result)
+	})
+
 	t.Run("Should return false when error is not a business error", func(t *testing.T) {
 		// Arrange
 		err := errors.New("error")
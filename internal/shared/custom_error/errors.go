@@ -4,6 +4,7 @@ import "net/http"
 
 var (
 	ErrRequestNotValid BusinessError = New(http.StatusUnprocessableEntity, "validation error", "request not valid, please check the fields")
+	ErrCursorNotValid  BusinessError = New(http.StatusUnprocessableEntity, "validation error", "cursor not valid, please restart the search")
 
 	ErrOrderInvalidStateTransition BusinessError = New(http.StatusBadRequest, "unable to update order state", "invalid state transition")
 	ErrOrderNotFound               BusinessError = New(http.StatusNotFound, "unable to find the order", "order not found")
@@ -22,5 +23,17 @@ var (
 	ErrPaymentNotFound               BusinessError = New(http.StatusNotFound, "unable to find the payment", "payment not found")
 	ErrPaymentInvalidStateTransition BusinessError = New(http.StatusBadRequest, "unable to update payment state", "invalid state transition")
 	ErrPaymentAlreadyExists          BusinessError = New(http.StatusConflict, "unable to create the payment", "payment already exists")
-	ErrPaymentAlreadyInState         BusinessError = New(http.StatusBadRequest, "unable to update payment state", "payment is already approved or rejected")
+	ErrPaymentAmountMismatch         BusinessError = New(http.StatusUnprocessableEntity, "unable to create the payment", "total items or amount do not match the items")
+	ErrPaymentConcurrentUpdate       BusinessError = New(http.StatusConflict, "unable to update the payment", "payment was changed by another request, please retry")
+	ErrPaymentNotRefundable          BusinessError = New(http.StatusBadRequest, "unable to refund the payment", "payment is not approved or was already fully refunded")
+	ErrRefundAmountExceeded          BusinessError = New(http.StatusUnprocessableEntity, "unable to refund the payment", "refund amount exceeds the remaining amount of the payment")
+	ErrRefundNotFound                BusinessError = New(http.StatusNotFound, "unable to find the refund", "refund not found")
+	ErrRefundNotPending              BusinessError = New(http.StatusConflict, "unable to settle the refund", "refund was already settled")
+
+	ErrEventNotFound         BusinessError = New(http.StatusNotFound, "unable to find the event", "event not found")
+	ErrEventAlreadyProcessed BusinessError = New(http.StatusConflict, "unable to process the event", "event already processed")
+
+	ErrGatewayRequestNotValid BusinessError = New(http.StatusUnprocessableEntity, "unable to process the gateway request", "request rejected by the gateway")
+	ErrGatewayUnauthorized    BusinessError = New(http.StatusBadGateway, "unable to process the gateway request", "gateway credentials rejected")
+	ErrGatewayUnavailable     BusinessError = New(http.StatusServiceUnavailable, "unable to process the gateway request", "gateway is unavailable")
 )
@@ -0,0 +1,137 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCheckTimedOut = errors.New("health check timed out")
+
+// Component is a named check aggregated by the Checker
+type Component struct {
+	Name  string
+	Check HealthCheck
+}
+
+type Report struct {
+	Status     string                   `json:"status"`
+	Components map[string]*HealthStatus `json:"components"`
+}
+
+func (r *Report) HasError() bool {
+	return r.Status != StatusHealthy
+}
+
+type cachedStatus struct {
+	status    *HealthStatus
+	expiresAt time.Time
+}
+
+// Checker runs the checks of the components concurrently, each one bounded by the timeout,
+// and keeps their results for the cache ttl so the probes do not hammer the dependencies
+type Checker struct {
+	components []Component
+	timeout    time.Duration
+	cacheTtl   time.Duration
+	now        func() time.Time
+
+	cache      map[string]cachedStatus
+	cacheMutex sync.Mutex
+}
+
+func NewChecker(timeout time.Duration, cacheTtl time.Duration, components ...Component) *Checker {
+	return &Checker{
+		components: components,
+		timeout:    timeout,
+		cacheTtl:   cacheTtl,
+		now:        time.Now,
+
+		cache: make(map[string]cachedStatus),
+	}
+}
+
+func (c *Checker) Check(ctx context.Context) *Report {
+	report := &Report{
+		Status:     StatusHealthy,
+		Components: make(map[string]*HealthStatus, len(c.components)),
+	}
+
+	statuses := make([]*HealthStatus, len(c.components))
+
+	var wg sync.WaitGroup
+
+	for i, component := range c.components {
+		wg.Add(1)
+
+		go func(i int, component Component) {
+			defer wg.Done()
+
+			statuses[i] = c.checkComponent(ctx, component)
+		}(i, component)
+	}
+
+	wg.Wait()
+
+	for i, component := range c.components {
+		report.Components[component.Name] = statuses[i]
+
+		if statuses[i].HasError() {
+			report.Status = StatusUnhealthy
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) checkComponent(ctx context.Context, component Component) *HealthStatus {
+	if status, ok := c.cached(component.Name); ok {
+		return status
+	}
+
+	// the result is shared by the next probes, so the check is not bound to the probe that
+	// ran it, a probe hanging up would otherwise cache its cancellation as the status
+	status := c.runWithTimeout(context.WithoutCancel(ctx), component.Check)
+
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	c.cache[component.Name] = cachedStatus{
+		status:    status,
+		expiresAt: c.now().Add(c.cacheTtl),
+	}
+
+	return status
+}
+
+func (c *Checker) cached(name string) (*HealthStatus, bool) {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	cached, ok := c.cache[name]
+	if !ok || !c.now().Before(cached.expiresAt) {
+		return nil, false
+	}
+
+	return cached.status, true
+}
+
+// runWithTimeout does not wait for a check that ignores its context past the timeout
+func (c *Checker) runWithTimeout(ctx context.Context, check HealthCheck) *HealthStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	result := make(chan *HealthStatus, 1)
+
+	go func() {
+		result <- check.Health(ctx)
+	}()
+
+	select {
+	case status := <-result:
+		return status
+	case <-ctx.Done():
+		return Unhealthy(ErrCheckTimedOut)
+	}
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countingCheck(calls *int32, status *HealthStatus) HealthCheck {
+	return CheckFunc(func(ctx context.Context) *HealthStatus {
+		atomic.AddInt32(calls, 1)
+		return status
+	})
+}
+
+func TestChecker(t *testing.T) {
+	t.Run("Should report healthy when every component is healthy", func(t *testing.T) {
+		// Arrange
+		checker := NewChecker(time.Second, time.Minute,
+			Component{Name: "database", Check: CheckFunc(func(ctx context.Context) *HealthStatus { return Healthy() })},
+			Component{Name: "queue", Check: CheckFunc(func(ctx context.Context) *HealthStatus { return Healthy() })},
+		)
+
+		// Act
+		report := checker.Check(context.Background())
+
+		// Assert
+		assert.False(t, report.HasError())
+		assert.Equal(t, StatusHealthy, report.Status)
+		assert.Equal(t, map[string]*HealthStatus{
+			"database": Healthy(),
+			"queue":    Healthy(),
+		}, report.Components)
+	})
+
+	t.Run("Should report unhealthy with the detail of the failed component", func(t *testing.T) {
+		// Arrange
+		checker := NewChecker(time.Second, time.Minute,
+			Component{Name: "database", Check: CheckFunc(func(ctx context.Context) *HealthStatus { return Healthy() })},
+			Component{Name: "queue", Check: CheckFunc(func(ctx context.Context) *HealthStatus { return Unhealthy(errors.New("queue not found")) })},
+		)
+
+		// Act
+		report := checker.Check(context.Background())
+
+		// Assert
+		assert.True(t, report.HasError())
+		assert.Equal(t, StatusHealthy, report.Components["database"].Status)
+		assert.Equal(t, StatusUnhealthy, report.Components["queue"].Status)
+		assert.Equal(t, "queue not found", report.Components["queue"].Err)
+	})
+
+	t.Run("Should report unhealthy when a check does not finish in time", func(t *testing.T) {
+		// Arrange
+		release := make(chan struct{})
+		defer close(release)
+
+		checker := NewChecker(10*time.Millisecond, time.Minute,
+			Component{Name: "topic", Check: CheckFunc(func(ctx context.Context) *HealthStatus {
+				<-release
+				return Healthy()
+			})},
+		)
+
+		// Act
+		report := checker.Check(context.Background())
+
+		// Assert
+		assert.True(t, report.HasError())
+		assert.Equal(t, ErrCheckTimedOut.Error(), report.Components["topic"].Err)
+	})
+
+	t.Run("Should not cache the cancellation of the probe that ran the check", func(t *testing.T) {
+		// Arrange
+		var calls int32
+
+		checker := NewChecker(time.Second, time.Minute,
+			Component{Name: "database", Check: CheckFunc(func(ctx context.Context) *HealthStatus {
+				atomic.AddInt32(&calls, 1)
+
+				if err := ctx.Err(); err != nil {
+					return Unhealthy(err)
+				}
+
+				return Healthy()
+			})},
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		canceled := checker.Check(ctx)
+		next := checker.Check(context.Background())
+
+		// Assert
+		assert.False(t, canceled.HasError())
+		assert.False(t, next.HasError())
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Should reuse the results until the cache expires", func(t *testing.T) {
+		// Arrange
+		var calls int32
+
+		now := time.Now()
+
+		checker := NewChecker(time.Second, time.Minute,
+			Component{Name: "database", Check: countingCheck(&calls, Healthy())},
+		)
+		checker.now = func() time.Time { return now }
+
+		// Act
+		checker.Check(context.Background())
+		checker.Check(context.Background())
+
+		now = now.Add(time.Minute)
+
+		checker.Check(context.Background())
+
+		// Assert
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
@@ -1,7 +1,21 @@
 package health
 
+import "context"
+
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
 type HealthCheck interface {
-	Health() *HealthStatus
+	Health(ctx context.Context) *HealthStatus
+}
+
+// CheckFunc adapts a function to a HealthCheck
+type CheckFunc func(ctx context.Context) *HealthStatus
+
+func (f CheckFunc) Health(ctx context.Context) *HealthStatus {
+	return f(ctx)
 }
 
 type HealthStatus struct {
@@ -9,6 +23,19 @@ type HealthStatus struct {
 	Err    string `json:"err,omitempty"`
 }
 
+func Healthy() *HealthStatus {
+	return &HealthStatus{
+		Status: StatusHealthy,
+	}
+}
+
+func Unhealthy(err error) *HealthStatus {
+	return &HealthStatus{
+		Status: StatusUnhealthy,
+		Err:    err.Error(),
+	}
+}
+
 func (h *HealthStatus) HasError() bool {
 	return h.Err != ""
 }
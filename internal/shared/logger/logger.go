@@ -32,6 +32,6 @@ func SetupLog(config *environment.Config) {
 		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
-	log := slog.New(handler)
+	log := slog.New(NewTraceHandler(handler))
 	slog.SetDefault(log)
 }
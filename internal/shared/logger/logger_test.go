@@ -21,7 +21,7 @@ func TestSetupLog(t *testing.T) {
 		SetupLog(config)
 
 		// Assert
-		assert.IsType(t, &slog.TextHandler{}, slog.Default().Handler())
+		assert.IsType(t, &slog.TextHandler{}, slog.Default().Handler().(*TraceHandler).Handler)
 	})
 
 	t.Run("Should setup log when is not development", func(t *testing.T) {
@@ -36,6 +36,6 @@ func TestSetupLog(t *testing.T) {
 		SetupLog(config)
 
 		// Assert
-		assert.IsType(t, &slog.JSONHandler{}, slog.Default().Handler())
+		assert.IsType(t, &slog.JSONHandler{}, slog.Default().Handler().(*TraceHandler).Handler)
 	})
 }
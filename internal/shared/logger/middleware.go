@@ -1,7 +1,6 @@
 package logger
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"runtime/debug"
@@ -27,7 +26,7 @@ func Middleware() echo.MiddlewareFunc {
 		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
 			if v.Error != nil {
 				child.LogAttrs(
-					context.Background(),
+					c.Request().Context(),
 					slog.LevelError,
 					"request error",
 					slog.String("uri", v.URI),
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHandler adds the trace and span ids of the context to the records
+type TraceHandler struct {
+	slog.Handler
+}
+
+func NewTraceHandler(handler slog.Handler) *TraceHandler {
+	return &TraceHandler{
+		Handler: handler,
+	}
+}
+
+func (h *TraceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewTraceHandler(h.Handler.WithAttrs(attrs))
+}
+
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	return NewTraceHandler(h.Handler.WithGroup(name))
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandler(t *testing.T) {
+	t.Run("Should add the trace and span ids of the context", func(t *testing.T) {
+		// Arrange
+		var buffer bytes.Buffer
+
+		log := slog.New(NewTraceHandler(slog.NewJSONHandler(&buffer, nil))).With("key", "value")
+
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{0x01},
+			SpanID:  trace.SpanID{0x02},
+		})
+
+		ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+		// Act
+		log.InfoContext(ctx, "message")
+
+		// Assert
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+		assert.Equal(t, spanContext.TraceID().String(), record["trace_id"])
+		assert.Equal(t, spanContext.SpanID().String(), record["span_id"])
+		assert.Equal(t, "value", record["key"])
+	})
+
+	t.Run("Should not add the ids when there is no span", func(t *testing.T) {
+		// Arrange
+		var buffer bytes.Buffer
+
+		log := slog.New(NewTraceHandler(slog.NewJSONHandler(&buffer, nil)))
+
+		// Act
+		log.InfoContext(context.Background(), "message")
+
+		// Assert
+		assert.NotContains(t, buffer.String(), "trace_id")
+	})
+}
@@ -0,0 +1,318 @@
+// Package money represents amounts as an integer number of minor units of an ISO 4217 currency.
+//
+// Rounding rules: decimal values given with more digits than the currency allows are rounded
+// to the nearest minor unit, halves away from zero (0.125 BRL is 0.13, -0.125 BRL is -0.13).
+// Legacy float values are read through their shortest decimal representation before rounding,
+// so 59.980000000000004 becomes 59.98. Arithmetic between amounts is exact and never rounds.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type Currency string
+
+const (
+	BRL Currency = "BRL"
+	EUR Currency = "EUR"
+	JPY Currency = "JPY"
+	USD Currency = "USD"
+)
+
+// DefaultCurrency is used by the codecs when the value does not carry a currency,
+// every payment is charged in it
+const DefaultCurrency = BRL
+
+// exponents holds the number of minor unit digits of each supported currency
+var exponents = map[Currency]int{
+	BRL: 2,
+	EUR: 2,
+	JPY: 0,
+	USD: 2,
+}
+
+var (
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(code))
+
+	if _, ok := exponents[currency]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedCurrency, code)
+	}
+
+	return currency, nil
+}
+
+// Exponent returns the number of minor unit digits, the default currency is assumed when empty
+func (c Currency) Exponent() int {
+	return exponents[c.orDefault()]
+}
+
+func (c Currency) orDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+
+	return c
+}
+
+// Money is an amount of minor units in a currency, the zero value is zero in the default currency
+type Money struct {
+	minor int64
+
+	// currency is left empty for the default currency,
+	// so the zero value equals New(0, DefaultCurrency)
+	currency Currency
+}
+
+// New returns the amount of minor units in the currency, like cents for BRL
+func New(minor int64, currency Currency) Money {
+	if currency == DefaultCurrency {
+		currency = ""
+	}
+
+	return Money{
+		minor:    minor,
+		currency: currency,
+	}
+}
+
+// Parse reads a decimal amount in major units, like "59.98", rounding it to the minor unit
+func Parse(value string, currency Currency) (Money, error) {
+	currency = currency.orDefault()
+
+	if _, ok := exponents[currency]; !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+
+	value = strings.TrimSpace(value)
+
+	// big.Rat also reads fractions and hexadecimal numbers, only decimals are amounts
+	if value == "" || strings.ContainsFunc(value, func(r rune) bool {
+		return !strings.ContainsRune("0123456789.+-eE", r)
+	}) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Exponent())), nil)
+	amount.Mul(amount, new(big.Rat).SetInt(scale))
+
+	minor, err := roundHalfAwayFromZero(amount)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", err, value)
+	}
+
+	return New(minor, currency), nil
+}
+
+// FromFloat reads a legacy float amount in major units, rounding it to the minor unit
+func FromFloat(value float64, currency Currency) (Money, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidAmount, value)
+	}
+
+	return Parse(strconv.FormatFloat(value, 'f', -1, 64), currency)
+}
+
+func roundHalfAwayFromZero(amount *big.Rat) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(amount.Num(), amount.Denom(), new(big.Int))
+
+	if remainder.Sign() != 0 {
+		twice := new(big.Int).Abs(remainder)
+		twice.Lsh(twice, 1)
+
+		if twice.Cmp(amount.Denom()) >= 0 {
+			quotient.Add(quotient, big.NewInt(int64(amount.Sign())))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrInvalidAmount
+	}
+
+	return quotient.Int64(), nil
+}
+
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+func (m Money) Currency() Currency {
+	return m.currency.orDefault()
+}
+
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.minor > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Add returns the sum of the amounts, which must be in the same currency
+func (m Money) Add(other Money) Money {
+	m.mustMatch(other)
+
+	return New(m.minor+other.minor, m.Currency())
+}
+
+// Sub returns the difference of the amounts, which must be in the same currency
+func (m Money) Sub(other Money) Money {
+	m.mustMatch(other)
+
+	return New(m.minor-other.minor, m.Currency())
+}
+
+// Multiply returns the amount times the factor, like the unit price times the quantity
+func (m Money) Multiply(factor int64) Money {
+	return New(m.minor*factor, m.Currency())
+}
+
+// Cmp returns -1, 0 or +1 when the amount is less than, equal to or greater than the other
+func (m Money) Cmp(other Money) int {
+	m.mustMatch(other)
+
+	switch {
+	case m.minor < other.minor:
+		return -1
+	case m.minor > other.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// mustMatch panics when the currencies differ, the amounts of a payment
+// are always decoded in its currency so this is a programming error
+func (m Money) mustMatch(other Money) {
+	if m.Currency() != other.Currency() {
+		panic(fmt.Sprintf("money: mixing %s and %s", m.Currency(), other.Currency()))
+	}
+}
+
+// String returns the amount in major units with every minor unit digit, like "59.98"
+func (m Money) String() string {
+	exponent := m.Currency().Exponent()
+
+	sign := ""
+	minor := m.minor
+	if minor < 0 {
+		sign = "-"
+	}
+
+	digits := strconv.FormatUint(uint64(abs(minor)), 10)
+
+	if exponent == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func abs(value int64) uint64 {
+	if value < 0 {
+		return uint64(-(value + 1)) + 1
+	}
+
+	return uint64(value)
+}
+
+// MarshalJSON writes the amount as a number in major units, like 59.98
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a number or a string in major units, legacy floats included,
+// in the currency already set on the value or in the default currency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	value := string(data)
+
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := Parse(value, m.currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// Value stores the amount as a decimal in major units, matching the DECIMAL columns
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads a decimal, a float or an integer in major units,
+// in the currency already set on the value or in the default currency
+func (m *Money) Scan(src interface{}) error {
+	var (
+		parsed Money
+		err    error
+	)
+
+	switch value := src.(type) {
+	case nil:
+		parsed = New(0, m.currency)
+	case []byte:
+		parsed, err = Parse(string(value), m.currency)
+	case string:
+		parsed, err = Parse(value, m.currency)
+	case float64:
+		parsed, err = FromFloat(value, m.currency)
+	case int64:
+		parsed, err = Parse(strconv.FormatInt(value, 10), m.currency)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// ValidatorValue exposes the minor units to the validator, so the amounts
+// can use numeric tags like "required,gt=0" once registered with RegisterCustomTypeFunc
+func ValidatorValue(field reflect.Value) interface{} {
+	if m, ok := field.Interface().(Money); ok {
+		return m.minor
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Should round to the minor unit with halves away from zero", func(t *testing.T) {
+		// Arrange
+		cases := map[string]int64{
+			"59.98":              5998,
+			"59.980000000000004": 5998,
+			"0.125":              13,
+			"-0.125":             -13,
+			"0.124":              12,
+			"10":                 1000,
+			"1e2":                10000,
+			"0.005":              1,
+			"-0.004":             0,
+		}
+
+		for value, expected := range cases {
+			// Act
+			amount, err := Parse(value, BRL)
+
+			// Assert
+			assert.NoError(t, err, value)
+			assert.Equal(t, expected, amount.Minor(), value)
+			assert.Equal(t, BRL, amount.Currency(), value)
+		}
+	})
+
+	t.Run("Should use the exponent of the currency", func(t *testing.T) {
+		// Act
+		amount, err := Parse("1234.5", JPY)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1235), amount.Minor())
+		assert.Equal(t, "1235", amount.String())
+	})
+
+	t.Run("Should return error when the value is not a decimal", func(t *testing.T) {
+		for _, value := range []string{"", "abc", "1/3", "0x10", "1.2.3", "NaN"} {
+			// Act
+			_, err := Parse(value, BRL)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidAmount, value)
+		}
+	})
+
+	t.Run("Should return error when the currency is not supported", func(t *testing.T) {
+		// Act
+		_, err := Parse("10", Currency("XXX"))
+
+		// Assert
+		assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	})
+}
+
+func TestFromFloat(t *testing.T) {
+	t.Run("Should read a legacy float through its shortest representation", func(t *testing.T) {
+		// Act
+		amount, err := FromFloat(19.99+39.99, BRL)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, New(5998, BRL), amount)
+	})
+
+	t.Run("Should return error when the float is not finite", func(t *testing.T) {
+		for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			// Act
+			_, err := FromFloat(value, BRL)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidAmount)
+		}
+	})
+}
+
+func TestArithmetic(t *testing.T) {
+	t.Run("Should add and subtract exactly", func(t *testing.T) {
+		// Arrange
+		amount := New(10, BRL)
+
+		for i := 0; i < 9; i++ {
+			amount = amount.Add(New(10, BRL))
+		}
+
+		// Act
+		remaining := New(100, BRL).Sub(amount)
+
+		// Assert
+		assert.Equal(t, New(100, BRL), amount)
+		assert.True(t, remaining.IsZero())
+		assert.Equal(t, 0, amount.Cmp(New(100, BRL)))
+		assert.Equal(t, -1, remaining.Cmp(amount))
+		assert.Equal(t, 1, amount.Cmp(remaining))
+	})
+
+	t.Run("Should multiply keeping the currency", func(t *testing.T) {
+		// Act
+		amount := New(1999, USD).Multiply(3)
+
+		// Assert
+		assert.Equal(t, New(5997, USD), amount)
+	})
+
+	t.Run("Should treat the zero value as the default currency", func(t *testing.T) {
+		// Act
+		amount := Money{}.Add(New(150, DefaultCurrency))
+
+		// Assert
+		assert.Equal(t, New(0, DefaultCurrency), Money{})
+		assert.Equal(t, DefaultCurrency, amount.Currency())
+		assert.Equal(t, "1.50", amount.String())
+	})
+
+	t.Run("Should panic when mixing currencies", func(t *testing.T) {
+		// Act & Assert
+		assert.Panics(t, func() {
+			New(100, BRL).Add(New(100, USD))
+		})
+	})
+}
+
+func TestString(t *testing.T) {
+	t.Run("Should write every minor unit digit", func(t *testing.T) {
+		// Arrange
+		cases := map[string]Money{
+			"59.98":  New(5998, BRL),
+			"0.05":   New(5, BRL),
+			"-0.50":  New(-50, BRL),
+			"100.00": New(10000, USD),
+			"0.00":   {},
+		}
+
+		for expected, amount := range cases {
+			// Act
+			value := amount.String()
+
+			// Assert
+			assert.Equal(t, expected, value)
+		}
+	})
+}
+
+func TestJSON(t *testing.T) {
+	type payload struct {
+		Amount Money `json:"amount"`
+	}
+
+	t.Run("Should write the amount as a number in major units", func(t *testing.T) {
+		// Act
+		data, err := json.Marshal(payload{Amount: New(5998, BRL)})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"amount":59.98}`, string(data))
+	})
+
+	t.Run("Should read legacy floats, numbers and strings", func(t *testing.T) {
+		for _, data := range []string{`{"amount":59.980000000000004}`, `{"amount":59.98}`, `{"amount":"59.98"}`} {
+			// Arrange
+			var p payload
+
+			// Act
+			err := json.Unmarshal([]byte(data), &p)
+
+			// Assert
+			assert.NoError(t, err, data)
+			assert.Equal(t, New(5998, BRL), p.Amount, data)
+		}
+	})
+
+	t.Run("Should keep the currency already set on the value", func(t *testing.T) {
+		// Arrange
+		p := payload{Amount: New(0, JPY)}
+
+		// Act
+		err := json.Unmarshal([]byte(`{"amount":1500.4}`), &p)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, New(1500, JPY), p.Amount)
+	})
+
+	t.Run("Should return error when the amount is not a number", func(t *testing.T) {
+		// Arrange
+		var p payload
+
+		// Act
+		err := json.Unmarshal([]byte(`{"amount":true}`), &p)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+	})
+}
+
+func TestSQL(t *testing.T) {
+	t.Run("Should store the amount as a decimal", func(t *testing.T) {
+		// Act
+		value, err := New(5998, BRL).Value()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "59.98", value)
+	})
+
+	t.Run("Should scan decimals, legacy floats and integers", func(t *testing.T) {
+		// Arrange
+		cases := []interface{}{[]byte("59.98"), "59.98", 59.980000000000004}
+
+		for _, src := range cases {
+			var amount Money
+
+			// Act
+			err := amount.Scan(src)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, New(5998, BRL), amount)
+		}
+
+		var amount Money
+
+		assert.NoError(t, amount.Scan(int64(12)))
+		assert.Equal(t, New(1200, BRL), amount)
+
+		assert.NoError(t, amount.Scan(nil))
+		assert.True(t, amount.IsZero())
+	})
+
+	t.Run("Should return error when the type cannot be scanned", func(t *testing.T) {
+		// Arrange
+		var amount Money
+
+		// Act
+		err := amount.Scan(true)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+	})
+}
+
+func TestValidatorValue(t *testing.T) {
+	t.Run("Should validate the minor units", func(t *testing.T) {
+		// Arrange
+		type request struct {
+			Amount Money `validate:"required,gt=0"`
+		}
+
+		validate := validator.New()
+		validate.RegisterCustomTypeFunc(ValidatorValue, Money{})
+
+		// Act
+		errValid := validate.Struct(request{Amount: New(1, BRL)})
+		errZero := validate.Struct(request{})
+		errNegative := validate.Struct(request{Amount: New(-1, BRL)})
+
+		// Assert
+		assert.NoError(t, errValid)
+		assert.Error(t, errZero)
+		assert.Error(t, errNegative)
+	})
+
+	t.Run("Should ignore other types", func(t *testing.T) {
+		// Act
+		value := ValidatorValue(reflect.ValueOf("value"))
+
+		// Assert
+		assert.Nil(t, value)
+	})
+}
+
+func TestParseCurrency(t *testing.T) {
+	t.Run("Should accept the supported ISO 4217 codes", func(t *testing.T) {
+		// Act
+		currency, err := ParseCurrency("usd")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, USD, currency)
+		assert.Equal(t, 2, currency.Exponent())
+	})
+
+	t.Run("Should return error when the code is not supported", func(t *testing.T) {
+		// Act
+		_, err := ParseCurrency("XYZ")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	})
+}
@@ -0,0 +1,137 @@
+package outbox_relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/tracing"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/worker"
+)
+
+type Relay struct {
+	repository   repository.OutboxRepository
+	timeProvider provider.TimeProvider
+	config       *environment.OutboxConfig
+
+	topics map[string]cloud.TopicService
+
+	runner *worker.Runner
+}
+
+func NewRelay(
+	repository repository.OutboxRepository,
+	timeProvider provider.TimeProvider,
+	config *environment.OutboxConfig,
+	topics ...cloud.TopicService,
+) *Relay {
+	relay := &Relay{
+		repository:   repository,
+		timeProvider: timeProvider,
+		config:       config,
+
+		topics: make(map[string]cloud.TopicService, len(topics)),
+	}
+
+	for _, topic := range topics {
+		relay.topics[topic.GetTopicName()] = topic
+	}
+
+	relay.runner = worker.NewRunner("outbox_relay", config.PollInterval, relay.relayBatch)
+
+	return relay
+}
+
+func (r *Relay) Start(ctx context.Context) {
+	r.runner.Start(ctx)
+}
+
+func (r *Relay) Stop(ctx context.Context) error {
+	return r.runner.Stop(ctx)
+}
+
+func (r *Relay) relayBatch(ctx context.Context) bool {
+	return r.relayMessages(ctx) == r.config.BatchSize
+}
+
+func (r *Relay) relayMessages(ctx context.Context) int {
+	now := r.timeProvider.GetTime()
+
+	messages, err := r.repository.ClaimPending(ctx, now, now.Add(r.config.LockTimeout), r.config.BatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "error claiming outbox messages", "error", err)
+		return 0
+	}
+
+	for _, message := range messages {
+		r.relayMessage(ctx, message)
+	}
+
+	return len(messages)
+}
+
+func (r *Relay) relayMessage(ctx context.Context, message outbox_entity.Message) {
+	// the publication belongs to the trace of the change that originated the message
+	ctx = tracing.Extract(ctx, message.TraceContext)
+
+	err := r.publish(ctx, message)
+	if err != nil {
+		nextAttemptAt := r.timeProvider.GetTime().Add(r.backoff(message.Attempts))
+
+		slog.ErrorContext(ctx, "error publishing outbox message",
+			"outbox_id", message.Id,
+			"topic", message.Topic,
+			"attempts", message.Attempts+1,
+			"next_attempt_at", nextAttemptAt,
+			"error", err)
+
+		message.MarkAsFailed(err, nextAttemptAt)
+
+		if err := r.repository.MarkAsFailed(ctx, &message); err != nil {
+			slog.ErrorContext(ctx, "error marking outbox message as failed", "outbox_id", message.Id, "error", err)
+		}
+
+		return
+	}
+
+	message.MarkAsSent(r.timeProvider.GetTime())
+
+	if err := r.repository.MarkAsSent(ctx, &message); err != nil {
+		slog.ErrorContext(ctx, "error marking outbox message as sent", "outbox_id", message.Id, "error", err)
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, message outbox_entity.Message) error {
+	topic, ok := r.topics[message.Topic]
+	if !ok {
+		return fmt.Errorf("topic %s is not registered in the outbox relay", message.Topic)
+	}
+
+	messageId, err := topic.PublishMessage(ctx, message.Id, json.RawMessage(message.Payload))
+	if err != nil {
+		return err
+	}
+
+	if messageId != nil {
+		slog.InfoContext(ctx, "outbox message published", "outbox_id", message.Id, "topic", message.Topic, "message_id", *messageId)
+	}
+
+	return nil
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.config.BaseBackoff
+
+	for i := 0; i < attempts && backoff < r.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, r.config.MaxBackoff)
+}
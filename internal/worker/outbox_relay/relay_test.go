@@ -0,0 +1,367 @@
+package outbox_relay
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud/mocks"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	provider_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/provider/mocks"
+	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newConfig() *environment.OutboxConfig {
+	return &environment.OutboxConfig{
+		PollInterval: time.Hour,
+		BatchSize:    10,
+		LockTimeout:  30 * time.Second,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+	}
+}
+
+func TestRelayMessages(t *testing.T) {
+	t.Run("Should publish the pending messages and mark them as sent", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		topic := mocks.NewMockTopicService(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		message, err := outbox_entity.NewMessage("topic", map[string]string{"order_id": "order_id"}, now)
+		assert.NoError(t, err)
+
+		timeProvider.On("GetTime").Return(now)
+
+		topic.On("GetTopicName").Return("topic").Once()
+
+		repository.On("ClaimPending", ctx, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{message}, nil).
+			Once()
+
+		topic.On("PublishMessage", ctx, message.Id, json.RawMessage(message.Payload)).
+			Return(nil, nil).
+			Once()
+
+		repository.On("MarkAsSent", ctx, mock.MatchedBy(func(m *outbox_entity.Message) bool {
+			return m.Id == message.Id && m.IsSent()
+		})).
+			Return(nil).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig(), topic)
+
+		// Act
+		claimed := relay.relayMessages(ctx)
+
+		// Assert
+		assert.Equal(t, 1, claimed)
+		repository.AssertExpectations(t)
+		topic.AssertExpectations(t)
+	})
+
+	t.Run("Should deduplicate the messages by their outbox id instead of their content", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		topic := mocks.NewMockTopicService(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		first, err := outbox_entity.NewMessage("topic", map[string]string{"order_id": "order_id"}, now)
+		assert.NoError(t, err)
+
+		second, err := outbox_entity.NewMessage("topic", map[string]string{"order_id": "order_id"}, now)
+		assert.NoError(t, err)
+
+		deduplicationIds := make([]string, 0, 2)
+
+		timeProvider.On("GetTime").Return(now)
+
+		topic.On("GetTopicName").Return("topic").Once()
+
+		repository.On("ClaimPending", ctx, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{first, second}, nil).
+			Once()
+
+		topic.On("PublishMessage", ctx, mock.Anything, json.RawMessage(first.Payload)).
+			Run(func(args mock.Arguments) {
+				deduplicationIds = append(deduplicationIds, args.String(1))
+			}).
+			Return(nil, nil).
+			Twice()
+
+		repository.On("MarkAsSent", ctx, mock.Anything).
+			Return(nil).
+			Twice()
+
+		relay := NewRelay(repository, timeProvider, newConfig(), topic)
+
+		// Act
+		claimed := relay.relayMessages(ctx)
+
+		// Assert
+		assert.Equal(t, 2, claimed)
+		assert.Equal(t, first.Payload, second.Payload)
+		assert.Equal(t, []string{first.Id, second.Id}, deduplicationIds)
+		assert.NotEqual(t, deduplicationIds[0], deduplicationIds[1])
+		repository.AssertExpectations(t)
+		topic.AssertExpectations(t)
+	})
+
+	t.Run("Should publish the message in the trace of the change that originated it", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		topic := mocks.NewMockTopicService(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		traceId := "0af7651916cd43dd8448eb211c80319c"
+
+		message, err := outbox_entity.NewMessage("topic", map[string]string{"order_id": "order_id"}, now)
+		assert.NoError(t, err)
+
+		message.TraceContext = `{"traceparent":"00-` + traceId + `-b7ad6b7169203331-01"}`
+
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		t.Cleanup(func() {
+			otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		})
+
+		timeProvider.On("GetTime").Return(now)
+
+		topic.On("GetTopicName").Return("topic").Once()
+
+		repository.On("ClaimPending", ctx, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{message}, nil).
+			Once()
+
+		topic.On("PublishMessage", mock.MatchedBy(func(ctx context.Context) bool {
+			return trace.SpanContextFromContext(ctx).TraceID().String() == traceId
+		}), message.Id, json.RawMessage(message.Payload)).
+			Return(nil, nil).
+			Once()
+
+		repository.On("MarkAsSent", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig(), topic)
+
+		// Act
+		claimed := relay.relayMessages(ctx)
+
+		// Assert
+		assert.Equal(t, 1, claimed)
+		repository.AssertExpectations(t)
+		topic.AssertExpectations(t)
+	})
+
+	t.Run("Should reschedule the message with backoff when the publication fails", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+		topic := mocks.NewMockTopicService(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		message, err := outbox_entity.NewMessage("topic", "payload", now)
+		assert.NoError(t, err)
+		message.Attempts = 2
+
+		timeProvider.On("GetTime").Return(now)
+
+		topic.On("GetTopicName").Return("topic").Once()
+
+		repository.On("ClaimPending", ctx, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{message}, nil).
+			Once()
+
+		topic.On("PublishMessage", ctx, message.Id, json.RawMessage(message.Payload)).
+			Return(nil, assert.AnError).
+			Once()
+
+		repository.On("MarkAsFailed", ctx, mock.MatchedBy(func(m *outbox_entity.Message) bool {
+			return m.Attempts == 3 &&
+				m.LastError == assert.AnError.Error() &&
+				m.NextAttemptAt.Equal(now.Add(4*time.Second)) &&
+				!m.IsSent()
+		})).
+			Return(nil).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig(), topic)
+
+		// Act
+		claimed := relay.relayMessages(ctx)
+
+		// Assert
+		assert.Equal(t, 1, claimed)
+		repository.AssertExpectations(t)
+		topic.AssertExpectations(t)
+	})
+
+	t.Run("Should mark the message as failed when the topic is unknown", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		message, err := outbox_entity.NewMessage("unknown", "payload", now)
+		assert.NoError(t, err)
+
+		timeProvider.On("GetTime").Return(now)
+
+		repository.On("ClaimPending", ctx, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{message}, nil).
+			Once()
+
+		repository.On("MarkAsFailed", ctx, mock.Anything).
+			Return(nil).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig())
+
+		// Act
+		claimed := relay.relayMessages(ctx)
+
+		// Assert
+		assert.Equal(t, 1, claimed)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should not relay anything when the messages cannot be claimed", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		timeProvider.On("GetTime").Return(now)
+
+		repository.On("ClaimPending", ctx, now, now.Add(30*time.Second), 10).
+			Return(nil, assert.AnError).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig())
+
+		// Act
+		claimed := relay.relayMessages(ctx)
+
+		// Assert
+		assert.Zero(t, claimed)
+		repository.AssertExpectations(t)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Should double the backoff for each attempt up to the maximum", func(t *testing.T) {
+		// Arrange
+		relay := NewRelay(nil, nil, newConfig())
+
+		// Act & Assert
+		assert.Equal(t, time.Second, relay.backoff(0))
+		assert.Equal(t, 2*time.Second, relay.backoff(1))
+		assert.Equal(t, 32*time.Second, relay.backoff(5))
+		assert.Equal(t, time.Minute, relay.backoff(6))
+		assert.Equal(t, time.Minute, relay.backoff(100))
+	})
+}
+
+func TestStartStop(t *testing.T) {
+	t.Run("Should relay on start and stop gracefully", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		ctx := context.Background()
+
+		now := time.Now()
+
+		claimed := make(chan struct{})
+
+		timeProvider.On("GetTime").Return(now)
+
+		repository.On("ClaimPending", mock.Anything, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{}, nil).
+			Run(func(args mock.Arguments) {
+				close(claimed)
+			}).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig())
+
+		// Act
+		relay.Start(ctx)
+
+		<-claimed
+
+		err := relay.Stop(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when the context expires before stopping", func(t *testing.T) {
+		// Arrange
+		repository := repository_mocks.NewMockOutboxRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		now := time.Now()
+
+		claiming := make(chan struct{})
+		release := make(chan struct{})
+
+		timeProvider.On("GetTime").Return(now)
+
+		repository.On("ClaimPending", mock.Anything, now, now.Add(30*time.Second), 10).
+			Return([]outbox_entity.Message{}, nil).
+			Run(func(args mock.Arguments) {
+				close(claiming)
+				<-release
+			}).
+			Once()
+
+		relay := NewRelay(repository, timeProvider, newConfig())
+
+		relay.Start(context.Background())
+
+		<-claiming
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		err := relay.Stop(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		assert.NoError(t, relay.Stop(context.Background()))
+	})
+}
@@ -0,0 +1,104 @@
+package payment_expiration
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/provider"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/worker"
+)
+
+type Expiration struct {
+	repository   repository.PaymentRepository
+	timeProvider provider.TimeProvider
+	config       *environment.ExpirationConfig
+
+	updateOrderTopic string
+
+	runner *worker.Runner
+}
+
+func NewExpiration(
+	repository repository.PaymentRepository,
+	timeProvider provider.TimeProvider,
+	config *environment.ExpirationConfig,
+	updateOrderTopic string,
+) *Expiration {
+	expiration := &Expiration{
+		repository:   repository,
+		timeProvider: timeProvider,
+		config:       config,
+
+		updateOrderTopic: updateOrderTopic,
+	}
+
+	expiration.runner = worker.NewRunner("payment_expiration", config.PollInterval, expiration.expireBatch)
+
+	return expiration
+}
+
+func (e *Expiration) Start(ctx context.Context) {
+	e.runner.Start(ctx)
+}
+
+func (e *Expiration) Stop(ctx context.Context) error {
+	return e.runner.Stop(ctx)
+}
+
+// expireBatch reports there is more work only when a full batch was expired,
+// so a batch that keeps failing waits for the next interval
+func (e *Expiration) expireBatch(ctx context.Context) bool {
+	return e.expirePayments(ctx) == e.config.BatchSize
+}
+
+func (e *Expiration) expirePayments(ctx context.Context) int {
+	createdBefore := e.timeProvider.GetTime().Add(-e.config.PaymentTtl)
+
+	payments, err := e.repository.GetOverdue(ctx, createdBefore, e.config.BatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "error getting overdue payments", "error", err)
+		return 0
+	}
+
+	expired := 0
+
+	for _, payment := range payments {
+		if err := e.expirePayment(ctx, &payment); err != nil {
+			slog.ErrorContext(ctx, "error expiring payment", "payment_id", payment.PaymentId, "error", err)
+			continue
+		}
+
+		expired++
+	}
+
+	return expired
+}
+
+func (e *Expiration) expirePayment(ctx context.Context, payment *payment_entity.Payment) error {
+	now := e.timeProvider.GetTime()
+
+	err := payment.TransitionTo(payment_entity.Expired, now, payment_entity.ChangeOrigin{
+		Source: payment_entity.SourceExpiration,
+	})
+	if err != nil {
+		return err
+	}
+
+	message, err := outbox_entity.NewMessage(e.updateOrderTopic, cloud.NewUpdateOrderContractFromPayment(payment), now)
+	if err != nil {
+		return err
+	}
+
+	if err := e.repository.Update(ctx, payment, message); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "payment expired, scheduling message to update order topic", "payment_id", payment.PaymentId, "created_at", payment.CreatedAt)
+
+	return nil
+}
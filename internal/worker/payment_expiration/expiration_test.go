@@ -0,0 +1,224 @@
+package payment_expiration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/cloud"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/environment"
+	provider_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/provider/mocks"
+	repository_mocks "github.com/jfelipearaujo-org/ms-payment-management/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newConfig() *environment.ExpirationConfig {
+	return &environment.ExpirationConfig{
+		PaymentTtl:   30 * time.Minute,
+		PollInterval: time.Hour,
+		BatchSize:    2,
+	}
+}
+
+func TestExpirePayments(t *testing.T) {
+	t.Run("Should expire the overdue payments and schedule the update order message", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		now := time.Now()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		timeProvider.On("GetTime").Return(now)
+
+		repository.On("GetOverdue", ctx, now.Add(-30*time.Minute), 2).
+			Return([]payment_entity.Payment{
+				{OrderId: "order_id", PaymentId: "payment_id", State: payment_entity.WaitingForApproval},
+			}, nil).
+			Once()
+
+		repository.On("Update", ctx,
+			mock.MatchedBy(func(payment *payment_entity.Payment) bool {
+				return payment.State == payment_entity.Expired && payment.UpdatedAt.Equal(now) &&
+					len(payment.StateChanges) == 1 &&
+					payment.StateChanges[0].Source == payment_entity.SourceExpiration
+			}),
+			mock.MatchedBy(func(message outbox_entity.Message) bool {
+				var contract cloud.UpdateOrderTopicContract
+				assert.NoError(t, json.Unmarshal([]byte(message.Payload), &contract))
+
+				return message.Topic == "UpdateOrderTopic" &&
+					contract.OrderId == "order_id" &&
+					contract.Payment.PaymentId == "payment_id" &&
+					contract.Payment.State == "Expired"
+			})).
+			Return(nil).
+			Once()
+
+		expiration := NewExpiration(repository, timeProvider, newConfig(), "UpdateOrderTopic")
+
+		// Act
+		expired := expiration.expirePayments(ctx)
+
+		// Assert
+		assert.Equal(t, 1, expired)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should keep expiring the batch when a payment fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		now := time.Now()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		timeProvider.On("GetTime").Return(now)
+
+		repository.On("GetOverdue", ctx, mock.Anything, 2).
+			Return([]payment_entity.Payment{
+				{OrderId: "order_id", PaymentId: "payment_id_1", State: payment_entity.WaitingForApproval},
+				{OrderId: "order_id", PaymentId: "payment_id_2", State: payment_entity.WaitingForApproval},
+			}, nil).
+			Once()
+
+		repository.On("Update", ctx, mock.MatchedBy(func(payment *payment_entity.Payment) bool {
+			return payment.PaymentId == "payment_id_1"
+		}), mock.Anything).
+			Return(assert.AnError).
+			Once()
+
+		repository.On("Update", ctx, mock.MatchedBy(func(payment *payment_entity.Payment) bool {
+			return payment.PaymentId == "payment_id_2"
+		}), mock.Anything).
+			Return(nil).
+			Once()
+
+		expiration := NewExpiration(repository, timeProvider, newConfig(), "UpdateOrderTopic")
+
+		// Act
+		more := expiration.expireBatch(ctx)
+
+		// Assert
+		assert.False(t, more)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should not update a payment that left the waiting state", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		timeProvider.On("GetTime").Return(time.Now())
+
+		repository.On("GetOverdue", ctx, mock.Anything, 2).
+			Return([]payment_entity.Payment{
+				{OrderId: "order_id", PaymentId: "payment_id_1", State: payment_entity.Approved},
+			}, nil).
+			Once()
+
+		expiration := NewExpiration(repository, timeProvider, newConfig(), "UpdateOrderTopic")
+
+		// Act
+		more := expiration.expireBatch(ctx)
+
+		// Assert
+		assert.False(t, more)
+		repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should report more work when a full batch was expired", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		timeProvider.On("GetTime").Return(time.Now())
+
+		repository.On("GetOverdue", ctx, mock.Anything, 2).
+			Return([]payment_entity.Payment{
+				{OrderId: "order_id", PaymentId: "payment_id_1", State: payment_entity.WaitingForApproval},
+				{OrderId: "order_id", PaymentId: "payment_id_2", State: payment_entity.WaitingForApproval},
+			}, nil).
+			Once()
+
+		repository.On("Update", ctx, mock.Anything, mock.Anything).
+			Return(nil).
+			Twice()
+
+		expiration := NewExpiration(repository, timeProvider, newConfig(), "UpdateOrderTopic")
+
+		// Act
+		more := expiration.expireBatch(ctx)
+
+		// Assert
+		assert.True(t, more)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should not expire anything when the overdue payments cannot be fetched", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		timeProvider.On("GetTime").Return(time.Now())
+
+		repository.On("GetOverdue", ctx, mock.Anything, 2).
+			Return(nil, assert.AnError).
+			Once()
+
+		expiration := NewExpiration(repository, timeProvider, newConfig(), "UpdateOrderTopic")
+
+		// Act
+		expired := expiration.expirePayments(ctx)
+
+		// Assert
+		assert.Zero(t, expired)
+		repository.AssertNotCalled(t, "Update")
+	})
+}
+
+func TestStartStop(t *testing.T) {
+	t.Run("Should expire on start and stop gracefully", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		repository := repository_mocks.NewMockPaymentRepository(t)
+		timeProvider := provider_mocks.NewMockTimeProvider(t)
+
+		fetched := make(chan struct{})
+
+		timeProvider.On("GetTime").Return(time.Now())
+
+		repository.On("GetOverdue", mock.Anything, mock.Anything, 2).
+			Return([]payment_entity.Payment{}, nil).
+			Run(func(args mock.Arguments) {
+				close(fetched)
+			}).
+			Once()
+
+		expiration := NewExpiration(repository, timeProvider, newConfig(), "UpdateOrderTopic")
+
+		// Act
+		expiration.Start(ctx)
+
+		<-fetched
+
+		err := expiration.Stop(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		repository.AssertExpectations(t)
+	})
+}
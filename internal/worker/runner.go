@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job runs a single iteration of a worker, returning true when there is
+// more work pending and it should run again without waiting for the interval
+type Job func(ctx context.Context) bool
+
+type Runner struct {
+	name     string
+	interval time.Duration
+	job      Job
+
+	cancel    context.CancelFunc
+	waitGroup sync.WaitGroup
+}
+
+func NewRunner(name string, interval time.Duration, job Job) *Runner {
+	return &Runner{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+func (r *Runner) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.waitGroup.Add(1)
+
+	go func() {
+		defer r.waitGroup.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		slog.InfoContext(ctx, "worker started", "worker", r.name, "interval", r.interval)
+
+		for {
+			if more := r.job(ctx); more && ctx.Err() == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				slog.InfoContext(ctx, "worker stopped", "worker", r.name)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		r.waitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
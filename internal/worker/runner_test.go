@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunner(t *testing.T) {
+	t.Run("Should run the job again while there is more work", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		var calls atomic.Int32
+
+		done := make(chan struct{})
+
+		runner := NewRunner("test", time.Hour, func(ctx context.Context) bool {
+			if calls.Add(1) == 3 {
+				close(done)
+				return false
+			}
+			return true
+		})
+
+		// Act
+		runner.Start(ctx)
+
+		<-done
+
+		err := runner.Stop(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("Should run the job on every interval", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+
+		var calls atomic.Int32
+
+		done := make(chan struct{})
+
+		runner := NewRunner("test", time.Millisecond, func(ctx context.Context) bool {
+			if calls.Add(1) == 2 {
+				close(done)
+			}
+			return false
+		})
+
+		// Act
+		runner.Start(ctx)
+
+		<-done
+
+		err := runner.Stop(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, calls.Load(), int32(2))
+	})
+
+	t.Run("Should return error when the context expires before the job finishes", func(t *testing.T) {
+		// Arrange
+		running := make(chan struct{})
+		release := make(chan struct{})
+
+		runner := NewRunner("test", time.Hour, func(ctx context.Context) bool {
+			close(running)
+			<-release
+			return false
+		})
+
+		runner.Start(context.Background())
+
+		<-running
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		err := runner.Stop(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		assert.NoError(t, runner.Stop(context.Background()))
+	})
+
+	t.Run("Should stop a runner that was never started", func(t *testing.T) {
+		// Arrange
+		runner := NewRunner("test", time.Hour, func(ctx context.Context) bool {
+			return false
+		})
+
+		// Act
+		err := runner.Stop(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,8 @@
+package worker
+
+import "context"
+
+type Worker interface {
+	Start(ctx context.Context)
+	Stop(ctx context.Context) error
+}
@@ -3,4 +3,7 @@
 echo "Initializing SQS queues..."
 
 awslocal sqs create-queue \
-    --queue-name OrderPaymentQueue
\ No newline at end of file
+    --queue-name OrderPaymentQueue
+
+awslocal sqs create-queue \
+    --queue-name OrderPaymentDLQ
\ No newline at end of file
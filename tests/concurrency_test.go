@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/payment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/custom_error"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const seededPaymentId = "9dfa1386-2f52-4cca-b9aa-f9bd6887d442"
+
+func TestConcurrentPaymentUpdates(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+
+	conn := startPostgres(t, ctx)
+
+	repo := payment.NewPaymentRepository(conn)
+	historyRepo := history.NewHistoryRepository(conn)
+
+	t.Run("Should accept only one of the updates racing on the same version", func(t *testing.T) {
+		// Arrange
+		const racers = 8
+
+		payments := make([]payment_entity.Payment, racers)
+
+		for i := range payments {
+			p, err := repo.GetByID(ctx, seededPaymentId)
+			assert.NoError(t, err)
+
+			state := payment_entity.Approved
+			if i%2 == 0 {
+				state = payment_entity.Rejected
+			}
+
+			err = p.TransitionTo(state, time.Now(), payment_entity.ChangeOrigin{Source: payment_entity.SourceWebhook})
+			assert.NoError(t, err)
+
+			payments[i] = p
+		}
+
+		start := make(chan struct{})
+		errs := make([]error, racers)
+
+		var wg sync.WaitGroup
+
+		for i := range payments {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				<-start
+
+				errs[i] = repo.Update(ctx, &payments[i])
+			}(i)
+		}
+
+		// Act
+		close(start)
+		wg.Wait()
+
+		// Assert
+		var winner *payment_entity.Payment
+
+		for i, err := range errs {
+			if err == nil {
+				assert.Nil(t, winner, "more than one update was accepted")
+				winner = &payments[i]
+				continue
+			}
+
+			assert.ErrorIs(t, err, custom_error.ErrPaymentConcurrentUpdate)
+		}
+
+		if !assert.NotNil(t, winner) {
+			return
+		}
+
+		stored, err := repo.GetByID(ctx, seededPaymentId)
+		assert.NoError(t, err)
+		assert.Equal(t, winner.State, stored.State)
+		assert.Equal(t, 1, stored.Version)
+		assert.Equal(t, stored.Version, winner.Version)
+
+		changes, err := historyRepo.GetByPaymentID(ctx, seededPaymentId)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, winner.State, changes[0].To)
+	})
+}
+
+func startPostgres(t *testing.T, ctx context.Context) *sql.DB {
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: "postgres:16.0",
+			ExposedPorts: []string{
+				"5432",
+			},
+			Env: map[string]string{
+				"POSTGRES_DB":       "payment_db",
+				"POSTGRES_USER":     "payment",
+				"POSTGRES_PASSWORD": "payment",
+			},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(120 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("failed to terminate postgres container: %v", err)
+		}
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres ip: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatalf("failed to get postgres port: %v", err)
+	}
+
+	connStr := fmt.Sprintf("postgres://payment:payment@%s:%s/payment_db?sslmode=disable", host, port.Port())
+
+	if err := migrateAndSeed(ctx, connStr); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatalf("failed to connect to postgres: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
@@ -11,4 +11,14 @@ Feature: Hooks
     Scenario: Send a payment hook for a failed payment
         Given I have a payment
         When I send a payment hook for a "failed" payment
+        Then the payment state should be "Rejected"
+
+    Scenario: Gateway notifies a successful payment
+        Given I have a payment
+        When the gateway notifies a "successful" payment
+        Then the payment state should be "Approved"
+
+    Scenario: Gateway notifies a failed payment
+        Given I have a payment
+        When the gateway notifies a "failed" payment
         Then the payment state should be "Rejected"
\ No newline at end of file
@@ -3,8 +3,12 @@ package tests
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"database/sql"
 	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"io"
@@ -18,6 +22,9 @@ import (
 	"github.com/cucumber/godog/colors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/database/migration"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/adapter/payment_gateway/fake"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -32,8 +39,20 @@ var opts = godog.Options{
 	Concurrency: 1,
 }
 
+const webhookSecret = "webhook-secret"
+
+// signingKey signs the tokens sent to the api, which trusts its public key
+var signingKey *rsa.PrivateKey
+
 func init() {
 	godog.BindFlags("godog.", flag.CommandLine, &opts)
+
+	var err error
+
+	signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
 }
 
 func TestFeatures(t *testing.T) {
@@ -101,11 +120,6 @@ func iSendAPaymentHookForAPayment(ctx context.Context, paymentType string) (cont
 
 	feat := state.retrieve(ctx)
 
-	token, err := generateToken(uuid.NewString(), time.Minute*10)
-	if err != nil {
-		return ctx, err
-	}
-
 	body := `{
 		"approved": true
 	}`
@@ -122,7 +136,11 @@ func iSendAPaymentHookForAPayment(ctx context.Context, paymentType string) (cont
 		return ctx, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", token)
+	req.Header.Set(payment_gateway.WebhookSignatureHeader, payment_gateway.SignWebhook(webhookSecret, time.Now(), payment_gateway.WebhookRequest{
+		Method: req.Method,
+		Target: req.URL.RequestURI(),
+		Body:   []byte(body),
+	}))
 
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -159,6 +177,65 @@ func iSendAPaymentHookForAPayment(ctx context.Context, paymentType string) (cont
 	return state.enrich(ctx, feat), nil
 }
 
+func theGatewayNotifiesAPayment(ctx context.Context, paymentType string) (context.Context, error) {
+	orderId := "c3fdab1b-3c06-4db2-9edc-4760a2429460"
+	paymentId := "9dfa1386-2f52-4cca-b9aa-f9bd6887d442"
+
+	feat := state.retrieve(ctx)
+
+	token, err := generateToken(uuid.NewString(), time.Minute*10)
+	if err != nil {
+		return ctx, err
+	}
+
+	gateway := fake.NewServer(
+		fake.WithWebhook(fmt.Sprintf("%s/payments/webhook", feat.HostApi), http.Header{}),
+		fake.WithWebhookSecret(webhookSecret),
+	)
+	defer gateway.Close()
+
+	status, err := gateway.Notify(ctx, paymentId, paymentType == "successful")
+	if err != nil {
+		return ctx, err
+	}
+
+	if status != http.StatusCreated {
+		return ctx, fmt.Errorf("gateway notification failed with status: %d", status)
+	}
+
+	route := fmt.Sprintf("%s/payments/order/%s", feat.HostApi, orderId)
+	req, err := http.NewRequest("GET", route, nil)
+	if err != nil {
+		return ctx, err
+	}
+	req.Header.Set("Authorization", token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ctx, err
+	}
+	defer res.Body.Close()
+
+	var payments []map[string]interface{}
+
+	if err := json.NewDecoder(res.Body).Decode(&payments); err != nil {
+		return ctx, err
+	}
+
+	for _, payment := range payments {
+		if payment["payment_id"] == paymentId {
+			stateTitle, ok := payment["state_title"].(string)
+			if !ok {
+				return ctx, fmt.Errorf("State title not found")
+			}
+
+			feat.StateTitle = stateTitle
+		}
+	}
+
+	return state.enrich(ctx, feat), nil
+}
+
 func thePaymentStateShouldBe(ctx context.Context, expectedState string) (context.Context, error) {
 	feat := state.retrieve(ctx)
 
@@ -214,6 +291,7 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 
 	ctx.Step(`^I have a payment$`, iHaveAPayment)
 	ctx.Step(`^I send a payment hook for a "([^"]*)" payment$`, iSendAPaymentHookForAPayment)
+	ctx.Step(`^the gateway notifies a "([^"]*)" payment$`, theGatewayNotifiesAPayment)
 	ctx.Step(`^the payment state should be "([^"]*)"$`, thePaymentStateShouldBe)
 
 	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
@@ -242,9 +320,9 @@ func generateToken(userId string, expire time.Duration) (string, error) {
 		"exp": time.Now().Add(expire).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
 
-	tokenString, err := token.SignedString([]byte("my-secret"))
+	tokenString, err := token.SignedString(signingKey)
 	if err != nil {
 		return "", err
 	}
@@ -252,17 +330,19 @@ func generateToken(userId string, expire time.Duration) (string, error) {
 	return fmt.Sprintf("Bearer %s", tokenString), nil
 }
 
-func createPostgresContainer(ctx context.Context, network *testcontainers.DockerNetwork) (testcontainers.Container, context.Context, error) {
-	dbScript, err := filepath.Abs(filepath.Join(".", "testdata", "init-db.sql"))
+func encodePublicKey() (string, error) {
+	data, err := x509.MarshalPKIXPublicKey(&signingKey.PublicKey)
 	if err != nil {
-		return nil, ctx, err
+		return "", err
 	}
 
-	dbScriptReader, err := os.Open(dbScript)
-	if err != nil {
-		return nil, ctx, err
-	}
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: data,
+	})), nil
+}
 
+func createPostgresContainer(ctx context.Context, network *testcontainers.DockerNetwork) (testcontainers.Container, context.Context, error) {
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
 			Image: "postgres:16.0",
@@ -282,14 +362,7 @@ func createPostgresContainer(ctx context.Context, network *testcontainers.Docker
 					"test",
 				},
 			},
-			Files: []testcontainers.ContainerFile{
-				{
-					Reader:            dbScriptReader,
-					ContainerFilePath: "/docker-entrypoint-initdb.d/init.sql",
-					FileMode:          0644,
-				},
-			},
-			WaitingFor: wait.ForLog("PostgreSQL init process complete; ready for start up").WithStartupTimeout(120 * time.Second),
+			WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(120 * time.Second),
 		},
 		Started: true,
 	})
@@ -310,9 +383,41 @@ func createPostgresContainer(ctx context.Context, network *testcontainers.Docker
 	feat := state.retrieve(ctx)
 	feat.ConnStr = fmt.Sprintf("postgres://payment:payment@%s:%s/payment_db?sslmode=disable", postgresIp, postgresPort.Port())
 
+	if err := migrateAndSeed(ctx, feat.ConnStr); err != nil {
+		return nil, ctx, err
+	}
+
 	return container, state.enrich(ctx, feat), nil
 }
 
+func migrateAndSeed(ctx context.Context, connStr string) error {
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	defer conn.Close()
+
+	migrator, err := migration.NewMigrator(conn)
+	if err != nil {
+		return err
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	seed, err := os.ReadFile(filepath.Join(".", "testdata", "seed.sql"))
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, string(seed)); err != nil {
+		return fmt.Errorf("failed to seed the database: %w", err)
+	}
+
+	return nil
+}
+
 func createLocalstackContainer(ctx context.Context, network *testcontainers.DockerNetwork) (testcontainers.Container, context.Context, error) {
 	snsScript, err := filepath.Abs(filepath.Join(".", "testdata", "init-sns.sh"))
 	if err != nil {
@@ -396,6 +501,11 @@ func createLocalstackContainer(ctx context.Context, network *testcontainers.Dock
 }
 
 func createApiContainer(ctx context.Context, network *testcontainers.DockerNetwork) (testcontainers.Container, context.Context, error) {
+	publicKey, err := encodePublicKey()
+	if err != nil {
+		return nil, ctx, err
+	}
+
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
 			FromDockerfile: testcontainers.FromDockerfile{
@@ -419,6 +529,10 @@ func createApiContainer(ctx context.Context, network *testcontainers.DockerNetwo
 				"AWS_ORDER_PRODUCTION_TOPIC_NAME": "OrderProductionTopic",
 				"AWS_UPDATE_ORDER_TOPIC_NAME":     "UpdateOrderTopic",
 				"AWS_ORDER_PAYMENT_QUEUE_NAME":    "OrderPaymentQueue",
+				"AWS_ORDER_PAYMENT_DLQ_NAME":      "OrderPaymentDLQ",
+				"GATEWAY_BASE_URL":                "http://test:8081",
+				"GATEWAY_WEBHOOK_SECRET":          webhookSecret,
+				"AUTH_PUBLIC_KEYS":                publicKey,
 			},
 			Networks: []string{
 				network.Name,
@@ -447,7 +561,7 @@ func createApiContainer(ctx context.Context, network *testcontainers.DockerNetwo
 
 	port := ports["8080/tcp"][0].HostPort
 
-	res, err := http.Get(fmt.Sprintf("http://localhost:%s/health", port))
+	res, err := http.Get(fmt.Sprintf("http://localhost:%s/health/ready", port))
 	if err != nil {
 		return nil, ctx, err
 	}
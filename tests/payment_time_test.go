@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/outbox_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/entity/payment_entity"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/history"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/outbox"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/repository/payment"
+	"github.com/jfelipearaujo-org/ms-payment-management/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// TestPaymentTimesInServiceZone runs in the zone the service sets on startup, which is
+// behind UTC, so a time compared in another zone than the one stored is caught
+func TestPaymentTimesInServiceZone(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	location, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := time.Local
+	time.Local = location
+
+	t.Cleanup(func() {
+		time.Local = local
+	})
+
+	ctx := context.Background()
+
+	conn := startPostgres(t, ctx)
+
+	repo := payment.NewPaymentRepository(conn)
+
+	t.Run("Should store every time with its zone", func(t *testing.T) {
+		// Arrange
+		// the migrations table is only written by the database clock and never read as an instant
+		query := `
+			SELECT table_name || '.' || column_name
+			FROM information_schema.columns
+			WHERE table_schema = 'public'
+				AND table_name <> 'schema_migrations'
+				AND data_type = 'timestamp without time zone';
+		`
+
+		// Act
+		rows, err := conn.QueryContext(ctx, query)
+		assert.NoError(t, err)
+		defer rows.Close()
+
+		columns := make([]string, 0)
+
+		for rows.Next() {
+			var column string
+			assert.NoError(t, rows.Scan(&column))
+			columns = append(columns, column)
+		}
+
+		// Assert
+		assert.NoError(t, rows.Err())
+		assert.Empty(t, columns)
+	})
+
+	t.Run("Should not return a payment just created as overdue", func(t *testing.T) {
+		// Arrange
+		now := time.Now()
+
+		created := payment_entity.NewPayment(uuid.NewString(), uuid.NewString(), nil, 1, money.New(1000, money.BRL), now)
+
+		err := repo.Create(ctx, &created)
+		assert.NoError(t, err)
+
+		// Act
+		overdue, err := repo.GetOverdue(ctx, now.Add(-time.Hour), 100)
+		assert.NoError(t, err)
+
+		overdueLater, errLater := repo.GetOverdue(ctx, now.Add(time.Second), 100)
+
+		// Assert
+		assert.NoError(t, errLater)
+		assert.NotContains(t, paymentIds(overdue), created.PaymentId)
+		assert.Contains(t, paymentIds(overdueLater), created.PaymentId)
+	})
+
+	t.Run("Should filter the payments created at the bounds of the interval", func(t *testing.T) {
+		// Arrange
+		now := time.Now().Truncate(time.Second)
+		before := now.Add(-time.Second)
+		after := now.Add(time.Second)
+
+		created := payment_entity.NewPayment(uuid.NewString(), uuid.NewString(), nil, 1, money.New(1000, money.BRL), now)
+
+		err := repo.Create(ctx, &created)
+		assert.NoError(t, err)
+
+		search := func(from *time.Time, to *time.Time) []string {
+			payments, err := repo.Search(ctx, payment_entity.SearchFilter{
+				CreatedFrom: from,
+				CreatedTo:   to,
+				Limit:       100,
+			})
+			assert.NoError(t, err)
+
+			return paymentIds(payments)
+		}
+
+		// Act
+		atBounds := search(&now, &now)
+		fromAfter := search(&after, nil)
+		toBefore := search(nil, &before)
+
+		// Assert
+		assert.Contains(t, atBounds, created.PaymentId)
+		assert.NotContains(t, fromAfter, created.PaymentId)
+		assert.NotContains(t, toBefore, created.PaymentId)
+	})
+
+	t.Run("Should claim a failed outbox message only once its backoff is over", func(t *testing.T) {
+		// Arrange
+		outboxRepo := outbox.NewOutboxRepository(conn)
+
+		now := time.Now()
+
+		message, err := outbox_entity.NewMessage("topic", map[string]string{"order_id": uuid.NewString()}, now)
+		assert.NoError(t, err)
+
+		err = outboxRepo.Create(ctx, message)
+		assert.NoError(t, err)
+
+		claimed, err := outboxRepo.ClaimPending(ctx, now, now.Add(30*time.Second), 100)
+		assert.NoError(t, err)
+		assert.Contains(t, messageIds(claimed), message.Id)
+
+		message.MarkAsFailed(assert.AnError, now.Add(time.Minute))
+
+		err = outboxRepo.MarkAsFailed(ctx, &message)
+		assert.NoError(t, err)
+
+		// Act
+		beforeBackoff, err := outboxRepo.ClaimPending(ctx, now.Add(59*time.Second), now.Add(90*time.Second), 100)
+		assert.NoError(t, err)
+
+		afterBackoff, errAfter := outboxRepo.ClaimPending(ctx, now.Add(61*time.Second), now.Add(90*time.Second), 100)
+
+		// Assert
+		assert.NoError(t, errAfter)
+		assert.NotContains(t, messageIds(beforeBackoff), message.Id)
+		assert.Contains(t, messageIds(afterBackoff), message.Id)
+	})
+
+	t.Run("Should read the state history at the instants it was written", func(t *testing.T) {
+		// Arrange
+		historyRepo := history.NewHistoryRepository(conn)
+
+		now := time.Now().Truncate(time.Microsecond)
+
+		change := payment_entity.NewStateChange(uuid.NewString(), payment_entity.WaitingForApproval, payment_entity.Approved, payment_entity.ChangeOrigin{
+			Source: payment_entity.SourceWebhook,
+		}, now)
+
+		tx, err := conn.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+
+		err = history.Insert(ctx, tx, change)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+
+		// Act
+		changes, err := historyRepo.GetByPaymentID(ctx, change.PaymentId)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.True(t, now.Equal(changes[0].CreatedAt), "written %s, read %s", now, changes[0].CreatedAt)
+	})
+}
+
+func paymentIds(payments []payment_entity.Payment) []string {
+	ids := make([]string, len(payments))
+
+	for i, payment := range payments {
+		ids[i] = payment.PaymentId
+	}
+
+	return ids
+}
+
+func messageIds(messages []outbox_entity.Message) []string {
+	ids := make([]string, len(messages))
+
+	for i, message := range messages {
+		ids[i] = message.Id
+	}
+
+	return ids
+}